@@ -0,0 +1,91 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genAccessor renders a variant of the transform reading and writing elements
+// through the Accessor interface rather than indexing slices, for callers
+// with storage that isn't a slice.
+func (p Program) genAccessor(f *jen.File, name string) error {
+	if p.Float() {
+		return fmt.Errorf("%s: accessors require a complex schedule", name)
+	}
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: accessors require a unit-stride schedule", name)
+	}
+	if p.Options.Compensated {
+		return fmt.Errorf("%s: accessors require an uncompensated transform", name)
+	}
+
+	accessor := name + "Accessor"
+
+	// Elements are set one at a time, and the accessors may be backed by
+	// anything, so they're assumed not to alias.
+	p.inPlace = false
+	p.accessor = true
+	p.Options.Lanes = 0
+	p.Options.NoAlias = false
+
+	extraParams, _ := p.passThrough()
+	params := append([]jen.Code{jen.List(jen.Id("xi"), jen.Id("xo")).Id("Accessor")}, extraParams...)
+
+	f.Line()
+	doc := fmt.Sprintf("%s computes %s reading xi and writing xo through their\nGet and Set methods.", accessor, name)
+	return p.genFunc(f, accessor, []string{doc}, params)
+}
+
+// accessElement returns an indexed identifier as a call to its slice's Get
+// method.
+func accessElement(id string) string {
+	slice, k, ok := splitIndex(id)
+	if !ok {
+		return id
+	}
+	return fmt.Sprintf("%s.Get(%d)", slice, k)
+}
+
+// genAccessed renders a statement reading and writing elements through
+// accessors.
+func genAccessed(expr Expr) *jen.Statement {
+	if expr.Op == ":=" && len(expr.Sub) == 2 {
+		if slice, k, ok := splitIndex(expr.Sub[0].Ident); ok {
+			return jen.Id(slice).Dot("Set").Call(jen.Lit(k), expr.Sub[1].MapIdents(accessElement).Gen())
+		}
+	}
+	return expr.MapIdents(accessElement).Gen()
+}
+
+// accessorSupport renders the interface accessor variants are computed
+// through, and its implementation by slices.
+func accessorSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("Accessor is implemented by storage of complex elements that accessor\nvariants of transforms read and write.")
+	f.Type().Id("Accessor").Interface(
+		jen.Comment("Get returns element i."),
+		jen.Id("Get").Params(jen.Id("i").Int()).Complex128(),
+		jen.Comment("Set sets element i to x."),
+		jen.Id("Set").Params(jen.Id("i").Int(), jen.Id("x").Complex128()),
+	)
+	f.Line()
+
+	f.Comment("SliceAccessor is an Accessor for a slice.")
+	f.Type().Id("SliceAccessor").Index().Complex128()
+	f.Line()
+
+	f.Comment("Get returns element i of the slice.")
+	f.Func().Params(jen.Id("s").Id("SliceAccessor")).Id("Get").Params(jen.Id("i").Int()).Complex128().Block(
+		jen.Return(jen.Id("s").Index(jen.Id("i"))),
+	)
+	f.Line()
+
+	f.Comment("Set sets element i of the slice to x.")
+	f.Func().Params(jen.Id("s").Id("SliceAccessor")).Id("Set").Params(jen.Id("i").Int(), jen.Id("x").Complex128()).Block(
+		jen.Id("s").Index(jen.Id("i")).Op("=").Id("x"),
+	)
+
+	return f
+}
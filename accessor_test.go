@@ -0,0 +1,47 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestAccessor(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go":  generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Accessor: true}}),
+		"accessor.go": accessorSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestAccessor(t *testing.T) {
+	xi := randCmplx(8)
+	want := make([]complex128, 8)
+	DftCmplx8(xi, want)
+
+	got := make(SliceAccessor, 8)
+	DftCmplx8Accessor(SliceAccessor(xi), got)
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Fatalf("output differs at %d: %v != %v", idx, got[idx], want[idx])
+		}
+	}
+}
+
+func BenchmarkSlice(b *testing.B) {
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	for i := 0; i < b.N; i++ {
+		DftCmplx8(xi, xo)
+	}
+}
+
+func BenchmarkAccessor(b *testing.B) {
+	var xi, xo Accessor = SliceAccessor(randCmplx(8)), make(SliceAccessor, 8)
+	for i := 0; i < b.N; i++ {
+		DftCmplx8Accessor(xi, xo)
+	}
+}
+`, "-bench", ".", "-benchtime", "1x")
+}
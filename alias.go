@@ -0,0 +1,66 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genAssertDisjoint renders statements asserting the input and output slices
+// of an out-of-place transform don't overlap. The assertions are no-ops
+// unless the package is built with the debug tag.
+func genAssertDisjoint(g *jen.Group, complexArgs bool) {
+	if complexArgs {
+		g.Id("assertDisjointCmplx").Call(jen.Id("xi"), jen.Id("xo"))
+	} else {
+		g.Id("assertDisjointFloat").Call(jen.Id("ri"), jen.Id("ro"))
+		g.Id("assertDisjointFloat").Call(jen.Id("ii"), jen.Id("io"))
+	}
+	g.Line()
+}
+
+// aliasSupport renders the release and debug implementations of the slice
+// overlap assertions used by out-of-place transforms.
+func aliasSupport(path string) (release, debug *jen.File) {
+	release = jen.NewFilePathName(path, path)
+	release.HeaderComment("//go:build !debug\n// +build !debug")
+
+	debug = jen.NewFilePathName(path, path)
+	debug.HeaderComment("//go:build debug\n// +build debug")
+
+	for _, t := range []struct {
+		suffix string
+		typ    jen.Code
+	}{
+		{"Cmplx", jen.Complex128()},
+		{"Float", jen.Float64()},
+	} {
+		name := "assertDisjoint" + t.suffix
+		params := jen.List(jen.Id("a"), jen.Id("b")).Index().Add(t.typ)
+
+		// Release builds compile the assertion to nothing.
+		release.Func().Id(name).Params(params).Block()
+		release.Line()
+
+		// Debug builds compare the address ranges spanned by each slice.
+		debug.Comment(fmt.Sprintf("%s panics if a and b share any elements.", name))
+		debug.Func().Id(name).Params(params).Block(
+			jen.If(jen.Len(jen.Id("a")).Op("==").Lit(0).Op("||").Len(jen.Id("b")).Op("==").Lit(0)).Block(
+				jen.Return(),
+			),
+			jen.Line(),
+			jen.Id("size").Op(":=").Qual("unsafe", "Sizeof").Call(jen.Id("a").Index(jen.Lit(0))),
+			jen.Id("aStart").Op(":=").Uintptr().Call(jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id("a").Index(jen.Lit(0)))),
+			jen.Id("bStart").Op(":=").Uintptr().Call(jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id("b").Index(jen.Lit(0)))),
+			jen.Id("aEnd").Op(":=").Id("aStart").Op("+").Uintptr().Call(jen.Len(jen.Id("a"))).Op("*").Id("size"),
+			jen.Id("bEnd").Op(":=").Id("bStart").Op("+").Uintptr().Call(jen.Len(jen.Id("b"))).Op("*").Id("size"),
+			jen.Line(),
+			jen.If(jen.Id("aStart").Op("<").Id("bEnd").Op("&&").Id("bStart").Op("<").Id("aEnd")).Block(
+				jen.Panic(jen.Lit("dft: input and output slices of an out-of-place transform overlap")),
+			),
+		)
+		debug.Line()
+	}
+
+	return
+}
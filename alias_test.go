@@ -0,0 +1,59 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestNoAlias(t *testing.T) {
+	dir := t.TempDir()
+	dft := Dft{
+		Prefix:  copyTestdata(t, dir, "cmplx_3"),
+		Func:    "DftCmplx3",
+		Options: Options{NoAlias: true},
+	}
+
+	f := mustGenerate(t, dft)
+	if src := f.GoString(); !strings.Contains(src, "// DftCmplx3 is out-of-place") {
+		t.Errorf("missing non-aliasing contract in doc comment:\n%s", src)
+	}
+
+	release, debug := aliasSupport("dft")
+	files := map[string]*jen.File{
+		"cmplx_3.go":     f,
+		"alias.go":       release,
+		"alias_debug.go": debug,
+	}
+
+	test := `package dft
+
+import "testing"
+
+func overlapPanics(xi, xo []complex128) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	DftCmplx3(xi, xo)
+	return
+}
+
+func TestNoAlias(t *testing.T) {
+	buf := make([]complex128, 6)
+	if overlapPanics(buf[:3], buf[3:]) {
+		t.Fatal("disjoint slices reported as overlapping")
+	}
+	if overlapPanics(buf[:3], buf[2:5]) != debug {
+		t.Fatalf("overlap assertion: expected panic=%v", debug)
+	}
+}
+`
+
+	t.Run("Release", func(t *testing.T) {
+		goTest(t, files, test+"\nconst debug = false\n")
+	})
+	t.Run("Debug", func(t *testing.T) {
+		goTest(t, files, test+"\nconst debug = true\n", "-tags", "debug")
+	})
+}
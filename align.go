@@ -0,0 +1,156 @@
+package genfft
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// cacheLine is the size in bytes of the cache line alignment benchmarks
+// sweep offsets across.
+const cacheLine = 64
+
+// alignSupport renders, for each package directory, a benchmark running every
+// transform over buffers starting at a range of offsets from a cache line.
+// Transforms taking arguments beyond their slices, returning errors or in
+// single precision are skipped.
+func alignSupport(dfts []Dft) map[string]*jen.File {
+	benchmarks := map[string][]jen.Code{}
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err != nil {
+			log.Warnf("skipping alignment benchmark of %s: %+v\n", dft.Func, err)
+			continue
+		}
+		if _, args := prog.passThrough(); len(args) > 0 || dft.Strided || dft.Generic || dft.ReturnErrors || dft.Precision == "float32" {
+			continue
+		}
+
+		helper := "benchmarkCmplxAlignment"
+		if prog.Float() {
+			helper = "benchmarkFloatAlignment"
+		}
+
+		dir := filepath.Dir(dft.Prefix)
+		benchmarks[dir] = append(benchmarks[dir], jen.Id("b").Dot("Run").Call(
+			jen.Lit(dft.Func),
+			jen.Func().Params(jen.Id("b").Op("*").Qual("testing", "B")).Block(
+				jen.Id(helper).Call(jen.Id("b"), jen.Lit(prog.TransformLength()), jen.Id(dft.Func)),
+			),
+		))
+	}
+
+	files := map[string]*jen.File{}
+	for dir, runs := range benchmarks {
+		f := jen.NewFilePathName("dft", "dft")
+
+		for _, t := range []struct {
+			name   string
+			elem   func() *jen.Statement
+			size   int
+			slices []string
+		}{
+			{"benchmarkCmplxAlignment", jen.Complex128, 16, []string{"xi", "xo"}},
+			{"benchmarkFloatAlignment", jen.Float64, 8, []string{"ri", "ii", "ro", "io"}},
+		} {
+			params := make([]jen.Code, len(t.slices))
+			args := make([]jen.Code, len(t.slices))
+			for idx, slice := range t.slices {
+				params[idx] = jen.Id(slice)
+				args[idx] = jen.Id("bufs").Index(jen.Lit(idx))
+			}
+
+			f.Comment(fmt.Sprintf("%s benchmarks fn with its slices starting at each\nelement offset within a cache line.", t.name))
+			f.Func().Id(t.name).Params(
+				jen.Id("b").Op("*").Qual("testing", "B"),
+				jen.Id("n").Int(),
+				jen.Id("fn").Func().Params(jen.List(params...).Index().Add(t.elem())),
+			).Block(
+				jen.For(
+					jen.Id("offset").Op(":=").Lit(0),
+					jen.Id("offset").Op("<").Lit(cacheLine/t.size),
+					jen.Id("offset").Op("++"),
+				).Block(
+					// Cut each slice from its own buffer at the offset.
+					jen.Id("bufs").Op(":=").Make(jen.Index().Index().Add(t.elem()), jen.Lit(len(t.slices))),
+					jen.For(jen.Id("idx").Op(":=").Range().Id("bufs")).Block(
+						jen.Id("bufs").Index(jen.Id("idx")).Op("=").Make(jen.Index().Add(t.elem()), jen.Id("n").Op("+").Id("offset")).Index(jen.Id("offset").Op(":")),
+					),
+					jen.Line(),
+					jen.Id("b").Dot("Run").Call(
+						jen.Qual("fmt", "Sprintf").Call(jen.Lit("Offset=%dB"), jen.Id("offset").Op("*").Lit(t.size)),
+						jen.Func().Params(jen.Id("b").Op("*").Qual("testing", "B")).Block(
+							jen.Id("b").Dot("SetBytes").Call(jen.Int64().Call(jen.Id("n").Op("*").Lit(t.size))),
+							jen.Id("b").Dot("ReportAllocs").Call(),
+							jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("b").Dot("N"), jen.Id("i").Op("++")).Block(
+								jen.Id("fn").Call(args...),
+							),
+						),
+					),
+				),
+			)
+			f.Line()
+		}
+
+		f.Func().Id("BenchmarkAlignment").Params(jen.Id("b").Op("*").Qual("testing", "B")).Block(runs...)
+
+		files[filepath.Join(dir, "align_test.go")] = f
+	}
+
+	return files
+}
+
+// genAssertAligned renders statements panicking unless the first element of
+// every slice is aligned to align bytes, so misaligned buffers fail fast
+// rather than in code relying on aligned loads.
+func (p Program) genAssertAligned(g *jen.Group, name string) error {
+	align := p.Options.Align
+	if align <= 0 || align&(align-1) != 0 {
+		return fmt.Errorf("%s: alignment must be a power of two, got %d", name, align)
+	}
+
+	helper, slices := "assertAlignedCmplx", []string{"xi", "xo"}
+	if p.Float() {
+		helper, slices = "assertAlignedFloat", []string{"ri", "ii", "ro", "io"}
+	}
+	for _, slice := range slices {
+		g.Id(helper).Call(jen.Lit(name), jen.Lit(slice), jen.Id(slice), jen.Lit(align))
+	}
+	g.Line()
+
+	return nil
+}
+
+// alignedSupport renders the alignment assertions used by transforms with an
+// alignment requirement.
+func alignedSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	for _, t := range []struct {
+		suffix string
+		typ    jen.Code
+	}{
+		{"Cmplx", jen.Complex128()},
+		{"Float", jen.Float64()},
+	} {
+		name := "assertAligned" + t.suffix
+		f.Comment(fmt.Sprintf("%s panics unless the first element of x, the argument\nslice of fn, is aligned to align bytes. Empty slices are never accessed.", name))
+		f.Func().Id(name).Params(
+			jen.List(jen.Id("fn"), jen.Id("slice")).String(),
+			jen.Id("x").Index().Add(t.typ),
+			jen.Id("align").Uintptr(),
+		).Block(
+			jen.If(jen.Len(jen.Id("x")).Op("==").Lit(0)).Block(
+				jen.Return(),
+			),
+			jen.If(jen.Uintptr().Call(jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id("x").Index(jen.Lit(0)))).Op("%").Id("align").Op("!=").Lit(0)).Block(
+				jen.Panic(jen.Qual("fmt", "Sprintf").Call(jen.Lit("dft: %s: %s is not %d-byte aligned"), jen.Id("fn"), jen.Id("slice"), jen.Id("align"))),
+			),
+		)
+		f.Line()
+	}
+
+	return f
+}
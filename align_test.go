@@ -0,0 +1,108 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestAlignmentBenchmark(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx8"},
+		{Func: "DftFloat8"},
+		{Func: "DftCmplx8Sign", Options: Options{RuntimeSign: true}},
+		{Func: "DftCmplx8Strided", Options: Options{Strided: true}},
+		{Func: "DftCmplx8F32", Options: Options{Precision: "float32"}},
+	} {
+		alst, cout := naiveSchedule(8, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+		dfts = append(dfts, dft)
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	support := alignSupport(dfts)
+	if len(support) != 1 {
+		t.Fatalf("expected one benchmark file, got %d", len(support))
+	}
+	for _, f := range support {
+		files["align_test.go"] = f
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestAlignment(t *testing.T) {
+	runs := 0
+	result := testing.Benchmark(func(b *testing.B) {
+		runs++
+		BenchmarkAlignment(b)
+	})
+	if runs == 0 || result.N == 0 {
+		t.Fatal("alignment benchmark didn't run")
+	}
+}
+`, "-bench", "Alignment", "-benchtime", "10x")
+}
+
+func TestAlign(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_4.go": generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: Options{Align: 32}}),
+		"float_4.go": generateNaive(t, 4, Dft{Func: "DftFloat4", Options: Options{Align: 32}}),
+		"aligned.go": alignedSupport("dft"),
+		"view.go":    viewSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// aligned32 returns n elements of a buffer starting on a 32-byte boundary.
+func aligned32(n int) []complex128 {
+	buf := make([]complex128, n+1)
+	if uintptr(unsafe.Pointer(&buf[0]))%32 != 0 {
+		buf = buf[1:]
+	}
+	return buf[:n]
+}
+
+func alignPanic(fn func()) (msg interface{}) {
+	defer func() {
+		msg = recover()
+	}()
+	fn()
+	return nil
+}
+
+func TestAlign(t *testing.T) {
+	xi, xo := aligned32(5), aligned32(4)
+	if msg := alignPanic(func() { DftCmplx4(xi[:4], xo) }); msg != nil {
+		t.Fatalf("aligned slices panicked: %v", msg)
+	}
+
+	// Complex elements are 16 bytes, so the second is misaligned.
+	want := "dft: DftCmplx4: xi is not 32-byte aligned"
+	if msg := alignPanic(func() { DftCmplx4(xi[1:], xo) }); msg != want {
+		t.Fatalf("misaligned input: got %v, want %q", msg, want)
+	}
+
+	ri, ii := FloatView(aligned32(2)), FloatView(aligned32(2))
+	ro, io := FloatView(aligned32(3)), FloatView(aligned32(2))
+	if msg := alignPanic(func() { DftFloat4(ri, ii, ro[:4], io) }); msg != nil {
+		t.Fatalf("aligned float slices panicked: %v", msg)
+	}
+
+	want = "dft: DftFloat4: ro is not 32-byte aligned"
+	if msg := alignPanic(func() { DftFloat4(ri, ii, ro[2:], io) }); msg != want {
+		t.Fatalf("misaligned output: got %v, want %q", msg, want)
+	}
+}
+`)
+}
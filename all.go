@@ -0,0 +1,86 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// constPool collects the constants of transforms sharing a constant block.
+type constPool struct {
+	constants []Constant
+
+	// defined is the index of each constant in constants, and definedBy the
+	// transform defining it first.
+	defined   map[string]int
+	definedBy map[string]string
+}
+
+// pooled reports whether a constant may be shared by the transforms of a
+// file. The imaginary constant depends on the transform's direction and
+// precision, and typed constants on its precision, so they're kept local to
+// each function.
+func (c Constant) pooled() bool {
+	return c.Name != "I" && !strings.Contains(c.Value, "(")
+}
+
+// add adds the pooled constants of the transform name to the pool, returning
+// an error if any is already defined with a different value.
+func (pool *constPool) add(name string, constants []Constant) error {
+	for _, c := range constants {
+		if !c.pooled() {
+			continue
+		}
+
+		idx, ok := pool.defined[c.Name]
+		if !ok {
+			pool.defined[c.Name] = len(pool.constants)
+			pool.definedBy[c.Name] = name
+			pool.constants = append(pool.constants, c)
+			continue
+		}
+
+		if prev := pool.constants[idx]; prev.Value != c.Value {
+			return fmt.Errorf("%s: constant %s = %s conflicts with %s = %s defined by %s", name, c.Name, c.Value, c.Name, prev.Value, pool.definedBy[c.Name])
+		}
+	}
+
+	return nil
+}
+
+// RenderAll renders every transform in a single file of package path, with
+// one constant block shared by all of them. It returns an error if a
+// transform can't be rendered, needs a file of its own, or defines a
+// constant with a different value than another transform.
+func RenderAll(path string, dfts []Dft) (*jen.File, error) {
+	pool := &constPool{defined: map[string]int{}, definedBy: map[string]string{}}
+
+	body := jen.NewFilePathName(path, path)
+	for _, dft := range dfts {
+		if dft.InPlaceTag || dft.GoGenerate {
+			return nil, fmt.Errorf("%s: inPlaceTag and goGenerate require a file of their own", dft.Func)
+		}
+
+		prog, err := dft.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dft.Prefix, err)
+		}
+
+		prog.pool = pool
+		if err := prog.renderTo(body, dft.Func); err != nil {
+			return nil, err
+		}
+		body.Line()
+	}
+
+	// The constant block is only known once every transform is rendered.
+	f := jen.NewFilePathName(path, path)
+	if len(pool.constants) > 0 {
+		f.Add(ConstBlock(pool.constants))
+		f.Line()
+	}
+	f.Add(body)
+
+	return f, nil
+}
@@ -0,0 +1,136 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRenderAll(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	for _, fn := range []string{"DftCmplx4", "DftCmplx8", "DftFloat8"} {
+		n := int(fn[len(fn)-1] - '0')
+		alst, cout := naiveSchedule(n, fn[3] == 'F')
+		dfts = append(dfts, Dft{Prefix: writeSchedule(t, dir, fn, alst, cout), Func: fn})
+	}
+
+	f, err := RenderAll("dft", dfts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src := f.GoString(); strings.Count(src, "KP707106781 =") != 1 || strings.Count(src, "\nconst (") != 1 {
+		t.Fatalf("expected one package constant block defining KP707106781 once:\n%s", src)
+	}
+
+	goTest(t, map[string]*jen.File{"dft_all.go": f}, `package dft
+
+import "testing"
+
+func TestRenderAll(t *testing.T) {
+	for n, dft := range map[int]func(xi, xo []complex128){
+		4: DftCmplx4,
+		8: DftCmplx8,
+	} {
+		xi := randCmplx(n)
+		xo := make([]complex128, n)
+		dft(xi, xo)
+
+		naiveDFT(xi, -1.0)
+		if err := dftError(xi, xo); err > 1e-12 {
+			t.Errorf("N=%d: error %g", n, err)
+		}
+	}
+
+	xi := randCmplx(8)
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	DftFloat8(ri, ii, ri, ii)
+
+	naiveDFT(xi, -1.0)
+	for idx := range xi {
+		if err := dftError([]complex128{complex(ri[idx], ii[idx])}, xi[idx:idx+1]); err > 1e-12 {
+			t.Errorf("float: error %g at %d", err, idx)
+		}
+	}
+}
+`)
+
+	// The same constant with another value can't be shared.
+	conflicting := append([]Dft{}, dfts...)
+	conflicting[1].Options.Constants = map[string]string{"KP707106781": "+0.7071"}
+	if _, err := RenderAll("dft", conflicting); err == nil || !strings.Contains(err.Error(), "KP707106781") {
+		t.Errorf("expected an error for conflicting constants, got %v", err)
+	}
+
+	tagged := append([]Dft{}, dfts...)
+	tagged[0].Options.InPlaceTag = true
+	if _, err := RenderAll("dft", tagged); err == nil {
+		t.Error("expected an error for a transform needing a file of its own")
+	}
+}
+
+func TestRenderAllMixed(t *testing.T) {
+	dir := t.TempDir()
+
+	// Transforms of both directions and precisions, whose imaginary
+	// constants differ, share a file.
+	var dfts []Dft
+	for _, dft := range []Dft{
+		{Func: "DftCmplx8"},
+		{Func: "DftCmplxInv8", Options: Options{Inverse: true}},
+		{Func: "DftCmplx8F32", Options: Options{Precision: "float32"}},
+	} {
+		alst, cout := naiveSchedule(8, false)
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+		dfts = append(dfts, dft)
+	}
+
+	f, err := RenderAll("dft", dfts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if src := f.GoString(); strings.Count(src, "KP707106781 =") != 1 {
+		t.Fatalf("expected KP707106781 shared by the transforms:\n%s", src)
+	}
+
+	goTest(t, map[string]*jen.File{"dft_all.go": f}, `package dft
+
+import "testing"
+
+func TestRenderAllMixed(t *testing.T) {
+	xi := randCmplx(8)
+	want := append([]complex128{}, xi...)
+
+	// The inverse undoes the forward transform, up to a factor of n.
+	xo := make([]complex128, 8)
+	DftCmplx8(xi, xo)
+	DftCmplxInv8(xo, xo)
+	for idx := range xo {
+		xo[idx] /= 8
+	}
+	if err := dftError(xo, want); err > 1e-12 {
+		t.Errorf("inverse: error %g", err)
+	}
+
+	single := make([]complex64, 8)
+	for idx, x := range xi {
+		single[idx] = complex64(x)
+	}
+	DftCmplx8F32(single, single)
+
+	naiveDFT(want, -1.0)
+	got := make([]complex128, 8)
+	for idx, x := range single {
+		got[idx] = complex128(x)
+	}
+	if err := dftError(got, want); err > 1e-5 {
+		t.Errorf("float32: error %g", err)
+	}
+}
+`)
+}
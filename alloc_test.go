@@ -0,0 +1,72 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestZeroAllocs(t *testing.T) {
+	// Every wrapper keeping its scratch space on the stack.
+	wrappers := Options{Into: true, Residual: true, PSD: true, Magnitude: true, Pad: true, Ring: true}
+	cmplxWrappers := wrappers
+	cmplxWrappers.ConvStep = true
+
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: cmplxWrappers}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: wrappers}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestZeroAllocs(t *testing.T) {
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	ri, ii, ro, io := make([]float64, 8), make([]float64, 8), make([]float64, 8), make([]float64, 8)
+	in, out := make([]float64, 8), make([]float64, 5)
+	in32, out32 := make([]float32, 8), make([]float32, 5)
+
+	for _, c := range []struct {
+		name string
+		f    func()
+	}{
+		{"DftCmplx8", func() { DftCmplx8(xi, xo) }},
+		{"DftCmplx8Into", func() { DftCmplx8Into(xi, xo) }},
+		{"DftCmplx8Residual", func() { DftCmplx8Residual(xi, xo) }},
+		{"DftCmplx8PSD", func() { DftCmplx8PSD(in, out) }},
+		{"DftCmplx8Magnitude", func() { DftCmplx8Magnitude(in32, out32) }},
+		{"DftCmplx8ConvStep", func() { DftCmplx8ConvStep(xi, xi, xo) }},
+		{"DftCmplx8Pad", func() { DftCmplx8Pad(xi[:5]) }},
+		{"DftCmplx8Ring", func() { DftCmplx8Ring(xi, xo, 3) }},
+		{"DftFloat8", func() { DftFloat8(ri, ii, ro, io) }},
+		{"DftFloat8Into", func() { DftFloat8Into(ri, ii, ro, io) }},
+		{"DftFloat8Residual", func() { DftFloat8Residual(ri, ii, ro, io) }},
+		{"DftFloat8PSD", func() { DftFloat8PSD(in, out) }},
+		{"DftFloat8Magnitude", func() { DftFloat8Magnitude(in32, out32) }},
+		{"DftFloat8Pad", func() { DftFloat8Pad(ri[:5], ii[:5]) }},
+		{"DftFloat8Ring", func() { DftFloat8Ring(ri, ii, ro, io, 3) }},
+	} {
+		if allocs := testing.AllocsPerRun(100, c.f); allocs != 0 {
+			t.Errorf("%s: %v allocations per call", c.name, allocs)
+		}
+	}
+}
+
+func BenchmarkDftCmplx8(b *testing.B) {
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DftCmplx8(xi, xo)
+	}
+}
+
+func BenchmarkDftFloat8(b *testing.B) {
+	ri, ii, ro, io := make([]float64, 8), make([]float64, 8), make([]float64, 8), make([]float64, 8)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DftFloat8(ri, ii, ro, io)
+	}
+}
+`, "-bench", ".", "-benchtime", "1x")
+}
@@ -0,0 +1,74 @@
+package genfft
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// FuncAPI describes a generated function. Inverse is set for functions
+// computing the unscaled inverse transform.
+type FuncAPI struct {
+	Name      string `json:"name"`
+	Size      int    `json:"size"`
+	Kind      string `json:"kind"`
+	Precision string `json:"precision"`
+	Inverse   bool   `json:"inverse,omitempty"`
+	Signature string `json:"signature"`
+}
+
+// API describes the exported functions of a file generated from the program.
+func (p Program) API(f *jen.File) (funcs []FuncAPI, err error) {
+	buf := &bytes.Buffer{}
+	err = f.Render(buf)
+	if err != nil {
+		return nil, fmt.Errorf("f.Render: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := goparser.ParseFile(fset, "", buf, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parser.ParseFile: %w", err)
+	}
+
+	kind := "complex"
+	if p.Float() {
+		kind = "float"
+	}
+
+	precision := "float64"
+	if p.Options.Precision == "float32" {
+		precision = "float32"
+	}
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !fn.Name.IsExported() {
+			continue
+		}
+
+		// Print only the signature.
+		fn.Body = nil
+		sig := &bytes.Buffer{}
+		err = printer.Fprint(sig, fset, fn)
+		if err != nil {
+			return nil, fmt.Errorf("printer.Fprint: %w", err)
+		}
+
+		funcs = append(funcs, FuncAPI{
+			Name:      fn.Name.Name,
+			Size:      p.TransformLength(),
+			Kind:      kind,
+			Precision: precision,
+			Inverse:   p.Options.Inverse,
+			Signature: sig.String(),
+		})
+	}
+
+	return funcs, nil
+}
@@ -0,0 +1,38 @@
+package genfft
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAPI(t *testing.T) {
+	prog := mustParse(t, Dft{Prefix: "testdata/cmplx_3", Options: Options{Into: true}})
+
+	funcs, err := prog.API(mustRender(t, prog, "DftCmplx3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[` +
+		`{"name":"DftCmplx3","size":3,"kind":"complex","precision":"float64","signature":"func DftCmplx3(xi, xo []complex128)"},` +
+		`{"name":"DftCmplx3Into","size":3,"kind":"complex","precision":"float64","signature":"func DftCmplx3Into(xi, out []complex128) []complex128"}` +
+		`]`
+	if string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Inverse transforms are marked as such.
+	prog = mustParse(t, Dft{Prefix: "testdata/cmplx_3", Options: Options{Inverse: true}})
+	funcs, err = prog.API(mustRender(t, prog, "DftCmplxInv3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(funcs) != 1 || !funcs[0].Inverse {
+		t.Fatalf("expected DftCmplxInv3 described as inverse, got %+v", funcs)
+	}
+}
@@ -0,0 +1,91 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// batchProgressInterval is the number of transforms batch wrappers compute
+// between calls to their progress callback.
+const batchProgressInterval = 64
+
+// genBatch renders a wrapper computing consecutive transforms of contiguous
+// blocks of its slices, reporting progress to an optional callback after
+// every batchProgressInterval transforms and after the last. With a context,
+// the wrapper checks for cancellation before each transform and returns the
+// context's error if it's done.
+func (p Program) genBatch(f *jen.File, name string, withContext bool) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: batches require a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	batch := name + "Batch"
+	extraParams, extraArgs := p.passThrough()
+
+	args, argType := p.Args()
+	var params []jen.Code
+	if withContext {
+		batch += "Context"
+		params = append(params, jen.Id("ctx").Qual("context", "Context"))
+	}
+	params = append(params, jen.List(args...).Index().Add(argType))
+	params = append(params, extraParams...)
+	params = append(params, jen.Id("progress").Func().Params(jen.List(jen.Id("done"), jen.Id("total")).Int()))
+
+	// Each transform operates on the next block of every slice.
+	k := jen.Id("k")
+	var call []jen.Code
+	for _, arg := range args {
+		call = append(call, jen.Add(arg).Index(k, k.Clone().Op("+").Lit(n)))
+	}
+	call = append(call, extraArgs...)
+
+	done := jen.Id("done")
+
+	doc := fmt.Sprintf(
+		"%s computes %s of each consecutive block of %d elements. If progress\nisn't nil, it's called with the number of transforms done after every %d and\nafter the last.",
+		batch, name, n, batchProgressInterval,
+	)
+	var results []jen.Code
+	if withContext {
+		doc += " It stops before the next transform once ctx is done, returning\nctx's error."
+		results = append(results, jen.Error())
+	} else if p.Options.ReturnErrors {
+		doc += " It stops at the first transform returning an error, returning\nthe error."
+		results = append(results, jen.Error())
+	}
+
+	f.Line()
+	f.Comment(doc)
+	f.Func().Id(batch).Params(params...).Add(results...).BlockFunc(func(g *jen.Group) {
+		g.Id("total").Op(":=").Len(args[0]).Op("/").Lit(n)
+		g.For(done.Clone().Op(":=").Lit(0), done.Clone().Op("<").Id("total"), done.Clone().Op("++")).BlockFunc(func(g *jen.Group) {
+			if withContext {
+				g.If(jen.Err().Op(":=").Id("ctx").Dot("Err").Call(), jen.Err().Op("!=").Nil()).Block(
+					jen.Return(jen.Err()),
+				)
+			}
+			g.Add(k).Op(":=").Add(done).Op("*").Lit(n)
+			g.Add(p.callTransform(name, nil, call...))
+			g.Line()
+
+			g.If(
+				jen.Id("progress").Op("!=").Nil().Op("&&").Parens(
+					jen.Parens(done.Clone().Op("+").Lit(1)).Op("%").Lit(batchProgressInterval).Op("==").Lit(0).
+						Op("||").Add(done).Op("+").Lit(1).Op("==").Id("total"),
+				),
+			).Block(
+				jen.Id("progress").Call(done.Clone().Op("+").Lit(1), jen.Id("total")),
+			)
+		})
+
+		if withContext || p.Options.ReturnErrors {
+			g.Line()
+			g.Return(jen.Nil())
+		}
+	})
+
+	return nil
+}
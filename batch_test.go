@@ -0,0 +1,107 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestBatch(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_4.go": generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: Options{Batch: true}}),
+		"float_4.go": generateNaive(t, 4, Dft{Func: "DftFloat4", Options: Options{Batch: true, RuntimeSign: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestBatch(t *testing.T) {
+	const total = 200
+	xi := randCmplx(4 * total)
+	xo := make([]complex128, len(xi))
+
+	var calls []int
+	DftCmplx4Batch(xi, xo, func(done, n int) {
+		if n != total {
+			t.Fatalf("total %d, want %d", n, total)
+		}
+		calls = append(calls, done)
+	})
+
+	want := []int{64, 128, 192, 200}
+	if len(calls) != len(want) {
+		t.Fatalf("progress called with %v, want %v", calls, want)
+	}
+	for idx := range want {
+		if calls[idx] != want[idx] {
+			t.Fatalf("progress called with %v, want %v", calls, want)
+		}
+	}
+
+	for k := 0; k < len(xi); k += 4 {
+		block := append([]complex128(nil), xi[k:k+4]...)
+		naiveDFT(block, -1.0)
+		if err := dftError(xo[k:k+4], block); err > 1e-13 {
+			t.Fatalf("block %d: error %g", k/4, err)
+		}
+	}
+
+	// Progress is optional.
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	DftFloat4Batch(ri, ii, ri, ii, 1, nil)
+}
+`)
+}
+
+func TestBatchContext(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_4.go": generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: Options{BatchContext: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatchContext(t *testing.T) {
+	const total = 200
+	xi := randCmplx(4 * total)
+	xo := make([]complex128, len(xi))
+
+	if err := DftCmplx4BatchContext(context.Background(), xi, xo, nil); err != nil {
+		t.Fatalf("uncancelled batch: %v", err)
+	}
+
+	// Cancel mid-batch from the first progress report.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	xo = make([]complex128, len(xi))
+	var calls []int
+	err := DftCmplx4BatchContext(ctx, xi, xo, func(done, total int) {
+		calls = append(calls, done)
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(calls) != 1 || calls[0] != 64 {
+		t.Fatalf("progress called with %v, want [64]", calls)
+	}
+
+	// Transforms after the cancellation weren't computed.
+	for _, x := range xo[4*64:] {
+		if x != 0 {
+			t.Fatal("transform computed after cancellation")
+		}
+	}
+	if xo[4*63] == 0 {
+		t.Fatal("transform before cancellation wasn't computed")
+	}
+}
+`)
+}
@@ -0,0 +1,164 @@
+package genfft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// BenchResult describes the performance of a generated transform.
+type BenchResult struct {
+	Name    string  `json:"name"`
+	Size    int     `json:"size"`
+	NsPerOp float64 `json:"nsPerOp"`
+	GFLOPs  float64 `json:"gflops"`
+}
+
+// benchHarness renders a test benchmarking each transform with
+// testing.Benchmark and writing the results as JSON to reportFilename.
+// Transforms taking arguments beyond their slices, returning errors or in
+// single precision are skipped.
+func benchHarness(dfts []Dft, reportFilename string) *jen.File {
+	f := jen.NewFilePathName("dft", "dft")
+
+	var results []jen.Code
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err != nil {
+			log.Warnf("skipping benchmark of %s: %+v\n", dft.Func, err)
+			continue
+		}
+		if _, args := prog.passThrough(); len(args) > 0 || dft.Strided || dft.Generic || dft.ReturnErrors || dft.Precision == "float32" {
+			continue
+		}
+
+		helper := "benchCmplx"
+		if prog.Float() {
+			helper = "benchFloat"
+		}
+		results = append(results, jen.Id(helper).Call(
+			jen.Lit(dft.Func), jen.Lit(prog.TransformLength()), jen.Lit(prog.Flops()), jen.Id(dft.Func),
+		))
+	}
+
+	f.Type().Id("benchResult").Struct(
+		jen.Id("Name").String().Tag(map[string]string{"json": "name"}),
+		jen.Id("Size").Int().Tag(map[string]string{"json": "size"}),
+		jen.Id("NsPerOp").Float64().Tag(map[string]string{"json": "nsPerOp"}),
+		jen.Id("GFLOPs").Float64().Tag(map[string]string{"json": "gflops"}),
+	)
+	f.Line()
+
+	for _, t := range []struct {
+		name   string
+		elem   func() *jen.Statement
+		slices []string
+	}{
+		{"benchCmplx", jen.Complex128, []string{"xi", "xo"}},
+		{"benchFloat", jen.Float64, []string{"ri", "ii", "ro", "io"}},
+	} {
+		f.Func().Id(t.name).Params(
+			jen.Id("name").String(),
+			jen.List(jen.Id("n"), jen.Id("flops")).Int(),
+			jen.Id("fn").Func().Params(jen.List(ids(t.slices)...).Index().Add(t.elem())),
+		).Id("benchResult").Block(
+			jen.List(ids(t.slices)...).Op(":=").List(makes(t.elem, len(t.slices))...),
+			jen.Id("r").Op(":=").Qual("testing", "Benchmark").Call(jen.Func().Params(jen.Id("b").Op("*").Qual("testing", "B")).Block(
+				jen.For(jen.Id("i").Op(":=").Lit(0), jen.Id("i").Op("<").Id("b").Dot("N"), jen.Id("i").Op("++")).Block(
+					jen.Id("fn").Call(ids(t.slices)...),
+				),
+			)),
+			jen.Id("ns").Op(":=").Float64().Call(jen.Id("r").Dot("T").Dot("Nanoseconds").Call()).Op("/").Float64().Call(jen.Id("r").Dot("N")),
+			jen.Return(jen.Id("benchResult").Values(jen.Id("name"), jen.Id("n"), jen.Id("ns"), jen.Float64().Call(jen.Id("flops")).Op("/").Id("ns"))),
+		)
+		f.Line()
+	}
+
+	f.Func().Id("TestBenchReport").Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+		jen.Id("results").Op(":=").Index().Id("benchResult").Values(results...),
+		jen.List(jen.Id("report"), jen.Err()).Op(":=").Qual("encoding/json", "Marshal").Call(jen.Id("results")),
+		jen.If(jen.Err().Op("!=").Nil()).Block(jen.Id("t").Dot("Fatal").Call(jen.Err())),
+		jen.If(
+			jen.Err().Op(":=").Qual("os", "WriteFile").Call(jen.Lit(reportFilename), jen.Id("report"), jen.Lit(0644)),
+			jen.Err().Op("!=").Nil(),
+		).Block(jen.Id("t").Dot("Fatal").Call(jen.Err())),
+	)
+
+	return f
+}
+
+// Benchmark runs, for each package directory, the benchmarks of its
+// transforms for benchtime each, in a copy of the package so nothing is
+// written alongside it. Results are ordered by size, then name.
+func Benchmark(dfts []Dft, benchtime string) (results []BenchResult, err error) {
+	packages := map[string][]Dft{}
+	for _, dft := range dfts {
+		dir := filepath.Dir(dft.Prefix)
+		packages[dir] = append(packages[dir], dft)
+	}
+
+	for dir, dfts := range packages {
+		tmp, err := os.MkdirTemp("", "genfft-bench")
+		if err != nil {
+			return nil, fmt.Errorf("os.MkdirTemp: %w", err)
+		}
+		defer os.RemoveAll(tmp)
+
+		// Copy the package's sources, leaving its tests behind.
+		sources, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, fmt.Errorf("filepath.Glob: %w", err)
+		}
+		for _, source := range sources {
+			if matched, _ := filepath.Match("*_test.go", filepath.Base(source)); matched {
+				continue
+			}
+			b, err := os.ReadFile(source)
+			if err != nil {
+				return nil, fmt.Errorf("os.ReadFile: %w", err)
+			}
+			if err := os.WriteFile(filepath.Join(tmp, filepath.Base(source)), b, 0644); err != nil {
+				return nil, fmt.Errorf("os.WriteFile: %w", err)
+			}
+		}
+
+		if err := os.WriteFile(filepath.Join(tmp, "go.mod"), []byte("module dft\n\ngo 1.18\n"), 0644); err != nil {
+			return nil, fmt.Errorf("os.WriteFile: %w", err)
+		}
+
+		reportFilename := filepath.Join(tmp, "report.json")
+		if err := benchHarness(dfts, reportFilename).Save(filepath.Join(tmp, "bench_test.go")); err != nil {
+			return nil, fmt.Errorf("f.Save: %w", err)
+		}
+
+		cmd := exec.Command("go", "test", "-count=1", "-run", "^TestBenchReport$", "-benchtime", benchtime, ".")
+		cmd.Dir = tmp
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("%s: go test: %w\n%s", dir, err, out)
+		}
+
+		report, err := os.ReadFile(reportFilename)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile: %w", err)
+		}
+		var dirResults []BenchResult
+		if err := json.Unmarshal(report, &dirResults); err != nil {
+			return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+		results = append(results, dirResults...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Size != results[j].Size {
+			return results[i].Size < results[j].Size
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
@@ -0,0 +1,49 @@
+package genfft
+
+import "testing"
+
+func TestBenchmark(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	for _, tc := range []struct {
+		n   int
+		dft Dft
+	}{
+		{4, Dft{Func: "DftCmplx4"}},
+		{2, Dft{Func: "DftCmplx2"}},
+		{3, Dft{Func: "DftFloat3"}},
+		// Transforms taking a sign can't be benchmarked without one.
+		{5, Dft{Func: "DftCmplx5", Options: Options{RuntimeSign: true}}},
+		// The harness only measures double precision.
+		{6, Dft{Func: "DftCmplx6F32", Options: Options{Precision: "float32"}}},
+	} {
+		alst, cout := naiveSchedule(tc.n, tc.dft.Func[3] == 'F')
+		tc.dft.Prefix = writeSchedule(t, dir, tc.dft.Func, alst, cout)
+		if err := mustGenerate(t, tc.dft).Save(tc.dft.Prefix + ".go"); err != nil {
+			t.Fatal(err)
+		}
+		dfts = append(dfts, tc.dft)
+	}
+
+	results, err := Benchmark(dfts, "10x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name string
+		size int
+	}{{"DftCmplx2", 2}, {"DftFloat3", 3}, {"DftCmplx4", 4}}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(results), len(want), results)
+	}
+	for idx, r := range results {
+		if r.Name != want[idx].name || r.Size != want[idx].size {
+			t.Errorf("result %d is %s of size %d, want %s of size %d", idx, r.Name, r.Size, want[idx].name, want[idx].size)
+		}
+		if r.NsPerOp <= 0 || r.GFLOPs <= 0 {
+			t.Errorf("%s: nonpositive timing %+v", r.Name, r)
+		}
+	}
+}
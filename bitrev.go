@@ -0,0 +1,96 @@
+package genfft
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// MapIdents returns a copy of the expression with every identifier replaced
+// by the result of fn.
+func (e Expr) MapIdents(fn func(string) string) Expr {
+	if e.Ident != "" {
+		return Expr{Ident: fn(e.Ident)}
+	}
+
+	sub := make([]Expr, len(e.Sub))
+	for idx := range e.Sub {
+		sub[idx] = e.Sub[idx].MapIdents(fn)
+	}
+
+	return Expr{Op: e.Op, Sub: sub}
+}
+
+// isOutput reports whether a slice name is one of a transform's outputs.
+func isOutput(name string) bool {
+	return name == "xo" || name == "ro" || name == "io"
+}
+
+// bitReverseOutputs returns the program's statements with output indices
+// replaced by their bit reversal.
+func (p Program) bitReverseOutputs(name string) ([]Expr, error) {
+	n := p.TransformLength()
+	if n&(n-1) != 0 {
+		return nil, fmt.Errorf("%s: bit-reversed output requires a power-of-two size, got %d", name, n)
+	}
+	shift := bits.UintSize - bits.Len(uint(n-1))
+
+	reverse := func(id string) string {
+		slice, k, ok := splitIndex(id)
+		if !ok || !isOutput(slice) {
+			return id
+		}
+		return fmt.Sprintf("%s[%d]", slice, bits.Reverse(uint(k))>>shift)
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(reverse)
+	}
+
+	return statements, nil
+}
+
+// bitReverseSupport renders helpers permuting the output of bit-reversed
+// transforms back into natural order.
+func bitReverseSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	for _, t := range []struct {
+		suffix string
+		typ    jen.Code
+	}{
+		{"Cmplx", jen.Complex128()},
+		{"Float", jen.Float64()},
+	} {
+		name := "BitReverse" + t.suffix
+
+		f.Comment(fmt.Sprintf("%s permutes x, whose length must be a power of two, between", name))
+		f.Comment("natural and bit-reversed order. The permutation is its own inverse.")
+		f.Func().Id(name).Params(jen.Id("x").Index().Add(t.typ)).Block(
+			jen.Id("n").Op(":=").Len(jen.Id("x")),
+			jen.For(
+				jen.List(jen.Id("i"), jen.Id("j")).Op(":=").List(jen.Lit(0), jen.Lit(0)),
+				jen.Id("i").Op("<").Id("n"),
+				jen.Id("i").Op("++"),
+			).Block(
+				jen.If(jen.Id("i").Op("<").Id("j")).Block(
+					jen.List(jen.Id("x").Index(jen.Id("i")), jen.Id("x").Index(jen.Id("j"))).Op("=").
+						List(jen.Id("x").Index(jen.Id("j")), jen.Id("x").Index(jen.Id("i"))),
+				),
+				jen.Line(),
+				jen.Comment("Increment j in bit-reversed order."),
+				jen.Id("bit").Op(":=").Id("n").Op(">>").Lit(1),
+				jen.For(jen.Id("j").Op("&").Id("bit").Op("!=").Lit(0)).Block(
+					jen.Id("j").Op("^=").Id("bit"),
+					jen.Id("bit").Op(">>=").Lit(1),
+				),
+				jen.Id("j").Op("|=").Id("bit"),
+			),
+		)
+		f.Line()
+	}
+
+	return f
+}
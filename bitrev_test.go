@@ -0,0 +1,56 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestBitReverse(t *testing.T) {
+	files := map[string]*jen.File{
+		"bitrev.go":  bitReverseSupport("dft"),
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{BitReverse: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{BitReverse: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestBitReverse(t *testing.T) {
+	xi := randCmplx(8)
+	xo := make([]complex128, 8)
+	DftCmplx8(xi, xo)
+
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+
+	// Outputs are written to bit-reversed positions.
+	reversed := []int{0, 4, 2, 6, 1, 5, 3, 7}
+	for idx, rev := range reversed {
+		if err := dftError(xo[idx:idx+1], naive[rev:rev+1]); err > 1e-13 {
+			t.Errorf("xo[%d] should hold bin %d", idx, rev)
+		}
+	}
+
+	BitReverseCmplx(xo)
+	if err := dftError(xo, naive); err > 1e-13 {
+		t.Errorf("DftCmplx8: error %g after un-permuting", err)
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx := range xi {
+		ri[idx], ii[idx] = real(xi[idx]), imag(xi[idx])
+	}
+	DftFloat8(ri, ii, ri, ii)
+	BitReverseFloat(ri)
+	BitReverseFloat(ii)
+	for idx := range ri {
+		xo[idx] = complex(ri[idx], ii[idx])
+	}
+	if err := dftError(xo, naive); err > 1e-13 {
+		t.Errorf("DftFloat8: error %g after un-permuting", err)
+	}
+}
+`)
+}
@@ -0,0 +1,46 @@
+package genfft
+
+import "sort"
+
+// Canonical returns a copy of the expression with the operands of every sum
+// and product sorted, so expressions differing only in the order of
+// commutative operands evaluate identically.
+func (e Expr) Canonical() Expr {
+	if e.Ident != "" {
+		return e
+	}
+
+	sub := make([]Expr, len(e.Sub))
+	for idx := range e.Sub {
+		sub[idx] = e.Sub[idx].Canonical()
+	}
+
+	if (e.Op == "+" || e.Op == "*") && len(sub) > 1 {
+		sort.SliceStable(sub, func(i, j int) bool {
+			return sub[i].String() < sub[j].String()
+		})
+	}
+
+	return Expr{Pos: e.Pos, Op: e.Op, Sub: sub}
+}
+
+// canonicalOrder returns the program's statements in canonical form, with
+// independent statements scheduled in order of their canonical text.
+func (p Program) canonicalOrder() []Expr {
+	statements := make([]Expr, len(p.Statements))
+	keys := make([]string, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.Canonical()
+		keys[idx] = statements[idx].String()
+	}
+
+	return Reorder(statements, func(ready []int) int {
+		best := 0
+		for pos, idx := range ready {
+			if keys[idx] < keys[ready[best]] {
+				best = pos
+			}
+		}
+		return best
+	})
+}
@@ -0,0 +1,104 @@
+package genfft
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// alstString renders an expression as a schedule s-expression.
+func alstString(e Expr) string {
+	if e.Ident != "" {
+		return e.Ident
+	}
+
+	parts := []string{e.Op}
+	for _, sub := range e.Sub {
+		parts = append(parts, alstString(sub))
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// shuffleOperands returns a copy of e with the operands of every sum and
+// product permuted.
+func shuffleOperands(rng *rand.Rand, e Expr) Expr {
+	if e.Ident != "" {
+		return e
+	}
+
+	sub := make([]Expr, len(e.Sub))
+	for idx := range e.Sub {
+		sub[idx] = shuffleOperands(rng, e.Sub[idx])
+	}
+	if e.Op == "+" || e.Op == "*" {
+		rng.Shuffle(len(sub), func(i, j int) { sub[i], sub[j] = sub[j], sub[i] })
+	}
+
+	return Expr{Op: e.Op, Sub: sub}
+}
+
+func TestCanonical(t *testing.T) {
+	dir := t.TempDir()
+	alst, cout := naiveSchedule(16, false)
+
+	prog := &Program{}
+	if err := parser.ParseString("", alst, prog); err != nil {
+		t.Fatal(err)
+	}
+
+	// Write an equivalent schedule with operands permuted, and the loads
+	// and the independent outputs each in reverse order.
+	rng := rand.New(rand.NewSource(1))
+	var loads, outputs []string
+	for _, expr := range prog.Statements {
+		line := alstString(shuffleOperands(rng, expr))
+		if _, ok := expr.Temporary(); ok {
+			loads = append([]string{line}, loads...)
+		} else {
+			outputs = append([]string{line}, outputs...)
+		}
+	}
+	shuffled := strings.Join(append(loads, outputs...), "\n")
+
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Prefix: writeSchedule(t, dir, "a", alst, cout), Func: "DftCmplx16A"},
+		{Prefix: writeSchedule(t, dir, "b", shuffled, cout), Func: "DftCmplx16B"},
+	} {
+		dft.Canonical = true
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	if a, b := files["DftCmplx16A.go"].GoString(), files["DftCmplx16B.go"].GoString(); strings.Replace(a, "16A", "16B", -1) != b {
+		t.Fatalf("equivalent schedules generated differently:\n%s\n%s", a, b)
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	xi := randCmplx(16)
+	a, b := make([]complex128, 16), make([]complex128, 16)
+	DftCmplx16A(xi, a)
+	DftCmplx16B(xi, b)
+
+	for k := range a {
+		if math.Float64bits(real(a[k])) != math.Float64bits(real(b[k])) ||
+			math.Float64bits(imag(a[k])) != math.Float64bits(imag(b[k])) {
+			t.Fatalf("output %d differs: %v != %v", k, a[k], b[k])
+		}
+	}
+
+	naiveDFT(xi, -1.0)
+	if err := dftError(a, xi); err > 1e-13 {
+		t.Fatalf("error %g", err)
+	}
+}
+`)
+}
@@ -0,0 +1,81 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// CheckTemporaries verifies every temporary is assigned before it's used,
+// returning an error naming the first that isn't and where it's used.
+// Identifiers that are neither temporaries, constants nor indexed arguments
+// are reported as undefined.
+func (p Program) CheckTemporaries() error {
+	defined := map[string]bool{"I": true}
+	for _, c := range p.Constants {
+		defined[c.Name] = true
+	}
+
+	// Find the statement assigning each temporary.
+	assigned := map[string]int{}
+	for idx, expr := range p.Statements {
+		if temp, ok := expr.Temporary(); ok {
+			if _, ok := assigned[temp]; !ok {
+				assigned[temp] = idx
+			}
+		}
+	}
+
+	for idx, expr := range p.Statements {
+		// The left side of the assignment, if any, is written not read.
+		operands := expr.Sub
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			operands = expr.Sub[1:]
+		}
+
+		for _, sub := range operands {
+			var err error
+			sub.walkIdents(func(id string, pos lexer.Position) {
+				if err != nil || defined[id] || strings.HasSuffix(id, "]") {
+					return
+				}
+
+				a, ok := assigned[id]
+				switch {
+				case !ok:
+					err = fmt.Errorf("%s: %s is undefined", position(pos, expr), id)
+				case a >= idx:
+					err = fmt.Errorf("%s: temporary %s used before it's assigned", position(pos, expr), id)
+				}
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkIdents calls fn with each identifier in an expression tree and its
+// position in the schedule.
+func (e Expr) walkIdents(fn func(id string, pos lexer.Position)) {
+	if e.Ident != "" {
+		fn(e.Ident, e.Pos)
+		return
+	}
+
+	for _, sub := range e.Sub {
+		sub.walkIdents(fn)
+	}
+}
+
+// position formats where an identifier appears, falling back on the
+// statement it appears in for expressions that weren't parsed.
+func position(pos lexer.Position, stmt Expr) string {
+	if pos.Line == 0 {
+		return fmt.Sprintf("in %s", stmt.Gen().GoString())
+	}
+	return fmt.Sprintf("line %d, column %d", pos.Line, pos.Column)
+}
@@ -0,0 +1,37 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckTemporaries(t *testing.T) {
+	testCases := []struct {
+		name string
+		alst string
+		want string
+	}{
+		{"Valid", "(:= T1 xi[0])\n(:= T2 (* KP500000000 T1))\n(:= xo[0] (+ T1 T2))\n", ""},
+		{"Forward", "(:= T1 xi[0])\n(:= xo[0] (+ T1 T2))\n(:= T2 xi[1])\n", "line 2, column 17: temporary T2 used before it's assigned"},
+		{"Self", "(:= T1 xi[0])\n(:= T2 (+ T1\n\tT2))\n", "line 3, column 2: temporary T2 used before it's assigned"},
+		{"Undefined", "(:= T1 xi[0])\n(:= xo[0] (* KP866025403 T1))\n", "line 2, column 14: KP866025403 is undefined"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prog := &Program{}
+			if err := parser.ParseString("", tc.alst, prog); err != nil {
+				t.Fatal(err)
+			}
+			prog.Constants = []Constant{{"KP500000000", "+0.5"}}
+
+			err := prog.CheckTemporaries()
+			switch {
+			case tc.want == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.want != "" && (err == nil || !strings.Contains(err.Error(), tc.want)):
+				t.Fatalf("got error %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+// Command genfft generates short, hard-coded DFT's in go from annotated lists
+// created with the FFTW tool genfft, as described by config.json.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/bemasher/genfft"
+	log "github.com/sirupsen/logrus"
+)
+
+func init() {
+	_, f, _, _ := runtime.Caller(0)
+	dir := filepath.Dir(f) + "\\"
+
+	log.SetFormatter(&log.TextFormatter{
+		ForceColors: true,
+		CallerPrettyfier: func(frame *runtime.Frame) (fn, file string) {
+			file = strings.TrimPrefix(filepath.Clean(frame.File), dir)
+			return frame.Function, fmt.Sprintf("%s:%d", file, frame.Line)
+		},
+	})
+	log.SetReportCaller(true)
+	log.SetLevel(log.TraceLevel)
+}
+
+func main() {
+	apiFilename := flag.String("emit-api-json", "", "write a JSON description of the generated functions to this file")
+	testsFilename := flag.String("emit-tests", "", "add the generated functions to the tests in this file, creating it if necessary")
+	maxSize := flag.Int("max-size", 0, "skip transforms longer than this, 0 for no limit")
+	chdir := flag.String("C", "", "change to this directory before reading config.json")
+	benchtime := flag.String("benchtime", "1s", "run each benchmark of the bench command for this long, or Nx times")
+	trials := flag.Int("trials", 16, "evaluate schedules compared by the cmp command on this many random inputs")
+	prefix := flag.String("prefix", "", "generate only the transform with this prefix, without reading config.json, or - to read its schedule from stdin and write it to stdout")
+	coutFilename := flag.String("cout", "", "read the constants for -prefix - from this file")
+	funcName := flag.String("func", "", "name the function generated for -prefix")
+	pkg := flag.String("pkg", "dft", "generate the transform for -prefix in this package")
+	out := flag.String("out", "", "write the transform for -prefix to this directory rather than beside its schedule")
+	inverse := flag.Bool("inverse", false, "generate the inverse of the transform for -prefix, without scaling by 1/N")
+	report := flag.Bool("report", false, "print the adds and multiplies performed by each generated transform")
+	singleFile := flag.Bool("single-file", false, "write the transforms in each directory to one dft_all.go sharing their constants")
+	flag.Parse()
+
+	if *chdir != "" {
+		if err := os.Chdir(*chdir); err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.Chdir: %w", err))
+		}
+	}
+
+	// Print the constants from C output as go.
+	if flag.Arg(0) == "constants" {
+		coutFile, err := os.Open(flag.Arg(1))
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.Open: %w", err))
+		}
+		defer coutFile.Close()
+
+		fmt.Printf("%#v\n", genfft.ConstBlock(genfft.ParseConstants(coutFile)))
+		return
+	}
+
+	// Print the shape of a schedule's expression trees.
+	if flag.Arg(0) == "stats" {
+		statements, err := genfft.ParseStatements(flag.Arg(1))
+		if err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+
+		genfft.ScheduleStats(statements).Fprint(os.Stdout)
+		if safe, clobbered := genfft.InPlaceSafe(statements); safe {
+			fmt.Println("in-place safe: true")
+		} else {
+			fmt.Printf("in-place safe: false, line %d reads an overwritten input\n", statements[clobbered].Pos.Line)
+		}
+		return
+	}
+
+	// Compare the output of two schedules on random input.
+	if flag.Arg(0) == "cmp" {
+		diffs, err := genfft.CompareFiles(flag.Arg(1), flag.Arg(2), *trials)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("genfft.CompareFiles: %w", err))
+		}
+
+		genfft.FprintDiffs(os.Stdout, diffs)
+		return
+	}
+
+	// Check a config without generating anything.
+	if flag.Arg(0) == "validate" {
+		configFilename := "config.json"
+		if flag.NArg() > 1 {
+			configFilename = flag.Arg(1)
+		}
+
+		problems := genfft.Validate(configFilename)
+		for _, err := range problems {
+			log.Errorf("%+v\n", err)
+		}
+		if len(problems) > 0 {
+			log.Fatalf("%s has %d problems\n", configFilename, len(problems))
+		}
+		log.Infof("%s is valid\n", configFilename)
+		return
+	}
+
+	if *coutFilename != "" && *prefix != "-" {
+		log.Fatalf("-cout requires -prefix -\n")
+	}
+	if *report && *prefix == "-" {
+		log.Fatalf("-report can't be combined with -prefix -, which writes to stdout\n")
+	}
+	if *inverse && *prefix == "" {
+		log.Fatalf("-inverse requires -prefix, set inverse in config.json otherwise\n")
+	}
+
+	// Generate a single transform from stdin to stdout, for pipelines.
+	if *prefix == "-" {
+		if err := generateStdin(os.Stdin, os.Stdout, *coutFilename, *funcName, *pkg, genfft.Options{Inverse: *inverse}); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+		return
+	}
+
+	// Generate a single transform described by flags.
+	if *prefix != "" {
+		dft := genfft.Dft{Prefix: *prefix, Func: *funcName, Options: genfft.Options{Inverse: *inverse}}
+		if err := checkFlags(dft, *pkg); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+
+		dir := filepath.Dir(*prefix)
+		if *out != "" {
+			dir = *out
+		}
+		goFilename := filepath.Join(dir, filepath.Base(*prefix)+".go")
+
+		funcs, err := generate(dft, *pkg, goFilename, *testsFilename != "")
+		if err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+
+		if *testsFilename != "" {
+			if err := emitTests(*testsFilename, funcs); err != nil {
+				log.Fatalf("%+v\n", err)
+			}
+		}
+
+		if *report {
+			if err := printReport(os.Stdout, []genfft.Dft{dft}); err != nil {
+				log.Fatalf("%+v\n", err)
+			}
+		}
+		return
+	}
+
+	// Load configurations.
+	dfts, err := genfft.LoadConfig("config.json")
+	if err != nil {
+		log.Fatalf("%+v\n", err)
+	}
+
+	// Skip transforms too large to compile quickly.
+	if *maxSize > 0 {
+		dfts = genfft.WithinSize(dfts, *maxSize)
+	}
+
+	// Check committed codelets against freshly generated ones.
+	if flag.Arg(0) == "regen-check" {
+		drifted, err := genfft.RegenCheck(dfts)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("genfft.RegenCheck: %w", err))
+		}
+		if len(drifted) > 0 {
+			log.Fatalf("%d codelets differ from their schedules\n", len(drifted))
+		}
+		return
+	}
+
+	// Benchmark the generated transforms.
+	if flag.Arg(0) == "bench" {
+		reportFilename := "bench.json"
+		if flag.NArg() > 1 {
+			reportFilename = flag.Arg(1)
+		}
+
+		results, err := genfft.Benchmark(dfts, *benchtime)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("genfft.Benchmark: %w", err))
+		}
+
+		report, err := json.MarshalIndent(results, "", "\t")
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("json.MarshalIndent: %w", err))
+		}
+
+		log.Infof("writing %s\n", reportFilename)
+		if err := os.WriteFile(reportFilename, report, 0644); err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.WriteFile: %w", err))
+		}
+		return
+	}
+
+	// Rewrite the tables of transforms checked by the dft package's tests.
+	if flag.Arg(0) == "tables" {
+		testFilename := filepath.Join("dft", "dft_test.go")
+		if flag.NArg() > 1 {
+			testFilename = flag.Arg(1)
+		}
+
+		var funcs []genfft.FuncAPI
+		for _, dft := range dfts {
+			fnFuncs, err := describeFuncs(dft)
+			if err != nil {
+				log.Fatalf("%+v\n", fmt.Errorf("%s: %w", dft.Prefix, err))
+			}
+			funcs = append(funcs, fnFuncs...)
+		}
+
+		src, err := os.ReadFile(testFilename)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.ReadFile: %w", err))
+		}
+		src, err = genfft.RewriteTables(src, funcs)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("%s: %w", testFilename, err))
+		}
+
+		log.Infof("writing %s\n", testFilename)
+		if err := os.WriteFile(testFilename, src, 0644); err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.WriteFile: %w", err))
+		}
+		return
+	}
+
+	// Arguments other than subcommands select transforms by prefix, support
+	// files are still written for every transform since they're shared.
+	generated := dfts
+	if flag.NArg() > 0 {
+		generated, err = genfft.SelectPrefixes(dfts, flag.Args())
+		if err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+	}
+
+	// Generate every transform before reporting failures, so one bad
+	// schedule doesn't lose the rest of the batch.
+	describe := *apiFilename != "" || *testsFilename != ""
+	var (
+		api     []genfft.FuncAPI
+		written []genfft.Dft
+		failed  []error
+	)
+	if *singleFile {
+		api, written, failed = generateSingleFiles(generated, describe)
+	} else {
+		api, written, failed = generateAll(generated, describe)
+	}
+
+	// Write the files shared by the transforms, leaving out those that
+	// failed so they don't reference functions that were never written.
+	for filename, f := range genfft.SupportFiles(withoutFailed(dfts, generated, written)) {
+		log.Infof("writing %s\n", filename)
+		err = f.Save(filename)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("f.Save: %w", err))
+		}
+	}
+
+	// Describe the generated functions.
+	if *apiFilename != "" {
+		apiBytes, err := json.MarshalIndent(api, "", "\t")
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("json.MarshalIndent: %w", err))
+		}
+
+		log.Infof("writing %s\n", *apiFilename)
+		err = os.WriteFile(*apiFilename, apiBytes, 0644)
+		if err != nil {
+			log.Fatalf("%+v\n", fmt.Errorf("os.WriteFile: %w", err))
+		}
+	}
+
+	// Check the generated functions in the dft package's tests.
+	if *testsFilename != "" {
+		if err := emitTests(*testsFilename, api); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+	}
+
+	// Summarize the transforms that failed.
+	for _, err := range failed {
+		log.Errorf("%+v\n", err)
+	}
+	if len(failed) > 0 {
+		log.Fatalf("%d of %d transforms failed\n", len(failed), len(generated))
+	}
+
+	// Print the cost of each generated transform.
+	if *report {
+		if err := printReport(os.Stdout, generated); err != nil {
+			log.Fatalf("%+v\n", err)
+		}
+	}
+}
+
+// printReport writes a table of the adds and multiplies performed by each
+// transform to w.
+func printReport(w io.Writer, dfts []genfft.Dft) error {
+	counts, err := genfft.OpCounts(dfts)
+	if err != nil {
+		return fmt.Errorf("genfft.OpCounts: %w", err)
+	}
+
+	return genfft.FprintOpCounts(w, counts)
+}
+
+// withoutFailed returns the transforms in dfts that either weren't among
+// those generated or were written.
+func withoutFailed(dfts, generated, written []genfft.Dft) (kept []genfft.Dft) {
+	type key struct{ prefix, fn string }
+
+	failed := map[key]bool{}
+	for _, dft := range generated {
+		failed[key{dft.Prefix, dft.Func}] = true
+	}
+	for _, dft := range written {
+		delete(failed, key{dft.Prefix, dft.Func})
+	}
+
+	for _, dft := range dfts {
+		if !failed[key{dft.Prefix, dft.Func}] {
+			kept = append(kept, dft)
+		}
+	}
+	return kept
+}
+
+// generateAll writes the transforms concurrently, one per CPU at a time,
+// returning descriptions of the generated functions if describe is set, the
+// transforms written, and an error for each transform that failed. All are
+// in the order of dfts.
+func generateAll(dfts []genfft.Dft, describe bool) (api []genfft.FuncAPI, written []genfft.Dft, failed []error) {
+	type result struct {
+		idx   int
+		funcs []genfft.FuncAPI
+		err   error
+	}
+
+	jobs := make(chan int)
+	results := make(chan result)
+
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				dft := dfts[idx]
+				funcs, err := generate(dft, "dft", dft.Prefix+".go", describe)
+				results <- result{idx, funcs, err}
+			}
+		}()
+	}
+
+	go func() {
+		for idx := range dfts {
+			jobs <- idx
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	// Results arrive in whatever order transforms finish.
+	funcs := make([][]genfft.FuncAPI, len(dfts))
+	errs := make([]error, len(dfts))
+	for r := range results {
+		funcs[r.idx], errs[r.idx] = r.funcs, r.err
+	}
+
+	for idx, dft := range dfts {
+		if errs[idx] != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", dft.Prefix, errs[idx]))
+			continue
+		}
+		api = append(api, funcs[idx]...)
+		written = append(written, dft)
+	}
+
+	return api, written, failed
+}
+
+// generateSingleFiles writes the transforms in each directory to a single
+// dft_all.go, returning descriptions of the generated functions if describe
+// is set, the transforms written, and an error for each directory that
+// failed.
+func generateSingleFiles(dfts []genfft.Dft, describe bool) (api []genfft.FuncAPI, written []genfft.Dft, failed []error) {
+	var dirs []string
+	byDir := map[string][]genfft.Dft{}
+	for _, dft := range dfts {
+		dir := filepath.Dir(dft.Prefix)
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], dft)
+	}
+
+	for _, dir := range dirs {
+		f, err := genfft.RenderAll("dft", byDir[dir])
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%s: %w", dir, err))
+			continue
+		}
+
+		var funcs []genfft.FuncAPI
+		if describe {
+			// Each transform is described from a file of its own, since
+			// descriptions take the transform's size from its program.
+			for _, dft := range byDir[dir] {
+				fnFuncs, err := describeFuncs(dft)
+				if err != nil {
+					failed = append(failed, fmt.Errorf("%s: %w", dft.Prefix, err))
+					continue
+				}
+				funcs = append(funcs, fnFuncs...)
+			}
+		}
+
+		goFilename := filepath.Join(dir, "dft_all.go")
+		log.Infof("writing %s\n", goFilename)
+		if err := f.Save(goFilename); err != nil {
+			failed = append(failed, fmt.Errorf("%s: f.Save: %w", dir, err))
+			continue
+		}
+		api = append(api, funcs...)
+		written = append(written, byDir[dir]...)
+	}
+
+	return api, written, failed
+}
+
+// emitTests adds funcs to the generated tests in filename, creating the file
+// if it doesn't exist.
+func emitTests(filename string, funcs []genfft.FuncAPI) error {
+	src, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	src, err = genfft.GeneratedTests(src, funcs)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	log.Infof("writing %s\n", filename)
+	if err := os.WriteFile(filename, src, 0644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+	return nil
+}
+
+// describeFuncs renders the transform described by dft in a file of its own,
+// returning descriptions of its functions.
+func describeFuncs(dft genfft.Dft) ([]genfft.FuncAPI, error) {
+	prog, err := dft.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := prog.Render("dft", dft.Func)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs, err := prog.API(f)
+	if err != nil {
+		return nil, fmt.Errorf("prog.API: %w", err)
+	}
+	return funcs, nil
+}
+
+// generateStdin renders the transform whose schedule is read from r, and
+// whose constants are defined inline or in coutFilename, as the function
+// funcName in package pkg with options opts, writing it to w.
+func generateStdin(r io.Reader, w io.Writer, coutFilename, funcName, pkg string, opts genfft.Options) error {
+	if err := checkNames(funcName, pkg); err != nil {
+		return err
+	}
+
+	var cout io.Reader
+	if coutFilename != "" {
+		coutFile, err := os.Open(coutFilename)
+		if err != nil {
+			return fmt.Errorf("os.Open: %w", err)
+		}
+		defer coutFile.Close()
+		cout = coutFile
+	}
+
+	prog, err := genfft.ParseProgram(r, cout)
+	if err != nil {
+		return err
+	}
+	prog.Options = opts
+
+	f, err := prog.Render(pkg, funcName)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Render(w); err != nil {
+		return fmt.Errorf("f.Render: %w", err)
+	}
+	return nil
+}
+
+// checkNames returns an error if the function or package named by flags
+// isn't a valid go identifier.
+func checkNames(funcName, pkg string) error {
+	if !token.IsIdentifier(funcName) {
+		return fmt.Errorf("-func %q isn't a valid go identifier", funcName)
+	}
+	if !token.IsIdentifier(pkg) {
+		return fmt.Errorf("-pkg %q isn't a valid go identifier", pkg)
+	}
+
+	return nil
+}
+
+// checkFlags returns an error if the transform described by flags has no
+// valid function or package name, or its schedule or constants are missing.
+func checkFlags(dft genfft.Dft, pkg string) error {
+	if err := checkNames(dft.Func, pkg); err != nil {
+		return err
+	}
+
+	for _, ext := range []string{".alst", ".cout"} {
+		if _, err := os.Stat(dft.Prefix + ext); err != nil {
+			return fmt.Errorf("os.Stat: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// generate writes the transform described by dft in package pkg to
+// goFilename, and its in-place variant if it has one, returning descriptions
+// of its functions if describe is set.
+func generate(dft genfft.Dft, pkg, goFilename string, describe bool) (funcs []genfft.FuncAPI, err error) {
+	// Generate code from the schedule.
+	prog, err := dft.Parse()
+	if err != nil {
+		return nil, err
+	}
+	f, err := prog.Render(pkg, dft.Func)
+	if err != nil {
+		return nil, err
+	}
+
+	if describe {
+		funcs, err = prog.API(f)
+		if err != nil {
+			return nil, fmt.Errorf("prog.API: %w", err)
+		}
+	}
+
+	// Write the code to disk.
+	log.Infof("writing %s\n", goFilename)
+	err = f.Save(goFilename)
+	if err != nil {
+		return nil, fmt.Errorf("f.Save: %w", err)
+	}
+
+	// Write the variant selected by the inplace build tag.
+	if dft.InPlaceTag {
+		inPlaceFilename := strings.TrimSuffix(goFilename, ".go") + "_inplace.go"
+		f, err := prog.RenderInPlace(pkg, dft.Func)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Infof("writing %s\n", inPlaceFilename)
+		err = f.Save(inPlaceFilename)
+		if err != nil {
+			return nil, fmt.Errorf("f.Save: %w", err)
+		}
+	}
+
+	return funcs, nil
+}
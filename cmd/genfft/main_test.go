@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bemasher/genfft"
+)
+
+// TestMain runs main in place of the tests when re-executed by runMain.
+func TestMain(m *testing.M) {
+	if os.Getenv("GENFFT_TEST_MAIN") == "1" {
+		os.Args = append([]string{"genfft"}, strings.Fields(os.Getenv("GENFFT_TEST_ARGS"))...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs the command with args in a child process, returning its
+// combined output and whether it succeeded.
+func runMain(t *testing.T, args ...string) (string, bool) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), "GENFFT_TEST_MAIN=1", "GENFFT_TEST_ARGS="+strings.Join(args, " "))
+	out, err := cmd.CombinedOutput()
+	if _, ok := err.(*exec.ExitError); err != nil && !ok {
+		t.Fatal(err)
+	}
+	return string(out), err == nil
+}
+
+// copyTestdata copies a schedule and its constants into dir, returning the new prefix.
+func copyTestdata(t *testing.T, dir, name string) string {
+	t.Helper()
+	for _, ext := range []string{".alst", ".cout"} {
+		b, err := os.ReadFile(filepath.Join("..", "..", "testdata", name+ext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+ext), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, name)
+}
+
+func TestGenerateAllContinues(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	dfts := []genfft.Dft{
+		{Prefix: filepath.Join(dir, "missing"), Func: "DftCmplx2"},
+		{Prefix: prefix, Func: "DftCmplx3", Options: genfft.Options{BitReverse: true}},
+		{Prefix: prefix, Func: "DftCmplx3"},
+	}
+
+	api, written, failed := generateAll(dfts, true)
+	if len(failed) != 2 {
+		t.Fatalf("expected the missing and bit-reversed transforms to fail, got %v", failed)
+	}
+	if len(written) != 1 || written[0].Func != "DftCmplx3" || written[0].Options.BitReverse {
+		t.Fatalf("expected only the plain DftCmplx3 written, got %+v", written)
+	}
+	if len(api) == 0 || api[0].Name != "DftCmplx3" {
+		t.Fatalf("expected DftCmplx3 described after earlier failures, got %+v", api)
+	}
+	if _, err := os.Stat(prefix + ".go"); err != nil {
+		t.Fatalf("expected DftCmplx3 written after earlier failures: %v", err)
+	}
+}
+
+func TestMainReportsFailures(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	// Truncate a copy of the schedule mid-statement.
+	for _, ext := range []string{".alst", ".cout"} {
+		b, err := os.ReadFile(prefix + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ext == ".alst" {
+			b = b[:bytes.LastIndexByte(b[:len(b)/2], ')')]
+		}
+		if err := os.WriteFile(filepath.Join(dir, "bad"+ext), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// DftBadRev parses, but a transform of length 3 can't be bit-reversed.
+	if err := os.Mkdir(filepath.Join(dir, "rev"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	copyTestdata(t, filepath.Join(dir, "rev"), "cmplx_3")
+	config := `[
+	{ "prefix": "cmplx_3", "func": "DftCmplx3" },
+	{ "prefix": "bad", "func": "DftBad" },
+	{ "prefix": "rev/cmplx_3", "func": "DftBadRev", "bitReverse": true }
+]`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, ok := runMain(t, "-C", dir)
+	if ok {
+		t.Fatalf("expected failure, got:\n%s", out)
+	}
+	if !strings.Contains(out, "2 of 3 transforms failed") {
+		t.Fatalf("expected a summary of the failure, got:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cmplx_3.go")); err != nil {
+		t.Fatalf("expected DftCmplx3 written despite the others failing: %v", err)
+	}
+
+	// Support files only reference the transforms written.
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	revMatches, err := filepath.Glob(filepath.Join(dir, "rev", "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches = append(matches, revMatches...)
+	for _, filename := range matches {
+		src, err := os.ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(src), "DftBad") {
+			t.Errorf("%s references a transform that failed:\n%s", filename, src)
+		}
+	}
+}
+
+func TestMainReportsBrokenGlobMatch(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	// A schedule matched by the glob alongside cmplx_3, truncated
+	// mid-statement.
+	for _, ext := range []string{".alst", ".cout"} {
+		b, err := os.ReadFile(prefix + ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ext == ".alst" {
+			b = b[:bytes.LastIndexByte(b[:len(b)/2], ')')]
+		}
+		if err := os.WriteFile(filepath.Join(dir, "cmplx_29"+ext), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := `[{ "prefix": "cmplx_*" }]`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, ok := runMain(t, "-C", dir)
+	if ok {
+		t.Fatalf("expected failure, got:\n%s", out)
+	}
+	if !strings.Contains(out, "1 of 2 transforms failed") {
+		t.Fatalf("expected a summary of the failure, got:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "cmplx_3.go")); err != nil {
+		t.Fatalf("expected DftCmplx3 written despite cmplx_29 failing: %v", err)
+	}
+}
+
+func TestGenerateAllOrder(t *testing.T) {
+	var dfts []genfft.Dft
+	for idx := 0; idx < 16; idx++ {
+		dft := genfft.Dft{Prefix: copyTestdata(t, t.TempDir(), "cmplx_3"), Func: fmt.Sprintf("DftCmplx3V%d", idx)}
+		if idx%5 == 0 {
+			dft.Prefix += "_missing"
+		}
+		dfts = append(dfts, dft)
+	}
+
+	api, written, failed := generateAll(dfts, true)
+	if len(failed) != 4 {
+		t.Fatalf("expected 4 failures, got %v", failed)
+	}
+	if len(written) != 12 {
+		t.Fatalf("expected 12 transforms written, got %d", len(written))
+	}
+	for idx, err := range failed {
+		if prefix := dfts[idx*5].Prefix; !strings.HasPrefix(err.Error(), prefix+":") {
+			t.Errorf("failure %d: expected %s, got %v", idx, prefix, err)
+		}
+	}
+
+	var names []string
+	for _, fn := range api {
+		if strings.HasPrefix(fn.Name, "DftCmplx3V") {
+			names = append(names, fn.Name)
+		}
+	}
+	var want []string
+	for idx, dft := range dfts {
+		if idx%5 != 0 {
+			want = append(want, dft.Func)
+		}
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected functions in config order %v, got %v", want, names)
+	}
+}
+
+func TestCheckFlags(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	if err := checkFlags(genfft.Dft{Prefix: prefix, Func: "DftCmplx3"}, "dft"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkFlags(genfft.Dft{Prefix: prefix, Func: "3Dft"}, "dft"); err == nil {
+		t.Error("expected an error for an invalid function name")
+	}
+	if err := checkFlags(genfft.Dft{Prefix: prefix, Func: "DftCmplx3"}, "my-dft"); err == nil {
+		t.Error("expected an error for an invalid package name")
+	}
+
+	if err := os.Remove(prefix + ".cout"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkFlags(genfft.Dft{Prefix: prefix, Func: "DftCmplx3"}, "dft"); err == nil {
+		t.Error("expected an error for missing constants")
+	}
+}
+
+func TestGenerateOut(t *testing.T) {
+	prefix := copyTestdata(t, t.TempDir(), "cmplx_3")
+	goFilename := filepath.Join(t.TempDir(), "cmplx_3.go")
+
+	if _, err := generate(genfft.Dft{Prefix: prefix, Func: "DftCmplx3"}, "fft", goFilename, false); err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := os.ReadFile(goFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(src), "package fft\n") || !strings.Contains(string(src), "func DftCmplx3(") {
+		t.Fatalf("expected DftCmplx3 in package fft:\n%s", src)
+	}
+}
+
+func TestEmitTests(t *testing.T) {
+	prefix := copyTestdata(t, t.TempDir(), "cmplx_3")
+	testsFilename := filepath.Join(t.TempDir(), "dft_generated_test.go")
+
+	funcs, err := generate(genfft.Dft{Prefix: prefix, Func: "DftCmplx3"}, "dft", prefix+".go", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Emitting the same functions twice lists them once.
+	for i := 0; i < 2; i++ {
+		if err := emitTests(testsFilename, funcs); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src, err := os.ReadFile(testsFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(src), "{3, DftCmplx3}"); n != 1 {
+		t.Fatalf("expected DftCmplx3 listed once, got %d:\n%s", n, src)
+	}
+}
+
+func TestGenerateSingleFiles(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	dfts := []genfft.Dft{
+		{Prefix: prefix, Func: "DftCmplx3"},
+		{Prefix: prefix, Func: "DftCmplx3Again"},
+	}
+	api, written, failed := generateSingleFiles(dfts, true)
+	if len(failed) > 0 {
+		t.Fatal(failed)
+	}
+	if len(written) != 2 {
+		t.Fatalf("expected both transforms written, got %+v", written)
+	}
+	if len(api) != 2 {
+		t.Fatalf("expected two functions described, got %+v", api)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "dft_all.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"func DftCmplx3(", "func DftCmplx3Again("} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing %s in:\n%s", want, src)
+		}
+	}
+	if n := strings.Count(string(src), "KP866025403 ="); n != 1 {
+		t.Errorf("expected KP866025403 defined once, got %d", n)
+	}
+}
+
+func TestGenerateStdin(t *testing.T) {
+	prefix := copyTestdata(t, t.TempDir(), "cmplx_3")
+	alst, err := os.ReadFile(prefix + ".alst")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := generateStdin(bytes.NewReader(alst), &out, prefix+".cout", "DftCmplx3", "fft", genfft.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if src := out.String(); !strings.HasPrefix(src, "package fft\n") || !strings.Contains(src, "KP866025403 = ") {
+		t.Fatalf("expected DftCmplx3 and its constants in package fft:\n%s", src)
+	}
+
+	// Options such as -inverse apply to the generated transform.
+	out.Reset()
+	if err := generateStdin(bytes.NewReader(alst), &out, prefix+".cout", "DftCmplxInv3", "fft", genfft.Options{Inverse: true}); err != nil {
+		t.Fatal(err)
+	}
+	if src := out.String(); !strings.Contains(src, "= -1i") {
+		t.Fatalf("expected a negated imaginary constant:\n%s", src)
+	}
+
+	// A function name is still required.
+	if err := generateStdin(bytes.NewReader(alst), &out, "", "", "fft", genfft.Options{}); err == nil {
+		t.Error("expected an error for a missing -func")
+	}
+}
+
+func TestPrintReport(t *testing.T) {
+	dft := genfft.Dft{Prefix: copyTestdata(t, t.TempDir(), "cmplx_3"), Func: "DftCmplx3"}
+
+	var out bytes.Buffer
+	if err := printReport(&out, []genfft.Dft{dft}); err != nil {
+		t.Fatal(err)
+	}
+	if src := out.String(); !strings.Contains(src, "mults") || !strings.Contains(src, "DftCmplx3") {
+		t.Fatalf("expected a table with a row for DftCmplx3:\n%s", src)
+	}
+
+	// Transforms whose schedules are missing fail the report.
+	dft.Prefix += "_missing"
+	if err := printReport(&out, []genfft.Dft{dft}); err == nil {
+		t.Error("expected an error for a missing schedule")
+	}
+}
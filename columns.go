@@ -0,0 +1,105 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genColumns renders a wrapper transforming each column of a row-major
+// matrix in place, the second half of a two-dimensional transform. Strided
+// schedules read each column directly with a stride of the row length,
+// others gather it first. Either way the transform is written to a column
+// on the stack and scattered back.
+func (p Program) genColumns(f *jen.File, name string) error {
+	if p.Float() {
+		return fmt.Errorf("%s: column transforms require a complex schedule", name)
+	}
+
+	n := p.TransformLength()
+	columns := name + "Columns"
+
+	// Input strides step between rows, output strides between elements of
+	// the column on the stack.
+	inputStrides := map[string]bool{}
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			if slice, stride, _, ok := stridedIndex(id); ok && !isOutput(slice) {
+				inputStrides[stride] = true
+			}
+		}
+	}
+
+	var args []jen.Code
+	strided := len(p.Strides()) > 0
+	if strided {
+		args = []jen.Code{jen.Id("data").Index(jen.Id("c"), jen.Empty()), jen.Id("col").Index(jen.Empty(), jen.Empty())}
+		for _, stride := range p.Strides() {
+			if inputStrides[stride] {
+				args = append(args, jen.Id("cols"))
+			} else {
+				args = append(args, jen.Lit(1))
+			}
+		}
+	} else {
+		args = []jen.Code{jen.Id("in").Index(jen.Empty(), jen.Empty()), jen.Id("col").Index(jen.Empty(), jen.Empty())}
+	}
+
+	// Wrappers write outputs from the start of the column.
+	if p.Options.OutputBase {
+		args = append(args, jen.Lit(0))
+	}
+	var extraParams []jen.Code
+	if p.Options.RuntimeSign {
+		extraParams = append(extraParams, jen.Id("sign").Int())
+		args = append(args, jen.Id("sign"))
+	}
+	if p.Options.SignMultiplier {
+		extraParams = append(extraParams, jen.Id("sign").Float64())
+		args = append(args, jen.Id("sign"))
+	}
+
+	params := append([]jen.Code{jen.Id("data").Index().Complex128(), jen.List(jen.Id("rows"), jen.Id("cols")).Int()}, extraParams...)
+	r, c := jen.Id("r"), jen.Id("c")
+	element := jen.Id("data").Index(r.Clone().Op("*").Id("cols").Op("+").Add(c.Clone()))
+
+	fails := "panics"
+	if p.Options.ReturnErrors {
+		fails = "returns an error"
+	}
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes %s of each column of data, a row-major matrix with\nthe given number of rows and columns, in place. It %s unless rows is %d.",
+		columns, name, fails, n,
+	))
+	f.Func().Id(columns).Params(params...).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		g.If(jen.Id("rows").Op("!=").Lit(n)).Block(
+			p.genWrapperFailure(nil, fmt.Sprintf("dft: %s: columns have %%d rows, need %d", columns, n), jen.Id("rows")),
+		)
+		g.If(jen.Len(jen.Id("data")).Op("<").Id("rows").Op("*").Id("cols")).Block(
+			p.genWrapperFailure(nil, fmt.Sprintf("dft: %s: data has length %%d, need %%d", columns), jen.Len(jen.Id("data")), jen.Id("rows").Op("*").Id("cols")),
+		)
+		g.Line()
+
+		if strided {
+			g.Var().Id("col").Index(jen.Lit(n)).Complex128()
+		} else {
+			g.Var().List(jen.Id("in"), jen.Id("col")).Index(jen.Lit(n)).Complex128()
+		}
+		g.For(c.Clone().Op(":=").Lit(0), c.Clone().Op("<").Id("cols"), c.Clone().Op("++")).BlockFunc(func(g *jen.Group) {
+			if !strided {
+				g.For(r.Clone().Op(":=").Range().Id("in")).Block(
+					jen.Id("in").Index(r.Clone()).Op("=").Add(element.Clone()),
+				)
+			}
+			g.Add(p.callTransform(name, nil, args...))
+			g.For(jen.List(r.Clone(), jen.Id("x")).Op(":=").Range().Id("col")).Block(
+				element.Clone().Op("=").Id("x"),
+			)
+		})
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package genfft
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestColumns(t *testing.T) {
+	// Index a naive schedule by strides as FFTW's stride codelets do.
+	alst, cout := naiveSchedule(8, false)
+	alst = regexp.MustCompile(`xi\[(\d+)\]`).ReplaceAllString(alst, "xi[WS(is, $1)]")
+	alst = regexp.MustCompile(`xo\[(\d+)\]`).ReplaceAllString(alst, "xo[WS(os, $1)]")
+	strided := Dft{Prefix: writeSchedule(t, t.TempDir(), "DftCmplx8Strided", alst, cout), Func: "DftCmplx8Strided", Options: Options{Columns: true}}
+
+	files := map[string]*jen.File{
+		"cmplx_8.go":         generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Columns: true}}),
+		"cmplx_8_strided.go": mustGenerate(t, strided),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func checkColumns(t *testing.T, name string, columns func(data []complex128, rows, cols int)) {
+	const rows, cols = 8, 3
+	data := randCmplx(rows * cols)
+
+	want := make([]complex128, len(data))
+	for c := 0; c < cols; c++ {
+		col := make([]complex128, rows)
+		for r := range col {
+			col[r] = data[r*cols+c]
+		}
+		naiveDFT(col, -1.0)
+		for r, x := range col {
+			want[r*cols+c] = x
+		}
+	}
+
+	columns(data, rows, cols)
+	if err := dftError(data, want); err > 1e-13 {
+		t.Fatalf("%s: error %g", name, err)
+	}
+}
+
+func wrongRowsPanics(columns func(data []complex128, rows, cols int)) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	columns(make([]complex128, 12), 4, 3)
+	return
+}
+
+func TestColumns(t *testing.T) {
+	checkColumns(t, "DftCmplx8Columns", DftCmplx8Columns)
+	checkColumns(t, "DftCmplx8StridedColumns", DftCmplx8StridedColumns)
+
+	if !wrongRowsPanics(DftCmplx8Columns) {
+		t.Fatal("columns of the wrong length didn't panic")
+	}
+}
+`)
+}
@@ -0,0 +1,177 @@
+package genfft
+
+import (
+	"fmt"
+	"io"
+	"math/cmplx"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Eval evaluates the program's schedule on the complex input x in complex128
+// arithmetic, returning its output. The real and imaginary parts of x are
+// the separate inputs of float schedules. Operators other than addition,
+// subtraction, negation and multiplication are errors, as lowerings only
+// describe how to render an operator, not its value.
+func (p Program) Eval(x []complex128) ([]complex128, error) {
+	values := map[string]complex128{"I": 1i}
+	for _, c := range p.Constants {
+		v, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("constant %s: %w", c.Name, err)
+		}
+		values[c.Name] = complex(v, 0)
+	}
+
+	out := make([]complex128, len(x))
+	read := func(id string) (complex128, error) {
+		if v, ok := values[id]; ok {
+			return v, nil
+		}
+
+		slice, k, ok := elementIndex(id)
+		if !ok || k >= len(x) {
+			return 0, fmt.Errorf("undefined identifier %s", id)
+		}
+		switch slice {
+		case "xi":
+			return x[k], nil
+		case "ri":
+			return complex(real(x[k]), 0), nil
+		case "ii":
+			return complex(imag(x[k]), 0), nil
+		}
+		return 0, fmt.Errorf("identifier %s read before it's written", id)
+	}
+
+	var eval func(e Expr) (complex128, error)
+	eval = func(e Expr) (complex128, error) {
+		if e.Ident != "" {
+			return read(e.Ident)
+		}
+
+		operands := make([]complex128, len(e.Sub))
+		for idx, sub := range e.Sub {
+			v, err := eval(sub)
+			if err != nil {
+				return 0, err
+			}
+			operands[idx] = v
+		}
+
+		v := operands[0]
+		switch {
+		case e.Op == "-" && len(operands) == 1:
+			return -v, nil
+		case e.Op == "+":
+			for _, operand := range operands[1:] {
+				v += operand
+			}
+		case e.Op == "-":
+			for _, operand := range operands[1:] {
+				v -= operand
+			}
+		case e.Op == "*":
+			for _, operand := range operands[1:] {
+				v *= operand
+			}
+		default:
+			return 0, fmt.Errorf("%s: can't evaluate operator %q", e.Pos, e.Op)
+		}
+		return v, nil
+	}
+
+	for _, expr := range p.Statements {
+		if expr.Op != ":=" || len(expr.Sub) != 2 {
+			return nil, fmt.Errorf("%s: statement isn't an assignment", expr.Pos)
+		}
+
+		v, err := eval(expr.Sub[1])
+		if err != nil {
+			return nil, err
+		}
+
+		lhs := expr.Sub[0].Ident
+		slice, k, ok := elementIndex(lhs)
+		switch {
+		case !ok:
+			values[lhs] = v
+		case k >= len(out):
+			return nil, fmt.Errorf("%s: output %s out of range", expr.Pos, lhs)
+		case slice == "xo":
+			out[k] = v
+		case slice == "ro":
+			out[k] = complex(real(v), imag(out[k]))
+		case slice == "io":
+			out[k] = complex(real(out[k]), real(v))
+		default:
+			return nil, fmt.Errorf("%s: assignment to input %s", expr.Pos, lhs)
+		}
+	}
+
+	return out, nil
+}
+
+// CompareSchedules evaluates two schedules of the same length on the same
+// random inputs, returning the largest difference between each of their
+// outputs over every trial.
+func CompareSchedules(a, b *Program, trials int, rng *rand.Rand) ([]float64, error) {
+	n := a.TransformLength()
+	if length := b.TransformLength(); length != n {
+		return nil, fmt.Errorf("schedules have lengths %d and %d", n, length)
+	}
+
+	diffs := make([]float64, n)
+	x := make([]complex128, n)
+	for trial := 0; trial < trials; trial++ {
+		for k := range x {
+			x[k] = complex(rng.Float64()*2-1, rng.Float64()*2-1)
+		}
+
+		outA, err := a.Eval(x)
+		if err != nil {
+			return nil, fmt.Errorf("first schedule: %w", err)
+		}
+		outB, err := b.Eval(x)
+		if err != nil {
+			return nil, fmt.Errorf("second schedule: %w", err)
+		}
+
+		for k := range diffs {
+			if d := cmplx.Abs(outA[k] - outB[k]); d > diffs[k] {
+				diffs[k] = d
+			}
+		}
+	}
+
+	return diffs, nil
+}
+
+// FprintDiffs writes the largest difference of each output to w, one per
+// line, followed by the largest of them all.
+func FprintDiffs(w io.Writer, diffs []float64) {
+	var max float64
+	for k, d := range diffs {
+		fmt.Fprintf(w, "output %d: %g\n", k, d)
+		if d > max {
+			max = d
+		}
+	}
+	fmt.Fprintf(w, "max: %g\n", max)
+}
+
+// CompareFiles parses two schedule files, with constants from C output
+// sharing their prefix, and compares them over trials random inputs.
+func CompareFiles(alstA, alstB string, trials int) ([]float64, error) {
+	var progs [2]*Program
+	for idx, alst := range []string{alstA, alstB} {
+		prog, err := Dft{Prefix: strings.TrimSuffix(alst, ".alst")}.Parse()
+		if err != nil {
+			return nil, err
+		}
+		progs[idx] = prog
+	}
+
+	return CompareSchedules(progs[0], progs[1], trials, rand.New(rand.NewSource(1)))
+}
@@ -0,0 +1,82 @@
+package genfft
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCompareSchedules(t *testing.T) {
+	dir := t.TempDir()
+	fftw := copyTestdata(t, dir, "cmplx_3")
+
+	alst, cout := naiveSchedule(3, false)
+	naive := writeSchedule(t, dir, "naive_3", alst, cout)
+	alst, cout = naiveSchedule(3, true)
+	float := writeSchedule(t, dir, "float_3", alst, cout)
+
+	// Conjugating one output makes a different transform.
+	b, err := os.ReadFile(fftw + ".alst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := os.ReadFile(fftw + ".cout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = bytes.Replace(b, []byte("(:= xo[1] (+ T5 T6))"), []byte("(:= xo[1] (+ T5 (- T6)))"), 1)
+	broken := writeSchedule(t, dir, "broken_3", string(b), string(c))
+
+	for _, other := range []string{naive, float} {
+		diffs, err := CompareFiles(fftw+".alst", other+".alst", 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, d := range diffs {
+			if d > 1e-14 {
+				t.Errorf("%s: equivalent output %d differs by %g", other, k, d)
+			}
+		}
+	}
+
+	diffs, err := CompareFiles(fftw+".alst", broken+".alst", 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs[0] > 1e-14 || diffs[2] > 1e-14 {
+		t.Errorf("unchanged outputs differ: %v", diffs)
+	}
+	if diffs[1] < 0.1 {
+		t.Errorf("changed output differs by only %g", diffs[1])
+	}
+
+	var report strings.Builder
+	FprintDiffs(&report, diffs)
+	if got := report.String(); !strings.HasPrefix(got, "output 0: ") || !strings.Contains(got, "\nmax: ") {
+		t.Errorf("unexpected report:\n%s", got)
+	}
+}
+
+func TestEval(t *testing.T) {
+	prog, err := Dft{Prefix: "testdata/cmplx_3"}.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A unit impulse transforms to all ones.
+	out, err := prog.Eval([]complex128{1, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range out {
+		if v != 1 {
+			t.Errorf("output %d: %v, want 1", k, v)
+		}
+	}
+
+	prog.Statements[0].Sub[1] = Expr{Op: "VSQRT", Sub: []Expr{{Ident: "xi[0]"}}}
+	if _, err := prog.Eval(make([]complex128, 3)); err == nil || !strings.Contains(err.Error(), `"VSQRT"`) {
+		t.Errorf("expected an error evaluating an unknown operator, got %v", err)
+	}
+}
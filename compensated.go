@@ -0,0 +1,84 @@
+package genfft
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// compensatedSum returns the name of the summation helper for a transform's
+// element type.
+func compensatedSum(float bool) string {
+	if float {
+		return "compensatedSumFloat"
+	}
+	return "compensatedSumCmplx"
+}
+
+// GenCompensated renders a go-representation of an expression, lowering
+// additions of three or more terms to calls to the compensated summation
+// helper sum.
+func (e Expr) GenCompensated(sum string) *jen.Statement {
+	switch {
+	case e.Op == ":=" && len(e.Sub) == 2:
+		// When the left side of an expression is an indexed identifier, assign only.
+		op := ":="
+		if strings.HasSuffix(e.Sub[0].Ident, "]") {
+			op = "="
+		}
+		return genIdent(e.Sub[0].Ident).Op(op).Add(e.Sub[1].GenCompensated(sum))
+
+	case e.Op == "+" && len(e.Sub) > 2:
+		terms := make([]jen.Code, len(e.Sub))
+		for idx, sub := range e.Sub {
+			terms[idx] = sub.GenCompensated(sum)
+		}
+		return jen.Id(sum).Call(terms...)
+	}
+
+	return e.Gen()
+}
+
+// compensatedSupport renders the compensated summation helpers used by
+// transforms with the compensated option.
+func compensatedSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	sum, c, x := jen.Id("sum"), jen.Id("c"), jen.Id("x")
+
+	// Neumaier's variant of Kahan summation also compensates when a term is
+	// larger than the running sum.
+	f.Comment("compensate adds x to sum, accumulating the rounding error of the addition in c.")
+	f.Func().Id("compensate").Params(jen.List(sum, c, x).Float64()).Params(jen.Float64(), jen.Float64()).Block(
+		jen.Id("t").Op(":=").Add(sum).Op("+").Add(x),
+		jen.If(jen.Qual("math", "Abs").Call(sum).Op(">=").Qual("math", "Abs").Call(x)).Block(
+			jen.Add(c).Op("+=").Parens(jen.Add(sum).Op("-").Id("t")).Op("+").Add(x),
+		).Else().Block(
+			jen.Add(c).Op("+=").Parens(jen.Add(x).Op("-").Id("t")).Op("+").Add(sum),
+		),
+		jen.Return(jen.Id("t"), c),
+	)
+	f.Line()
+
+	f.Comment("compensatedSumFloat returns the sum of terms corrected by its accumulated\nrounding error.")
+	f.Func().Id("compensatedSumFloat").Params(jen.Id("terms").Op("...").Float64()).Float64().Block(
+		jen.Var().List(sum, c).Float64(),
+		jen.For(jen.List(jen.Id("_"), x).Op(":=").Range().Id("terms")).Block(
+			jen.List(sum, c).Op("=").Id("compensate").Call(sum, c, x),
+		),
+		jen.Return(jen.Add(sum).Op("+").Add(c)),
+	)
+	f.Line()
+
+	f.Comment("compensatedSumCmplx returns the sum of terms, compensating the real and\nimaginary parts separately.")
+	f.Func().Id("compensatedSumCmplx").Params(jen.Id("terms").Op("...").Complex128()).Complex128().Block(
+		jen.Var().List(jen.Id("re"), jen.Id("reC"), jen.Id("im"), jen.Id("imC")).Float64(),
+		jen.For(jen.List(jen.Id("_"), x).Op(":=").Range().Id("terms")).Block(
+			jen.List(jen.Id("re"), jen.Id("reC")).Op("=").Id("compensate").Call(jen.Id("re"), jen.Id("reC"), jen.Real(x)),
+			jen.List(jen.Id("im"), jen.Id("imC")).Op("=").Id("compensate").Call(jen.Id("im"), jen.Id("imC"), jen.Imag(x)),
+		),
+		jen.Return(jen.Complex(jen.Id("re").Op("+").Id("reC"), jen.Id("im").Op("+").Id("imC"))),
+	)
+
+	return f
+}
@@ -0,0 +1,57 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestCompensated(t *testing.T) {
+	compensated := generateNaive(t, 4, Dft{Func: "DftCmplx4Compensated", Options: Options{Compensated: true}})
+	if src := compensated.GoString(); !strings.Contains(src, "compensatedSumCmplx(") {
+		t.Fatalf("additions weren't lowered to compensated sums:\n%s", src)
+	}
+
+	files := map[string]*jen.File{
+		"cmplx_4.go":             generateNaive(t, 4, Dft{Func: "DftCmplx4"}),
+		"cmplx_4_compensated.go": compensated,
+		"float_4_compensated.go": generateNaive(t, 4, Dft{Func: "DftFloat4Compensated", Options: Options{Compensated: true}}),
+		"compensated.go":         compensatedSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestCompensated(t *testing.T) {
+	// The DC bin sums all inputs, naive summation loses both unit terms to
+	// the large ones.
+	xi := []complex128{1e16, 1, -1e16, 1}
+	naive, compensated := make([]complex128, 4), make([]complex128, 4)
+	DftCmplx4(xi, naive)
+	DftCmplx4Compensated(xi, compensated)
+
+	if naive[0] == 2 {
+		t.Fatalf("naive summation was exact, input doesn't exercise rounding")
+	}
+	if compensated[0] != 2 {
+		t.Fatalf("compensated DC bin %v, expected 2", compensated[0])
+	}
+
+	ri, ii := []float64{1e16, 1, -1e16, 1}, make([]float64, 4)
+	ro, io := make([]float64, 4), make([]float64, 4)
+	DftFloat4Compensated(ri, ii, ro, io)
+	if ro[0] != 2 {
+		t.Fatalf("compensated float DC bin %v, expected 2", ro[0])
+	}
+
+	xi = randCmplx(4)
+	DftCmplx4Compensated(xi, compensated)
+	naiveDFT(xi, -1.0)
+	if err := dftError(compensated, xi); err > 1e-14 {
+		t.Fatalf("compensated transform error %g", err)
+	}
+}
+`)
+}
@@ -0,0 +1,144 @@
+package genfft
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// ConstBlock renders constants as a const block, deduplicated by name and
+// sorted. The first definition of a repeated name wins.
+func ConstBlock(constants []Constant) *jen.Statement {
+	seen := map[string]bool{}
+	var unique []Constant
+	for _, c := range constants {
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+		unique = append(unique, c)
+	}
+
+	sort.Slice(unique, func(i, j int) bool {
+		return unique[i].Name < unique[j].Name
+	})
+
+	return jen.Const().DefsFunc(func(d *jen.Group) {
+		for _, c := range unique {
+			d.Add(c.Gen())
+		}
+	})
+}
+
+// signRe matches the sign FFTW encodes in the names of its constants, KP for
+// positive values and KN for negative ones.
+var signRe = regexp.MustCompile(`^K([NP])\d`)
+
+// signed returns the constant with the sign of its value matching the sign
+// encoded in its name. Values without an explicit sign take the name's, and
+// values contradicting their name are corrected to it with a warning.
+func (c Constant) signed() Constant {
+	m := signRe.FindStringSubmatch(c.Name)
+	if m == nil {
+		return c
+	}
+
+	sign := "+"
+	if m[1] == "N" {
+		sign = "-"
+	}
+
+	magnitude := strings.TrimLeft(c.Value, "+-")
+	if explicit := c.Value[:len(c.Value)-len(magnitude)]; explicit != "" && explicit != sign {
+		log.Warnf("constant %s has value %s of the opposite sign, using %s%s\n", c.Name, c.Value, sign, magnitude)
+	}
+
+	c.Value = sign + magnitude
+	return c
+}
+
+// stripConstants returns a schedule with any inline constant definitions
+// blanked out, preserving line numbers for the parser's error messages.
+func stripConstants(alst []byte) []byte {
+	var buf bytes.Buffer
+
+	defining := false
+	alstScanner := bufio.NewScanner(bytes.NewReader(alst))
+	for alstScanner.Scan() {
+		line := alstScanner.Text()
+
+		// Definitions may continue onto following lines until a semicolon.
+		if defining || constStartRe.MatchString(line) {
+			defining = !strings.HasSuffix(strings.TrimSpace(line), ";")
+			line = ""
+		}
+
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
+
+// mergeConstants returns the constants of each list in order, skipping names
+// already defined by an earlier one.
+func mergeConstants(lists ...[]Constant) (merged []Constant) {
+	seen := map[string]bool{}
+	for _, constants := range lists {
+		for _, c := range constants {
+			if seen[c.Name] {
+				continue
+			}
+			seen[c.Name] = true
+			merged = append(merged, c)
+		}
+	}
+
+	return merged
+}
+
+// overrideConstants returns the program's constants with any values
+// overridden by the options substituted.
+func (p Program) overrideConstants(name string) []Constant {
+	constants := make([]Constant, len(p.Constants))
+	found := map[string]bool{}
+	for idx, c := range p.Constants {
+		if value, ok := p.Options.Constants[c.Name]; ok {
+			c.Value = value
+			found[c.Name] = true
+		}
+		constants[idx] = c
+	}
+
+	for override := range p.Options.Constants {
+		if !found[override] {
+			log.Warnf("%s: override for undefined constant %s\n", name, override)
+		}
+	}
+
+	return constants
+}
+
+// referencedConstants returns the program's constants that its statements
+// reference, in order.
+func (p Program) referencedConstants() (constants []Constant) {
+	referenced := map[string]bool{}
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			referenced[id] = true
+		}
+	}
+
+	for _, c := range p.Constants {
+		if referenced[c.Name] {
+			constants = append(constants, c)
+		}
+	}
+
+	return constants
+}
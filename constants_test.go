@@ -0,0 +1,181 @@
+package genfft
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConstBlock(t *testing.T) {
+	cout := `     DVK(KP866025403, +0.866025403784438646763723170752936183471402627);
+     DVK(KP500000000, +0.500000000000000000000000000000000000000000000);
+     DK(KP866025403, +0.866025403784438646763723170752936183471402627);
+     E T1, T2, T3;
+`
+
+	got := fmt.Sprintf("%#v", ConstBlock(ParseConstants(strings.NewReader(cout))))
+	want := `const (
+	KP500000000 = +0.500000000000000000000000000000000000000000000
+	KP866025403 = +0.866025403784438646763723170752936183471402627
+)`
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseConstantsContinuation(t *testing.T) {
+	cout := `     DVK(KP866025403,
+          +0.866025403784438646763723170752936183471402627);
+     DVK(
+          KP500000000, +0.500000000000000000000000000000000000000000000);
+     DK(KP559016994, +0.559016994374947424102293417182819058860154590);
+`
+
+	got := ParseConstants(strings.NewReader(cout))
+	want := []Constant{
+		{"KP866025403", "+0.866025403784438646763723170752936183471402627"},
+		{"KP500000000", "+0.500000000000000000000000000000000000000000000"},
+		{"KP559016994", "+0.559016994374947424102293417182819058860154590"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d constants, want %d: %+v", len(got), len(want), got)
+	}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("constant %d: got %+v, want %+v", idx, got[idx], want[idx])
+		}
+	}
+}
+
+func TestOverrideConstants(t *testing.T) {
+	cout := `DVK(KP707106781, +0.707106781186547524400844362104849039284835938);
+DVK(KP1_414213562, +1.414213562373095048801688724209698078569671875);
+`
+	alst := `(:= T1 xi[0])
+(:= T2 xi[1])
+(:= xo[0] (* KP1_414213562 T1))
+(:= xo[1] (* KP707106781 T2))
+`
+	prefix := writeSchedule(t, t.TempDir(), "cmplx_2", alst, cout)
+
+	dft := Dft{Prefix: prefix, Func: "DftCmplx2", Options: Options{
+		Constants: map[string]string{"KP1_414213562": "+1.4142"},
+	}}
+	src := mustGenerate(t, dft).GoString()
+
+	if !strings.Contains(src, "KP1_414213562 = +1.4142\n") {
+		t.Errorf("override not emitted:\n%s", src)
+	}
+	if !strings.Contains(src, "KP707106781   = +0.707106781186547524400844362104849039284835938") {
+		t.Errorf("constant without override changed:\n%s", src)
+	}
+}
+
+func TestInlineConstants(t *testing.T) {
+	dir := t.TempDir()
+	separate := Dft{Prefix: copyTestdata(t, dir, "cmplx_3"), Func: "DftCmplx3"}
+
+	alst, err := os.ReadFile(separate.Prefix + ".alst")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Define the constants inline, one wrapped across lines, and drop the
+	// separate C output.
+	inline := `DVK(KP500000000, +0.500000000000000000000000000000000000000000000);
+DVK(KP866025403,
+    +0.866025403784438646763723170752936183471402627);
+` + string(alst)
+
+	prefix := filepath.Join(dir, "inline")
+	if err := os.WriteFile(prefix+".alst", []byte(inline), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := mustGenerate(t, Dft{Prefix: prefix, Func: "DftCmplx3"}).GoString()
+	if want := mustGenerate(t, separate).GoString(); got != want {
+		t.Fatalf("inline constants generated:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Without inline constants the C output is still required.
+	if err := os.WriteFile(prefix+".alst", alst, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Dft{Prefix: prefix, Func: "DftCmplx3"}).Parse(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected missing C output error, got %v", err)
+	}
+}
+
+func TestSignedConstants(t *testing.T) {
+	cout := `DK(KN707106781, -0.707106781186547524400844362104849039284835938);
+DK(KN866025403, 0.866025403784438646763723170752936183471402627);
+DK(KP500000000, -0.500000000000000000000000000000000000000000000);
+DK(KP559016994, +0.559016994374947424102293417182819058860154590);
+DK(SCALE, -1.0);
+`
+
+	got := ParseConstants(strings.NewReader(cout))
+	want := []Constant{
+		{"KN707106781", "-0.707106781186547524400844362104849039284835938"},
+		{"KN866025403", "-0.866025403784438646763723170752936183471402627"},
+		{"KP500000000", "+0.500000000000000000000000000000000000000000000"},
+		{"KP559016994", "+0.559016994374947424102293417182819058860154590"},
+		{"SCALE", "-1.0"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d constants, want %d: %+v", len(got), len(want), got)
+	}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("constant %d: got %+v, want %+v", idx, got[idx], want[idx])
+		}
+	}
+
+	// A KN constant referenced by a schedule negates its operand.
+	alst := `(:= T1 xi[0])
+(:= T2 xi[1])
+(:= xo[0] (+ T1 T2))
+(:= xo[1] (* KN707106781 (+ T1 (- T2))))
+`
+	kn := "DK(KN707106781, -0.707106781186547524400844362104849039284835938);\n"
+	src := mustGenerate(t, Dft{Prefix: writeSchedule(t, t.TempDir(), "cmplx_2", alst, kn), Func: "DftCmplx2"}).GoString()
+	for _, line := range []string{
+		"KN707106781 = -0.707106781186547524400844362104849039284835938",
+		"xo[1] = KN707106781 * (T1 - T2)",
+	} {
+		if !strings.Contains(src, line) {
+			t.Errorf("missing %q in:\n%s", line, src)
+		}
+	}
+}
+
+func TestPruneConstants(t *testing.T) {
+	alst := `(:= T1 xi[0])
+(:= T2 xi[1])
+(:= xo[0] (+ T1 T2))
+(:= xo[1] (* KP500000000 (- T1 T2)))
+`
+	cout := `DVK(KP866025403, +0.866025403784438646763723170752936183471402627);
+DVK(KP500000000, +0.500000000000000000000000000000000000000000000);
+DVK(KP707106781, +0.707106781186547524400844362104849039284835938);
+`
+	prefix := writeSchedule(t, t.TempDir(), "pruned", alst, cout)
+
+	src := mustGenerate(t, Dft{Prefix: prefix, Func: "DftCmplx2"}).GoString()
+
+	// Neither the unreferenced constants nor the unused imaginary constant
+	// are declared.
+	want := "\tconst (\n\t\tKP500000000 = +0.500000000000000000000000000000000000000000000\n\t)\n"
+	if !strings.Contains(src, want) {
+		t.Errorf("expected only KP500000000 declared:\n%s", src)
+	}
+	for _, pruned := range []string{"KP866025403", "KP707106781", "1i"} {
+		if strings.Contains(src, pruned) {
+			t.Errorf("%s wasn't pruned:\n%s", pruned, src)
+		}
+	}
+}
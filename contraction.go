@@ -0,0 +1,177 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Operators introduced by contraction control, lowered like any custom
+// operator. fmaOp fuses a product and a sum with a single rounding. The
+// rounding operators convert their operand to its own type, which the go spec
+// guarantees rounds it and so prevents fusing it with other operations.
+const (
+	fmaOp        = "FMA"
+	roundFloatOp = "ROUND64"
+	roundCmplxOp = "ROUND128"
+)
+
+func init() {
+	RegisterLowering(fmaOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Qual("math", "FMA").Call(operands...)
+	})
+	RegisterLowering(roundFloatOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Float64().Call(operands...)
+	})
+	RegisterLowering(roundCmplxOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Complex128().Call(operands...)
+	})
+}
+
+// product reports whether e is a product, or a negated one, and returns it.
+func (e Expr) product() (prod Expr, negated, ok bool) {
+	if e.Op == "-" && len(e.Sub) == 1 {
+		prod, negated = e.Sub[0], true
+	} else {
+		prod = e
+	}
+	return prod, negated, prod.Op == "*" && len(prod.Sub) > 1
+}
+
+// contract returns the program's statements with every sum of products
+// computed by math.FMA, so results don't depend on whether the compiler
+// fuses them.
+func (p Program) contract(name string) ([]Expr, error) {
+	if !p.Float() {
+		return nil, fmt.Errorf("%s: fma contraction requires a float schedule", name)
+	}
+
+	var fuse func(e Expr) Expr
+	fuse = func(e Expr) Expr {
+		if e.Ident != "" {
+			return e
+		}
+
+		subs := make([]Expr, len(e.Sub))
+		for idx, sub := range e.Sub {
+			subs[idx] = fuse(sub)
+		}
+		e = Expr{Pos: e.Pos, Op: e.Op, Sub: subs}
+
+		// Differences are sums of the first term and the negated rest, so
+		// a*b - c fuses as math.FMA(a, b, -c).
+		if e.Op == "-" && len(subs) > 1 {
+			terms := []Expr{subs[0]}
+			for _, sub := range subs[1:] {
+				terms = append(terms, Expr{Op: "-", Sub: []Expr{sub}})
+			}
+			subs = terms
+			e = Expr{Pos: e.Pos, Op: "+", Sub: subs}
+		}
+		if e.Op != "+" || len(subs) < 2 {
+			return e
+		}
+
+		var products, others []Expr
+		for _, sub := range subs {
+			if _, _, ok := sub.product(); ok {
+				products = append(products, sub)
+			} else {
+				others = append(others, sub)
+			}
+		}
+		if len(products) == 0 {
+			return e
+		}
+
+		// Accumulate products onto the sum of the remaining terms.
+		var acc Expr
+		switch len(others) {
+		case 0:
+			acc, products = products[len(products)-1], products[:len(products)-1]
+		case 1:
+			acc = others[0]
+		default:
+			acc = Expr{Op: "+", Sub: others}
+		}
+
+		for _, sub := range products {
+			prod, negated, _ := sub.product()
+			a, b := prod.Sub[0], prod.Sub[1]
+			if len(prod.Sub) > 2 {
+				b = Expr{Op: "*", Sub: prod.Sub[1:]}
+			}
+			if negated {
+				a = Expr{Op: "-", Sub: []Expr{a}}
+			}
+			acc = Expr{Op: fmaOp, Sub: []Expr{a, b, acc}}
+		}
+		return acc
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = fuse(expr)
+	}
+	return statements, nil
+}
+
+// roundProducts returns the program's statements with every product rounded
+// before it's used. Products within sums are computed into intermediate
+// variables first, so each addition clearly operates on rounded values.
+func (p Program) roundProducts() []Expr {
+	roundOp := roundCmplxOp
+	if p.Float() {
+		roundOp = roundFloatOp
+	}
+
+	temps := temporaries(p.Statements)
+	next := 0
+	intermediate := func() string {
+		for {
+			next++
+			if name := fmt.Sprintf("M%d", next); !temps[name] {
+				return name
+			}
+		}
+	}
+
+	var statements []Expr
+
+	// round wraps products in e with the rounding operator, hoisting those
+	// within sums into intermediate variables.
+	var round func(e Expr, inSum bool) Expr
+	round = func(e Expr, inSum bool) Expr {
+		if e.Ident != "" {
+			return e
+		}
+
+		// Negations pass through whether they're within a sum.
+		sum := e.additive() || (inSum && len(e.Sub) == 1)
+		subs := make([]Expr, len(e.Sub))
+		for idx, sub := range e.Sub {
+			subs[idx] = round(sub, sum)
+		}
+		e = Expr{Pos: e.Pos, Op: e.Op, Sub: subs}
+		if e.Op != "*" || len(e.Sub) < 2 {
+			return e
+		}
+
+		rounded := Expr{Op: roundOp, Sub: []Expr{e}}
+		if !inSum {
+			return rounded
+		}
+
+		m := Expr{Ident: intermediate()}
+		statements = append(statements, Expr{Pos: e.Pos, Op: ":=", Sub: []Expr{m, rounded}})
+		return m
+	}
+
+	for _, expr := range p.Statements {
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			expr = Expr{Pos: expr.Pos, Op: ":=", Sub: []Expr{expr.Sub[0], round(expr.Sub[1], false)}}
+		}
+		statements = append(statements, expr)
+	}
+	return statements
+}
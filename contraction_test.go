@@ -0,0 +1,138 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestNoContraction(t *testing.T) {
+	dft := Dft{
+		Prefix:  copyTestdata(t, t.TempDir(), "cmplx_3"),
+		Func:    "DftCmplx3",
+		Options: Options{Contraction: "none"},
+	}
+
+	src := mustGenerate(t, dft).GoString()
+	for _, line := range []string{
+		"T6 := complex128(I * complex128(KP866025403*(T3-T2)))",
+		"M1 := complex128(KP500000000 * T4)",
+		"T5 := T1 - M1",
+	} {
+		if !strings.Contains(src, line) {
+			t.Errorf("missing %q in:\n%s", line, src)
+		}
+	}
+}
+
+func TestContraction(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8_none.go": generateNaive(t, 8, Dft{Func: "DftCmplx8None", Options: Options{Contraction: "none"}}),
+		"float_8_none.go": generateNaive(t, 8, Dft{Func: "DftFloat8None", Options: Options{Contraction: "none"}}),
+		"float_8_fma.go":  generateNaive(t, 8, Dft{Func: "DftFloat8FMA", Options: Options{Contraction: "fma"}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func checkFloat(t *testing.T, name string, dft func(ri, ii, ro, io []float64), xi, want []complex128) {
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	dft(ri, ii, ri, ii)
+
+	got := make([]complex128, 8)
+	for idx := range got {
+		got[idx] = complex(ri[idx], ii[idx])
+	}
+	if err := dftError(got, want); err > 1e-13 {
+		t.Fatalf("%s: error %g", name, err)
+	}
+}
+
+func TestContraction(t *testing.T) {
+	xi := randCmplx(8)
+	want := append([]complex128(nil), xi...)
+	naiveDFT(want, -1.0)
+
+	xo := make([]complex128, 8)
+	DftCmplx8None(xi, xo)
+	if err := dftError(xo, want); err > 1e-13 {
+		t.Fatalf("DftCmplx8None: error %g", err)
+	}
+
+	checkFloat(t, "DftFloat8None", DftFloat8None, xi, want)
+	checkFloat(t, "DftFloat8FMA", DftFloat8FMA, xi, want)
+}
+`)
+}
+
+func TestContractDifference(t *testing.T) {
+	prog := Program{Statements: []Expr{
+		parseExpr(t, "(:= ro[0] (- (* K ri[0]) ii[0]))"),
+		parseExpr(t, "(:= io[0] (- ii[0] (* K ri[0])))"),
+	}}
+
+	statements, err := prog.contract("DftFloat1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for idx, want := range []string{
+		"ro[0] = math.FMA(K, ri[0], -ii[0])",
+		"io[0] = math.FMA(-K, ri[0], ii[0])",
+	} {
+		if got := fmt.Sprintf("%#v", statements[idx].Gen()); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+}
+
+func TestContractionError(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_13.go":     generateNaive(t, 13, Dft{Func: "DftFloat13"}),
+		"float_13_fma.go": generateNaive(t, 13, Dft{Func: "DftFloat13FMA", Options: Options{Contraction: "fma"}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+// floatError returns the mean error of a float transform over many random
+// inputs.
+func floatError(dft func(ri, ii, ro, io []float64)) (mean float64) {
+	const trials = 1000
+	for trial := 0; trial < trials; trial++ {
+		xi := randCmplx(13)
+		want := append([]complex128(nil), xi...)
+		naiveDFT(want, -1.0)
+
+		ri, ii := make([]float64, 13), make([]float64, 13)
+		for idx, x := range xi {
+			ri[idx], ii[idx] = real(x), imag(x)
+		}
+		dft(ri, ii, ri, ii)
+
+		got := make([]complex128, 13)
+		for idx := range got {
+			got[idx] = complex(ri[idx], ii[idx])
+		}
+		mean += dftError(got, want) / trials
+	}
+	return mean
+}
+
+func TestContractionError(t *testing.T) {
+	unfused, fused := floatError(DftFloat13), floatError(DftFloat13FMA)
+	t.Logf("mean error without fma %g, with fma %g", unfused, fused)
+
+	if fused > unfused*1.05 {
+		t.Fatalf("fma error %g exceeds unfused error %g", fused, unfused)
+	}
+}
+`)
+}
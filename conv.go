@@ -0,0 +1,84 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genConvStep renders a wrapper computing one step of fast convolution: the
+// forward transform of a block, its pointwise product with a kernel's
+// spectrum, and the normalized inverse transform of the product. Transforms
+// with a fixed direction compute the inverse by conjugating the product
+// before and after the forward transform.
+func (p Program) genConvStep(f *jen.File, name string) error {
+	if p.Float() {
+		return fmt.Errorf("%s: convolution steps require a complex schedule", name)
+	}
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: convolution steps require a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	conv := name + "ConvStep"
+
+	// call renders a transform of xi into xo in the given direction.
+	call := func(xi, xo jen.Code, sign int) *jen.Statement {
+		args := []jen.Code{xi, xo}
+		if p.Options.OutputBase {
+			args = append(args, jen.Lit(0))
+		}
+		if p.Options.RuntimeSign {
+			args = append(args, jen.Lit(sign))
+		}
+		if p.Options.SignMultiplier {
+			args = append(args, jen.Lit(float64(sign)))
+		}
+		return p.callTransform(name, nil, args...)
+	}
+
+	k := jen.Id("k")
+	y := jen.Id("y").Index(jen.Empty(), jen.Empty())
+	conj := jen.Qual("math/cmplx", "Conj")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes the circular convolution of %d samples in x with the kernel\nwhose forward transform is kernelSpectrum, writing the result to out.",
+		conv, n,
+	))
+	f.Func().Id(conv).Params(
+		jen.List(jen.Id("x"), jen.Id("kernelSpectrum"), jen.Id("out")).Index().Complex128(),
+	).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		g.Var().Id("y").Index(jen.Lit(n)).Complex128()
+		g.Add(call(jen.Id("x"), y, -1))
+		g.Line()
+
+		product := jen.Id("y").Index(k).Op("*").Id("kernelSpectrum").Index(k)
+		if p.Options.RuntimeSign || p.Options.SignMultiplier {
+			g.For(k.Clone().Op(":=").Range().Id("y")).Block(
+				jen.Id("y").Index(k).Op("=").Add(product),
+			)
+			g.Add(call(y.Clone(), jen.Id("out"), 1))
+			g.Line()
+
+			g.For(k.Clone().Op(":=").Range().Id("out").Index(jen.Empty(), jen.Lit(n))).Block(
+				jen.Id("out").Index(k).Op("/=").Lit(float64(n)),
+			)
+			g.Add(p.wrapperReturn())
+			return
+		}
+
+		g.For(k.Clone().Op(":=").Range().Id("y")).Block(
+			jen.Id("y").Index(k).Op("=").Add(conj.Clone().Call(product)),
+		)
+		g.Add(call(y.Clone(), jen.Id("out"), 1))
+		g.Line()
+
+		g.For(k.Clone().Op(":=").Range().Id("out").Index(jen.Empty(), jen.Lit(n))).Block(
+			jen.Id("out").Index(k).Op("=").Add(conj.Clone().Call(jen.Id("out").Index(k))).Op("/").Lit(float64(n)),
+		)
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
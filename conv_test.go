@@ -0,0 +1,51 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestConvStep(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{ConvStep: true}}),
+		"cmplx_5.go": generateNaive(t, 5, Dft{Func: "DftCmplx5", Options: Options{ConvStep: true, RuntimeSign: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func checkConvStep(t *testing.T, name string, n int, conv func(x, kernelSpectrum, out []complex128)) {
+	x, h := randCmplx(n), randCmplx(n)
+
+	// Convolve directly.
+	want := make([]complex128, n)
+	for i := range want {
+		for j := range x {
+			want[i] += x[j] * h[(i-j+n)%n]
+		}
+	}
+
+	spectrum := append([]complex128(nil), h...)
+	naiveDFT(spectrum, -1.0)
+
+	out := make([]complex128, n)
+	conv(x, spectrum, out)
+	if err := dftError(out, want); err > 1e-13 {
+		t.Fatalf("%s: error %g", name, err)
+	}
+
+	// The output may overwrite the input.
+	conv(x, spectrum, x)
+	if err := dftError(x, want); err > 1e-13 {
+		t.Fatalf("%s in-place: error %g", name, err)
+	}
+}
+
+func TestConvStep(t *testing.T) {
+	checkConvStep(t, "DftCmplx8ConvStep", 8, DftCmplx8ConvStep)
+	checkConvStep(t, "DftCmplx5ConvStep", 5, DftCmplx5ConvStep)
+}
+`)
+}
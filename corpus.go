@@ -0,0 +1,112 @@
+package genfft
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// sectionRe matches the marker starting each schedule in a corpus, naming
+// the function it generates and the transform's size.
+var sectionRe = regexp.MustCompile(`^;;\s*([A-Za-z_]\w*)\s+(\d+)\s*$`)
+
+// Section is one schedule in a corpus.
+type Section struct {
+	Name string
+	Size int
+}
+
+// Sections returns the sections of a corpus in order.
+func Sections(corpus []byte) (sections []Section, err error) {
+	corpusScanner := bufio.NewScanner(bytes.NewReader(corpus))
+	for line := 1; corpusScanner.Scan(); line++ {
+		text := corpusScanner.Text()
+
+		m := sectionRe.FindStringSubmatch(text)
+		switch {
+		case m != nil:
+			size, _ := strconv.Atoi(m[2])
+			sections = append(sections, Section{m[1], size})
+		case len(sections) == 0 && strings.TrimSpace(text) != "":
+			return nil, fmt.Errorf("line %d: schedule before the first section marker", line)
+		}
+	}
+
+	return sections, nil
+}
+
+// corpusSection returns a corpus with every line outside the named section
+// blanked out, keeping line numbers for the parser's error messages, along
+// with the size the section's marker declares.
+func corpusSection(corpus []byte, name string) ([]byte, int, error) {
+	var (
+		buf   bytes.Buffer
+		size  int
+		found bool
+	)
+
+	inSection := false
+	corpusScanner := bufio.NewScanner(bytes.NewReader(corpus))
+	for corpusScanner.Scan() {
+		line := corpusScanner.Text()
+
+		if m := sectionRe.FindStringSubmatch(line); m != nil {
+			inSection = m[1] == name
+			if inSection {
+				if found {
+					return nil, 0, fmt.Errorf("section %s is repeated", name)
+				}
+				found = true
+				size, _ = strconv.Atoi(m[2])
+			}
+			line = ""
+		}
+
+		if inSection {
+			buf.WriteString(line)
+		}
+		buf.WriteByte('\n')
+	}
+
+	if !found {
+		return nil, 0, fmt.Errorf("no section %s", name)
+	}
+	return buf.Bytes(), size, nil
+}
+
+// expandCorpora replaces each transform generated from a corpus with one per
+// section, named after the section and written alongside the corpus.
+func expandCorpora(dfts []Dft) (expanded []Dft, err error) {
+	for _, dft := range dfts {
+		if !dft.Corpus {
+			expanded = append(expanded, dft)
+			continue
+		}
+
+		corpus, err := os.ReadFile(dft.Prefix + ".alst")
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile: %w", err)
+		}
+
+		sections, err := Sections(corpus)
+		if err != nil {
+			return nil, fmt.Errorf("%s.alst: %w", dft.Prefix, err)
+		}
+
+		for _, s := range sections {
+			expanded = append(expanded, Dft{
+				Prefix:  filepath.Join(filepath.Dir(dft.Prefix), strings.ToLower(s.Name)),
+				Func:    s.Name,
+				Options: dft.Options,
+				corpus:  dft.Prefix,
+			})
+		}
+	}
+
+	return expanded, nil
+}
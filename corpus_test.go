@@ -0,0 +1,82 @@
+package genfft
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestCorpus(t *testing.T) {
+	dir := t.TempDir()
+
+	var corpus strings.Builder
+	for _, n := range []int{2, 4} {
+		alst, _ := naiveSchedule(n, false)
+		fmt.Fprintf(&corpus, ";; DftCmplx%d %d\n", n, n)
+		corpus.WriteString(alst)
+	}
+
+	prefix := filepath.Join(dir, "corpus")
+	if err := os.WriteFile(prefix+".alst", []byte(corpus.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dfts, err := expandCorpora([]Dft{{Prefix: prefix, Corpus: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfts) != 2 || dfts[0].Func != "DftCmplx2" || dfts[1].Func != "DftCmplx4" {
+		t.Fatalf("expected sections DftCmplx2 and DftCmplx4, got %+v", dfts)
+	}
+
+	files := map[string]*jen.File{}
+	for _, dft := range dfts {
+		files[filepath.Base(dft.Prefix)+".go"] = mustGenerate(t, dft)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestCorpus(t *testing.T) {
+	for n, dft := range map[int]func(xi, xo []complex128){
+		2: DftCmplx2,
+		4: DftCmplx4,
+	} {
+		xi := randCmplx(n)
+		xo := make([]complex128, n)
+		dft(xi, xo)
+
+		naiveDFT(xi, -1.0)
+		if err := dftError(xo, xi); err > 1e-13 {
+			t.Fatalf("%d: error %g", n, err)
+		}
+	}
+}
+`)
+}
+
+func TestCorpusSize(t *testing.T) {
+	alst, _ := naiveSchedule(2, false)
+	corpus := []byte(";; DftCmplx2 3\n" + alst)
+
+	prefix := filepath.Join(t.TempDir(), "corpus")
+	if err := os.WriteFile(prefix+".alst", corpus, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Dft{Prefix: prefix, Func: "DftCmplx2", corpus: prefix}.Parse()
+	if err == nil || !strings.Contains(err.Error(), "declares size 3") {
+		t.Fatalf("expected size mismatch, got %v", err)
+	}
+}
+
+func TestSections(t *testing.T) {
+	if _, err := Sections([]byte("(:= T1 xi[0])\n;; DftCmplx1 1\n")); err == nil {
+		t.Fatal("expected error for schedule before the first marker")
+	}
+}
@@ -0,0 +1,62 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestCrossSpectrum(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{CrossSpectrum: true}}),
+		"float_5.go": generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{CrossSpectrum: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math/cmplx"
+	"testing"
+)
+
+// realSpectrum returns the naive transform of n random real samples.
+func realSpectrum(n int) (x []float64, spectrum []complex128) {
+	spectrum = randCmplx(n)
+	x = make([]float64, n)
+	for k := range spectrum {
+		x[k] = real(spectrum[k])
+		spectrum[k] = complex(x[k], 0)
+	}
+	naiveDFT(spectrum, -1.0)
+	return x, spectrum
+}
+
+func checkCrossSpectrum(t *testing.T, name string, n int, cross func(a, b []float64, out []complex128)) {
+	a, fa := realSpectrum(n)
+	b, fb := realSpectrum(n)
+
+	out := make([]complex128, n/2+1)
+	cross(a, b, out)
+
+	for k := range out {
+		want := fa[k] * cmplx.Conj(fb[k])
+		if cmplx.Abs(out[k]-want) > 1e-12*(1+cmplx.Abs(want)) {
+			t.Fatalf("%s: bin %d: %v != %v", name, k, out[k], want)
+		}
+	}
+
+	// The cross-spectrum of a signal with itself is its power spectrum.
+	cross(a, a, out)
+	for k := range out {
+		if want := real(fa[k])*real(fa[k]) + imag(fa[k])*imag(fa[k]); cmplx.Abs(out[k]-complex(want, 0)) > 1e-12*(1+want) {
+			t.Fatalf("%s: auto-spectrum bin %d: %v != %g", name, k, out[k], want)
+		}
+	}
+}
+
+func TestCrossSpectrum(t *testing.T) {
+	checkCrossSpectrum(t, "DftCmplx8CrossSpectrum", 8, DftCmplx8CrossSpectrum)
+	checkCrossSpectrum(t, "DftFloat5CrossSpectrum", 5, DftFloat5CrossSpectrum)
+}
+`)
+}
@@ -0,0 +1,204 @@
+package genfft
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tempRe matches the temporaries FFTW's generator names.
+var tempRe = regexp.MustCompile(`^T(\d+)$`)
+
+// commonSubexpressions counts the operations appearing in the right sides of
+// the statements, keyed by their text, which records the operator and its
+// operands in order. Repeats aren't descended into, so operations counted
+// more than once appear outside any other repeated operation.
+func commonSubexpressions(statements []Expr) map[string]int {
+	count := map[string]int{}
+
+	var walk func(e Expr)
+	walk = func(e Expr) {
+		if !e.binary() {
+			for _, sub := range e.Sub {
+				walk(sub)
+			}
+			return
+		}
+
+		key := e.String()
+		count[key]++
+		if count[key] > 1 {
+			return
+		}
+		for _, sub := range e.Sub {
+			walk(sub)
+		}
+	}
+
+	for _, expr := range statements {
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			walk(expr.Sub[1])
+		}
+	}
+
+	return count
+}
+
+// eliminateCommon assigns each operation appearing more than once to a new
+// temporary before its first use, and replaces later appearances with it. A
+// temporary is only reused while the identifiers it reads are unchanged, so
+// writes to outputs, which may alias the inputs, and reassigned temporaries
+// are never moved past the reads depending on them.
+func (p Program) eliminateCommon() []Expr {
+	count := commonSubexpressions(p.Statements)
+
+	// New temporaries are numbered after the schedule's own.
+	first := 0
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			if m := tempRe.FindStringSubmatch(id); m != nil {
+				if k, _ := strconv.Atoi(m[1]); k > first {
+					first = k
+				}
+			}
+		}
+	}
+
+	type available struct {
+		temp    string
+		reads   map[string]bool
+		indexed bool
+	}
+	avail := map[string]available{}
+
+	next := first
+	var statements []Expr
+	var rewrite func(e Expr) Expr
+	rewrite = func(e Expr) Expr {
+		if e.Ident != "" {
+			return e
+		}
+
+		key := e.String()
+		if a, ok := avail[key]; ok && e.binary() {
+			return Expr{Pos: e.Pos, Ident: a.temp}
+		}
+
+		sub := make([]Expr, len(e.Sub))
+		for idx := range e.Sub {
+			sub[idx] = rewrite(e.Sub[idx])
+		}
+		e = Expr{Pos: e.Pos, Op: e.Op, Sub: sub}
+
+		if !e.binary() || count[key] < 2 {
+			return e
+		}
+
+		next++
+		a := available{temp: "T" + strconv.Itoa(next), reads: map[string]bool{}}
+		for _, id := range e.Idents() {
+			a.reads[id] = true
+			a.indexed = a.indexed || strings.HasSuffix(id, "]")
+		}
+		avail[key] = a
+
+		statements = append(statements, Expr{Pos: e.Pos, Op: ":=", Sub: []Expr{{Pos: e.Pos, Ident: a.temp}, e}})
+		return Expr{Pos: e.Pos, Ident: a.temp}
+	}
+
+	for _, expr := range p.Statements {
+		if expr.Op != ":=" || len(expr.Sub) != 2 {
+			statements = append(statements, expr)
+			continue
+		}
+
+		statements = append(statements, Expr{
+			Pos: expr.Pos,
+			Op:  expr.Op,
+			Sub: []Expr{expr.Sub[0], rewrite(expr.Sub[1])},
+		})
+
+		// Forget temporaries whose operands this statement may change.
+		// Indexed writes may alias any indexed read.
+		lhs := expr.Sub[0].Ident
+		indexed := strings.HasSuffix(lhs, "]")
+		for key, a := range avail {
+			if a.reads[lhs] || indexed && a.indexed {
+				delete(avail, key)
+			}
+		}
+	}
+
+	return inlineSingleUse(statements, first)
+}
+
+// inlineSingleUse substitutes temporaries numbered after first which are read
+// only once back into the statement reading them, since a temporary can be
+// forgotten before its operation repeats. The remaining new temporaries are
+// renumbered in order. Each is assigned just before the statement first
+// reading it, so substituting it moves no read past a write.
+func inlineSingleUse(statements []Expr, first int) []Expr {
+	isNew := func(id string) bool {
+		m := tempRe.FindStringSubmatch(id)
+		if m == nil {
+			return false
+		}
+		k, _ := strconv.Atoi(m[1])
+		return k > first
+	}
+
+	reads := map[string]int{}
+	for _, expr := range statements {
+		read := expr
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			read = expr.Sub[1]
+		}
+		for _, id := range read.Idents() {
+			reads[id]++
+		}
+	}
+
+	inlined := map[string]Expr{}
+	renamed := map[string]string{}
+	next := first
+
+	var substitute func(e Expr) Expr
+	substitute = func(e Expr) Expr {
+		if e.Ident != "" {
+			if sub, ok := inlined[e.Ident]; ok {
+				return sub
+			}
+			if name, ok := renamed[e.Ident]; ok {
+				return Expr{Pos: e.Pos, Ident: name}
+			}
+			return e
+		}
+
+		sub := make([]Expr, len(e.Sub))
+		for idx := range e.Sub {
+			sub[idx] = substitute(e.Sub[idx])
+		}
+		return Expr{Pos: e.Pos, Op: e.Op, Sub: sub}
+	}
+
+	var kept []Expr
+	for _, expr := range statements {
+		temp, ok := expr.Temporary()
+		if !ok || !isNew(temp) {
+			kept = append(kept, substitute(expr))
+			continue
+		}
+
+		rhs := substitute(expr.Sub[1])
+		if reads[temp] < 2 {
+			inlined[temp] = rhs
+			continue
+		}
+
+		next++
+		renamed[temp] = "T" + strconv.Itoa(next)
+		kept = append(kept, Expr{Pos: expr.Pos, Op: ":=", Sub: []Expr{{Pos: expr.Pos, Ident: renamed[temp]}, rhs}})
+	}
+
+	return kept
+}
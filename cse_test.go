@@ -0,0 +1,101 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestEliminateCommon(t *testing.T) {
+	for _, fn := range []string{"DftCmplx16", "DftFloat16"} {
+		alst, cout := naiveSchedule(16, fn[3] == 'F')
+		dft := Dft{Prefix: writeSchedule(t, t.TempDir(), fn, alst, cout), Func: fn}
+
+		prog := mustParse(t, dft)
+		eliminated := *prog
+		eliminated.Statements = prog.eliminateCommon()
+
+		before, after := prog.Flops(), eliminated.Flops()
+		t.Logf("%s: %d flops, %d after eliminating common subexpressions", fn, before, after)
+		if after >= before {
+			t.Errorf("%s: expected fewer than %d flops, got %d", fn, before, after)
+		}
+
+		// Eliminating again finds nothing left to share.
+		again := eliminated
+		again.Statements = eliminated.eliminateCommon()
+		if len(again.Statements) != len(eliminated.Statements) {
+			t.Errorf("%s: second pass added %d statements", fn, len(again.Statements)-len(eliminated.Statements))
+		}
+	}
+
+	files := map[string]*jen.File{
+		"cmplx_16.go":     generateNaive(t, 16, Dft{Func: "DftCmplx16"}),
+		"cmplx_16_cse.go": generateNaive(t, 16, Dft{Func: "DftCmplx16CSE", Options: Options{CSE: true}}),
+		"float_16_cse.go": generateNaive(t, 16, Dft{Func: "DftFloat16CSE", Options: Options{CSE: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestEliminateCommon(t *testing.T) {
+	xi := randCmplx(16)
+	want := make([]complex128, 16)
+	DftCmplx16(xi, want)
+
+	got := make([]complex128, 16)
+	DftCmplx16CSE(xi, got)
+	if err := dftError(got, want); err > 1e-13 {
+		t.Fatalf("output error %g", err)
+	}
+
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	DftFloat16CSE(ri, ii, ri, ii)
+	for idx := range want {
+		if err := dftError([]complex128{complex(ri[idx], ii[idx])}, want[idx:idx+1]); err > 1e-13 {
+			t.Fatalf("float in-place output error %g at %d", err, idx)
+		}
+	}
+}
+`)
+}
+
+func TestEliminateCommonOrder(t *testing.T) {
+	prog, err := parseSchedule("order.alst", []byte(`(:= T1 xi[1])
+(:= xo[0] (* KP500000000 (+ xi[0] T1)))
+(:= T1 xi[2])
+(:= xo[1] (* KP500000000 (+ xi[0] T1)))
+(:= xo[2] (+ (* KP500000000 xi[3]) (* KP500000000 xi[3])))
+(:= xo[3] (* KP500000000 xi[3]))
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, expr := range prog.eliminateCommon() {
+		lines = append(lines, expr.Gen().GoString())
+	}
+	got := strings.Join(lines, "\n")
+
+	// Reassigning T1 and writing xo[0], which may alias xi[0], both change
+	// the repeated product, so it's computed again. The product of xi[3] is
+	// shared until xo[2] is written.
+	want := strings.Join([]string{
+		"T1 := xi[1]",
+		"xo[0] = KP500000000 * (xi[0] + T1)",
+		"T1 := xi[2]",
+		"xo[1] = KP500000000 * (xi[0] + T1)",
+		"T2 := KP500000000 * xi[3]",
+		"xo[2] = T2 + T2",
+		"xo[3] = KP500000000 * xi[3]",
+	}, "\n")
+	if got != want {
+		t.Fatalf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
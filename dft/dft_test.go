@@ -2,10 +2,10 @@ package dft
 
 import (
 	"fmt"
-	"math"
 	"math/cmplx"
 	"strconv"
 	"testing"
+	"time"
 )
 
 const (
@@ -38,20 +38,6 @@ func dftError(i, j []complex128) float64 {
 	return err / float64(len(i))
 }
 
-func naiveDFT(f []complex128, sign float64) {
-	n := len(f)
-	h := make([]complex128, n)
-	phi := sign * 2.0 * math.Pi / float64(n)
-	for w := 0; w < n; w++ {
-		var t complex128
-		for k := 0; k < n; k++ {
-			t += f[k] * cmplx.Rect(1, phi*float64(k)*float64(w))
-		}
-		h[w] = t
-	}
-	copy(f, h)
-}
-
 type floatDft struct {
 	Size int
 	Fn   func(ri, ii, ro, io []float64)
@@ -88,7 +74,7 @@ func TestFloatDFT(t *testing.T) {
 			}
 
 			naiveOut := stepCmplx(dft.Size)
-			naiveDFT(naiveOut, -1.0)
+			NaiveDFT(naiveOut, false)
 
 			err := dftError(genOut, naiveOut)
 			t.Logf("DFT%d Error: %0.12g", dft.Size, err)
@@ -129,7 +115,7 @@ func TestCmplxDFT(t *testing.T) {
 			dft.Fn(xi, xi)
 
 			naiveOut := stepCmplx(dft.Size)
-			naiveDFT(naiveOut, -1.0)
+			NaiveDFT(naiveOut, false)
 
 			err := dftError(xi, naiveOut)
 			t.Logf("DFT%d Error: %0.3g", dft.Size, err)
@@ -149,7 +135,7 @@ func BenchmarkFloatDFT(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
 			for n := 0; n < b.N; n++ {
-				naiveDFT(ri, -1.0)
+				NaiveDFT(ri, false)
 			}
 		})
 
@@ -160,9 +146,11 @@ func BenchmarkFloatDFT(b *testing.B) {
 			b.SetBytes(int64(dft.Size))
 			b.ReportAllocs()
 			b.ResetTimer()
+			start := time.Now()
 			for n := 0; n < b.N; n++ {
 				dft.Fn(ri, ii, ri, ii)
 			}
+			reportFlops(b, fmt.Sprintf("DftFloat%d", dft.Size), time.Since(start))
 		})
 
 		b.Run(fmt.Sprintf("Out of Place Float DFT N=%d", dft.Size), func(b *testing.B) {
@@ -174,9 +162,11 @@ func BenchmarkFloatDFT(b *testing.B) {
 			b.SetBytes(int64(dft.Size))
 			b.ReportAllocs()
 			b.ResetTimer()
+			start := time.Now()
 			for n := 0; n < b.N; n++ {
 				dft.Fn(ri, ii, ro, io)
 			}
+			reportFlops(b, fmt.Sprintf("DftFloat%d", dft.Size), time.Since(start))
 		})
 	}
 }
@@ -190,7 +180,7 @@ func BenchmarkCmplxDFT(b *testing.B) {
 			b.ReportAllocs()
 			b.ResetTimer()
 			for n := 0; n < b.N; n++ {
-				naiveDFT(ri, -1.0)
+				NaiveDFT(ri, false)
 			}
 		})
 
@@ -200,9 +190,11 @@ func BenchmarkCmplxDFT(b *testing.B) {
 			b.SetBytes(int64(dft.Size))
 			b.ReportAllocs()
 			b.ResetTimer()
+			start := time.Now()
 			for n := 0; n < b.N; n++ {
 				dft.Fn(xi, xi)
 			}
+			reportFlops(b, fmt.Sprintf("DftCmplx%d", dft.Size), time.Since(start))
 		})
 
 		b.Run(fmt.Sprintf("Out of Cmplx Place DFT N=%d", dft.Size), func(b *testing.B) {
@@ -212,9 +204,11 @@ func BenchmarkCmplxDFT(b *testing.B) {
 			b.SetBytes(int64(dft.Size))
 			b.ReportAllocs()
 			b.ResetTimer()
+			start := time.Now()
 			for n := 0; n < b.N; n++ {
 				dft.Fn(xi, xo)
 			}
+			reportFlops(b, fmt.Sprintf("DftCmplx%d", dft.Size), time.Since(start))
 		})
 	}
 }
@@ -0,0 +1,94 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genDirection renders a wrapper around a transform whose direction is
+// chosen at runtime, taking the direction as a Direction rather than a sign.
+// Inverse transforms are normalized by 1/n if NormalizeInverse is set.
+func (p Program) genDirection(f *jen.File, name string) error {
+	if !(p.Options.RuntimeSign || p.Options.SignMultiplier) {
+		return fmt.Errorf("%s: direction wrappers require a runtime sign", name)
+	}
+	strides := p.Strides()
+	if p.Options.NormalizeInverse && len(strides) > 0 {
+		return fmt.Errorf("%s: normalized inverses require a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	direction := name + "Direction"
+
+	args, argType := p.Args()
+	params := []jen.Code{jen.List(args...).Index().Add(argType)}
+	if len(strides) > 0 {
+		params = append(params, strideParams(strides))
+		for _, stride := range strides {
+			args = append(args, jen.Id(stride))
+		}
+	}
+	params = append(params, jen.Id("dir").Id("Direction"))
+	if p.Options.OutputBase {
+		args = append(args, jen.Lit(0))
+	}
+	if p.Options.SignMultiplier {
+		args = append(args, jen.Float64().Call(jen.Id("dir")))
+	} else {
+		args = append(args, jen.Int().Call(jen.Id("dir")))
+	}
+
+	outputs := []string{"xo"}
+	if p.Float() {
+		outputs = []string{"ro", "io"}
+	}
+
+	f.Line()
+	if p.Options.NormalizeInverse {
+		f.Comment(fmt.Sprintf("%s computes %s in the direction dir, scaling the inverse\ntransform by 1/%d.", direction, name, n))
+	} else {
+		f.Comment(fmt.Sprintf("%s computes %s in the direction dir.", direction, name))
+	}
+	f.Func().Id(direction).Params(params...).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		g.Add(p.callTransform(name, nil, args...))
+		if !p.Options.NormalizeInverse {
+			g.Add(p.wrapperReturn())
+			return
+		}
+
+		scale := jen.Lit(1 / float64(n))
+		if !p.Float() {
+			scale = jen.Complex(jen.Lit(1/float64(n)), jen.Lit(0))
+		}
+		g.If(jen.Id("dir").Op("==").Id("Inverse")).BlockFunc(func(g *jen.Group) {
+			g.For(jen.Id("k").Op(":=").Range().Id(outputs[0]).Index(jen.Empty(), jen.Lit(n))).BlockFunc(func(g *jen.Group) {
+				for _, out := range outputs {
+					g.Id(out).Index(jen.Id("k")).Op("*=").Add(scale.Clone())
+				}
+			})
+		})
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
+
+// directionSupport renders the type selecting the direction of transforms
+// with direction wrappers.
+func directionSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("Direction selects between the forward and inverse transform, its value is\nthe sign of the transform's exponent.")
+	f.Type().Id("Direction").Int()
+	f.Line()
+
+	f.Const().Defs(
+		jen.Comment("Forward computes the forward transform."),
+		jen.Id("Forward").Id("Direction").Op("=").Lit(-1),
+		jen.Comment("Inverse computes the inverse transform."),
+		jen.Id("Inverse").Id("Direction").Op("=").Lit(1),
+	)
+
+	return f
+}
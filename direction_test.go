@@ -0,0 +1,66 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestDirection(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_6.go":   generateNaive(t, 6, Dft{Func: "DftCmplx6", Options: Options{Direction: true, RuntimeSign: true}}),
+		"cmplx_8.go":   generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Direction: true, NormalizeInverse: true, SignMultiplier: true}}),
+		"float_5.go":   generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{Direction: true, NormalizeInverse: true, RuntimeSign: true}}),
+		"direction.go": directionSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestDirection(t *testing.T) {
+	xi := randCmplx(6)
+	forward, inverse := make([]complex128, 6), make([]complex128, 6)
+	DftCmplx6Direction(xi, forward, Forward)
+	DftCmplx6Direction(xi, inverse, Inverse)
+
+	want := append([]complex128(nil), xi...)
+	naiveDFT(want, -1.0)
+	if err := dftError(forward, want); err > 1e-13 {
+		t.Fatalf("forward: error %g", err)
+	}
+	want = append(want[:0], xi...)
+	naiveDFT(want, 1.0)
+	if err := dftError(inverse, want); err > 1e-13 {
+		t.Fatalf("inverse: error %g", err)
+	}
+}
+
+func TestNormalizeInverse(t *testing.T) {
+	xi := randCmplx(8)
+	spectrum, xo := make([]complex128, 8), make([]complex128, 8)
+	DftCmplx8Direction(xi, spectrum, Forward)
+	DftCmplx8Direction(spectrum, xo, Inverse)
+	if err := dftError(xo, xi); err > 1e-13 {
+		t.Fatalf("cmplx: round trip error %g", err)
+	}
+
+	ri, ii := make([]float64, 5), make([]float64, 5)
+	for k, x := range randCmplx(5) {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+	sr, si := make([]float64, 5), make([]float64, 5)
+	ro, io := make([]float64, 5), make([]float64, 5)
+	DftFloat5Direction(ri, ii, sr, si, Forward)
+	DftFloat5Direction(sr, si, ro, io, Inverse)
+
+	got, want := make([]complex128, 5), make([]complex128, 5)
+	for k := range got {
+		got[k], want[k] = complex(ro[k], io[k]), complex(ri[k], ii[k])
+	}
+	if err := dftError(got, want); err > 1e-13 {
+		t.Fatalf("float: round trip error %g", err)
+	}
+}
+`)
+}
@@ -0,0 +1,139 @@
+package genfft
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// dispatchCase is a transform called by a dispatch function for its length,
+// and whether it returns an error.
+type dispatchCase struct {
+	n       int
+	name    string
+	returns bool
+}
+
+// dispatchSupport renders, for each package with transforms setting
+// Dispatch, the DftCmplx and DftFloat functions calling the forward transform
+// of the length they're given, returning the error of transforms that return
+// errors. Inverse transforms, those that can't be parsed
+// or rendered, taking anything other than the input and output slices, or
+// repeating another's length, are skipped with a warning.
+func dispatchSupport(dfts []Dft) map[string]*jen.File {
+	cmplx := map[string][]dispatchCase{}
+	float := map[string][]dispatchCase{}
+	seen := map[string]string{}
+
+	for _, dft := range dfts {
+		if !dft.Dispatch {
+			continue
+		}
+		if dft.Inverse {
+			log.Warnf("skipping dispatch to %s: only forward transforms are dispatched\n", dft.Func)
+			continue
+		}
+
+		prog, err := dft.Parse()
+		if err != nil {
+			log.Warnf("skipping dispatch to %s: %+v\n", dft.Func, err)
+			continue
+		}
+
+		sig := fmt.Sprintf("func %s(xi, xo []complex128)", dft.Func)
+		if prog.Float() {
+			sig = fmt.Sprintf("func %s(ri, ii, ro, io []float64)", dft.Func)
+		}
+
+		f, err := prog.Render("dft", dft.Func)
+		if err != nil {
+			log.Warnf("skipping dispatch to %s: %+v\n", dft.Func, err)
+			continue
+		}
+
+		funcs, err := prog.API(f)
+		if err != nil {
+			log.Warnf("skipping dispatch to %s: %+v\n", dft.Func, err)
+			continue
+		}
+		returns := hasSignature(funcs, sig+" error")
+		if !returns && !hasSignature(funcs, sig) {
+			log.Warnf("skipping dispatch to %s: signature isn't %s\n", dft.Func, sig)
+			continue
+		}
+
+		dir := filepath.Dir(dft.Prefix)
+		n := prog.TransformLength()
+		key := fmt.Sprintf("%s %t %d", dir, prog.Float(), n)
+		if prev, ok := seen[key]; ok {
+			log.Warnf("skipping dispatch to %s: length %d is dispatched to %s\n", dft.Func, n, prev)
+			continue
+		}
+		seen[key] = dft.Func
+
+		if prog.Float() {
+			float[dir] = append(float[dir], dispatchCase{n, dft.Func, returns})
+		} else {
+			cmplx[dir] = append(cmplx[dir], dispatchCase{n, dft.Func, returns})
+		}
+	}
+
+	files := map[string]*jen.File{}
+	file := func(dir string) *jen.File {
+		filename := filepath.Join(dir, "dispatch.go")
+		if files[filename] == nil {
+			files[filename] = jen.NewFilePathName("dft", "dft")
+		}
+		return files[filename]
+	}
+
+	for dir, cases := range cmplx {
+		genDispatch(file(dir), "DftCmplx", "complex", []jen.Code{jen.Id("xi"), jen.Id("xo")}, jen.Complex128(), cases)
+	}
+	for dir, cases := range float {
+		genDispatch(file(dir), "DftFloat", "float", []jen.Code{jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")}, jen.Float64(), cases)
+	}
+
+	return files
+}
+
+// genDispatch renders a function calling the transform of length n from
+// cases, returning an error for lengths without one.
+func genDispatch(f *jen.File, name, kind string, args []jen.Code, argType jen.Code, cases []dispatchCase) {
+	sort.Slice(cases, func(i, j int) bool { return cases[i].n < cases[j].n })
+
+	f.Comment(fmt.Sprintf("%s computes the %s transform of length n, returning an error if\nno transform of that length was generated.", name, kind))
+	f.Func().Id(name).Params(
+		jen.Id("n").Int(),
+		jen.List(args...).Index().Add(argType),
+	).Error().Block(
+		jen.Switch(jen.Id("n")).BlockFunc(func(g *jen.Group) {
+			for _, c := range cases {
+				call := jen.Id(c.name).Call(args...)
+				if c.returns {
+					call = jen.Return(call)
+				}
+				g.Case(jen.Lit(c.n)).Block(call)
+			}
+			g.Default().Block(jen.Return(jen.Qual("fmt", "Errorf").Call(
+				jen.Lit(fmt.Sprintf("dft: %s: no transform of length %%d", name)),
+				jen.Id("n"),
+			)))
+		}),
+		jen.Return(jen.Nil()),
+	)
+	f.Line()
+}
+
+// hasSignature reports whether any of funcs has the signature sig.
+func hasSignature(funcs []FuncAPI, sig string) bool {
+	for _, fn := range funcs {
+		if fn.Signature == sig {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package genfft
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestDispatch(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	files := map[string]*jen.File{}
+	for _, tc := range []struct {
+		n   int
+		dft Dft
+	}{
+		// Inverse transforms are skipped, even ahead of the forward one.
+		{2, Dft{Func: "DftCmplxInv2", Options: Options{Dispatch: true, Inverse: true}}},
+		{2, Dft{Func: "DftCmplx2", Options: Options{Dispatch: true}}},
+		{4, Dft{Func: "DftCmplx4", Options: Options{Dispatch: true}}},
+		{3, Dft{Func: "DftFloat3", Options: Options{Dispatch: true}}},
+		// Transforms returning errors are dispatched, returning their errors.
+		{3, Dft{Func: "DftCmplx3", Options: Options{Dispatch: true, ReturnErrors: true}}},
+		// Transforms taking more than their slices are skipped.
+		{8, Dft{Func: "DftCmplx8", Options: Options{Dispatch: true, RuntimeSign: true}}},
+		// As are transforms repeating another's length.
+		{4, Dft{Func: "DftCmplx4Again", Options: Options{Dispatch: true}}},
+		// And transforms not setting dispatch.
+		{5, Dft{Func: "DftCmplx5"}},
+		// And transforms that can't be rendered, without stopping the others.
+		{8, Dft{Func: "DftCmplx8F32", Options: Options{Dispatch: true, Precision: "float32"}}},
+	} {
+		alst, cout := naiveSchedule(tc.n, tc.dft.Func[3] == 'F')
+		tc.dft.Prefix = writeSchedule(t, dir, tc.dft.Func, alst, cout)
+		dfts = append(dfts, tc.dft)
+		if tc.dft.Precision == "" {
+			files[tc.dft.Func+".go"] = mustGenerate(t, tc.dft)
+		}
+	}
+
+	// Nor do transforms whose schedules are missing.
+	dfts = append(dfts, Dft{Prefix: filepath.Join(dir, "missing"), Func: "DftCmplx6", Options: Options{Dispatch: true}})
+
+	files["errors.go"] = errorsSupport("dft")
+
+	support := dispatchSupport(dfts)
+	if len(support) != 1 {
+		t.Fatalf("expected one dispatch file, got %d", len(support))
+	}
+	for _, f := range support {
+		src := f.GoString()
+		for _, skipped := range []string{"DftCmplxInv2", "DftCmplx8", "DftCmplx4Again", "DftCmplx5", "DftCmplx8F32", "DftCmplx6"} {
+			if strings.Contains(src, skipped+"(") {
+				t.Fatalf("dispatch calls %s:\n%s", skipped, src)
+			}
+		}
+		files["dispatch.go"] = f
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDispatch(t *testing.T) {
+	for _, n := range []int{2, 3, 4} {
+		xi := randCmplx(n)
+		xo := make([]complex128, n)
+		if err := DftCmplx(n, xi, xo); err != nil {
+			t.Fatal(err)
+		}
+
+		naiveDFT(xi, -1.0)
+		if e := dftError(xi, xo); e > 1e-12 {
+			t.Errorf("DftCmplx(%d): error %g", n, e)
+		}
+	}
+
+	xi := randCmplx(3)
+	ri, ii := make([]float64, 3), make([]float64, 3)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 3), make([]float64, 3)
+	if err := DftFloat(3, ri, ii, ro, io); err != nil {
+		t.Fatal(err)
+	}
+
+	naiveDFT(xi, -1.0)
+	for idx, x := range xi {
+		if math.Abs(real(x)-ro[idx])+math.Abs(imag(x)-io[idx]) > 1e-12 {
+			t.Errorf("DftFloat(3): output %d is %g%+gi, expected %g", idx, ro[idx], io[idx], x)
+		}
+	}
+
+	for _, n := range []int{5, 8} {
+		if err := DftCmplx(n, make([]complex128, n), make([]complex128, n)); err == nil {
+			t.Errorf("DftCmplx(%d): expected an error", n)
+		}
+	}
+	if err := DftFloat(2, nil, nil, nil, nil); err == nil {
+		t.Error("DftFloat(2): expected an error")
+	}
+
+	want := "dft: DftCmplx3: xo has length 2, need 3"
+	if err := DftCmplx(3, make([]complex128, 3), make([]complex128, 2)); err == nil || err.Error() != want {
+		t.Errorf("DftCmplx(3): got error %v, want %q", err, want)
+	}
+}
+`)
+}
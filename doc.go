@@ -0,0 +1,42 @@
+package genfft
+
+import "fmt"
+
+// funcDoc returns the lines of the transform's doc comment: its length,
+// direction, the slices it reads and writes, how long they must be and
+// whether they may be the same.
+func (p Program) funcDoc(name string) []string {
+	n := p.TransformLength()
+
+	inputs, outputs, kind := []string{"xi"}, []string{"xo"}, "complex"
+	if p.Float() {
+		inputs, outputs, kind = []string{"ri", "ii"}, []string{"ro", "io"}, "float"
+	}
+
+	direction := "forward"
+	if p.Options.Inverse {
+		direction = "unscaled inverse"
+	}
+
+	summary := fmt.Sprintf("%s computes the %d-point %s %s DFT of %s into %s.", name, n, direction, kind, joinNames(inputs), joinNames(outputs))
+	if p.Options.RuntimeSign || p.Options.SignMultiplier {
+		summary = fmt.Sprintf("%s computes the %d-point %s DFT of %s into %s in the direction of sign.", name, n, kind, joinNames(inputs), joinNames(outputs))
+	}
+
+	slices := joinNames(append(append([]string{}, inputs...), outputs...))
+	length := fmt.Sprintf("%s must have length at least %d", slices, n)
+	if len(p.Strides()) > 0 {
+		length = fmt.Sprintf("%s must hold %d elements at their strides", slices, n)
+	}
+	if p.Options.OutputBase {
+		length += fmt.Sprintf(", %s past obase", joinNames(outputs))
+	}
+
+	switch safe, _ := InPlaceSafe(p.Statements); {
+	case p.Options.NoAlias:
+		return []string{summary, length + ".", fmt.Sprintf("%s is out-of-place, its input and output slices must not overlap.", name)}
+	case safe:
+		return []string{summary, fmt.Sprintf("%s, and %s may be the same as %s.", length, joinNames(inputs), joinNames(outputs))}
+	}
+	return []string{summary, length + ", and must not overlap."}
+}
@@ -0,0 +1,77 @@
+package genfft
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestFuncDoc(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+	alst, cout := naiveSchedule(4, true)
+	floatPrefix := writeSchedule(t, dir, "float_4", alst, cout)
+
+	for _, tc := range []struct {
+		dft  Dft
+		want []string
+	}{
+		{Dft{Prefix: prefix, Func: "DftCmplx3"}, []string{
+			"// DftCmplx3 computes the 3-point forward complex DFT of xi into xo.",
+			"// xi and xo must have length at least 3, and xi may be the same as xo.",
+			"func DftCmplx3(",
+		}},
+		{Dft{Prefix: prefix, Func: "DftCmplx3", Options: Options{RuntimeSign: true, NoAlias: true}}, []string{
+			"// DftCmplx3 computes the 3-point complex DFT of xi into xo in the direction of sign.",
+			"// xi and xo must have length at least 3.",
+			"// DftCmplx3 is out-of-place, its input and output slices must not overlap.",
+			"func DftCmplx3(",
+		}},
+		{Dft{Prefix: floatPrefix, Func: "DftFloat4", Options: Options{Stages: 2}}, []string{
+			"// DftFloat4 computes the 4-point forward float DFT of ri and ii into ro and io.",
+			"// ri, ii, ro and io must have length at least 4, and ri and ii may be the same as ro and io.",
+			"func DftFloat4(",
+		}},
+	} {
+		src := mustGenerate(t, tc.dft).GoString()
+		if want := strings.Join(tc.want, "\n"); !strings.Contains(src, want) {
+			t.Errorf("expected doc comment:\n%s\ngot:\n%s", want, src)
+		}
+	}
+}
+
+func TestWrapperDoc(t *testing.T) {
+	for _, opts := range []Options{
+		{Ring: true, Indexed: true, Scaled: true, Scratch: true},
+		{Ring: true, Indexed: true, Scaled: true, Scratch: true, Expvar: true},
+	} {
+		src := generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: opts}).GoString()
+		file, err := goparser.ParseFile(token.NewFileSet(), "cmplx_8.go", src, goparser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		docs := map[string]string{}
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok {
+				docs[fn.Name.Name] = fn.Doc.Text()
+			}
+		}
+
+		for _, wrapper := range []string{"DftCmplx8Ring", "DftCmplx8Indexed", "DftCmplx8Scaled", "DftCmplx8Scratch"} {
+			doc, ok := docs[wrapper]
+			if !ok {
+				t.Fatalf("%+v: missing %s in:\n%s", opts, wrapper, src)
+			}
+			// Each wrapper has its own doc, starting with its name, alone.
+			if !strings.HasPrefix(doc, wrapper+" computes DftCmplx8 ") || strings.Count(doc, " computes ") != 1 {
+				t.Errorf("%+v: %s has doc:\n%s", opts, wrapper, doc)
+			}
+		}
+		if doc := docs["DftCmplx8"]; !strings.HasPrefix(doc, "DftCmplx8 computes the 8-point forward complex DFT") {
+			t.Errorf("%+v: DftCmplx8 has doc:\n%s", opts, doc)
+		}
+	}
+}
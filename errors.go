@@ -0,0 +1,126 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genFailure renders a statement failing the transform with a message
+// formatted from format and args: returning it as an error if the transform
+// returns errors, panicking with it otherwise.
+func (p Program) genFailure(format string, args ...jen.Code) *jen.Statement {
+	return p.genWrapperFailure(nil, format, args...)
+}
+
+// genWrapperFailure renders a statement failing a wrapper like genFailure,
+// returning the given values of the wrapper's other results before the
+// error.
+func (p Program) genWrapperFailure(values []jen.Code, format string, args ...jen.Code) *jen.Statement {
+	if p.Options.ReturnErrors {
+		return jen.Return(append(values, jen.Qual("fmt", "Errorf").Call(append([]jen.Code{jen.Lit(format)}, args...)...))...)
+	}
+	return jen.Panic(jen.Qual("fmt", "Sprintf").Call(append([]jen.Code{jen.Lit(format)}, args...)...))
+}
+
+// wrapperResults returns a wrapper's results, followed by an error if the
+// transform returns errors.
+func (p Program) wrapperResults(results ...jen.Code) jen.Code {
+	if p.Options.ReturnErrors {
+		results = append(results, jen.Error())
+	}
+	switch len(results) {
+	case 0:
+		return jen.Null()
+	case 1:
+		return results[0]
+	}
+	return jen.Parens(jen.List(results...))
+}
+
+// wrapperReturn renders a wrapper's return of values, followed by a nil
+// error if the transform returns errors. Wrappers without results and errors
+// return nothing.
+func (p Program) wrapperReturn(values ...jen.Code) *jen.Statement {
+	if p.Options.ReturnErrors {
+		values = append(values, jen.Nil())
+	}
+	if len(values) == 0 {
+		return jen.Null()
+	}
+	return jen.Return(values...)
+}
+
+// callTransform renders a wrapper's call of the transform. If the transform
+// returns errors the wrapper does too, returning any error from the call
+// after the given values of the wrapper's other results.
+func (p Program) callTransform(name string, values []jen.Code, args ...jen.Code) *jen.Statement {
+	call := jen.Id(name).Call(args...)
+	if !p.Options.ReturnErrors {
+		return call
+	}
+
+	return jen.If(jen.Err().Op(":=").Add(call), jen.Err().Op("!=").Nil()).Block(
+		jen.Return(append(values, jen.Err())...),
+	)
+}
+
+// genOverlapErrors renders statements returning an error if an input and
+// the output it's transformed into overlap without being the same slice, or
+// overlap at all if the transform is out-of-place only.
+func (p Program) genOverlapErrors(g *jen.Group, name string) {
+	helper, pairs := "overlapCmplx", [][2]string{{"xi", "xo"}}
+	if p.Float() {
+		helper, pairs = "overlapFloat", [][2]string{{"ri", "ro"}, {"ii", "io"}}
+	}
+
+	for _, pair := range pairs {
+		in, out := jen.Id(pair[0]), jen.Id(pair[1])
+		results := jen.List(jen.Id("overlap"), jen.Id("same"))
+		cond := jen.Id("overlap").Op("&&").Op("!").Id("same")
+		msg := fmt.Sprintf("dft: %s: %s and %s overlap without being the same slice", name, pair[0], pair[1])
+		if p.Options.NoAlias {
+			results = jen.List(jen.Id("overlap"), jen.Id("_"))
+			cond = jen.Id("overlap")
+			msg = fmt.Sprintf("dft: %s: %s and %s of an out-of-place transform overlap", name, pair[0], pair[1])
+		}
+
+		g.If(results.Op(":=").Id(helper).Call(in, out), cond).Block(
+			jen.Return(jen.Qual("errors", "New").Call(jen.Lit(msg))),
+		)
+	}
+	g.Line()
+}
+
+// errorsSupport renders the overlap checks used by transforms returning
+// errors.
+func errorsSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	for _, t := range []struct {
+		suffix string
+		typ    jen.Code
+	}{
+		{"Cmplx", jen.Complex128()},
+		{"Float", jen.Float64()},
+	} {
+		name := "overlap" + t.suffix
+		f.Comment(fmt.Sprintf("%s reports whether a and b share any elements, and whether\nthey start at the same element.", name))
+		f.Func().Id(name).Params(jen.List(jen.Id("a"), jen.Id("b")).Index().Add(t.typ)).Params(jen.List(jen.Id("overlap"), jen.Id("same")).Bool()).Block(
+			jen.If(jen.Len(jen.Id("a")).Op("==").Lit(0).Op("||").Len(jen.Id("b")).Op("==").Lit(0)).Block(
+				jen.Return(jen.False(), jen.False()),
+			),
+			jen.Line(),
+			jen.Id("size").Op(":=").Qual("unsafe", "Sizeof").Call(jen.Id("a").Index(jen.Lit(0))),
+			jen.Id("aStart").Op(":=").Uintptr().Call(jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id("a").Index(jen.Lit(0)))),
+			jen.Id("bStart").Op(":=").Uintptr().Call(jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id("b").Index(jen.Lit(0)))),
+			jen.Id("aEnd").Op(":=").Id("aStart").Op("+").Uintptr().Call(jen.Len(jen.Id("a"))).Op("*").Id("size"),
+			jen.Id("bEnd").Op(":=").Id("bStart").Op("+").Uintptr().Call(jen.Len(jen.Id("b"))).Op("*").Id("size"),
+			jen.Line(),
+			jen.Return(jen.Id("aStart").Op("<").Id("bEnd").Op("&&").Id("bStart").Op("<").Id("aEnd"), jen.Id("aStart").Op("==").Id("bStart")),
+		)
+		f.Line()
+	}
+
+	return f
+}
@@ -0,0 +1,124 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestReturnErrors(t *testing.T) {
+	wrappers := Options{
+		ReturnErrors: true, Into: true, Residual: true, PSD: true, Magnitude: true,
+		Halfcomplex: true, CrossSpectrum: true, Pad: true, Batch: true, BatchContext: true,
+		Stream: true, Ring: true, Scaled: true, Scratch: true,
+	}
+	cmplx := wrappers
+	cmplx.ConvStep, cmplx.Columns, cmplx.Plan, cmplx.Transformer = true, true, true, true
+	float := wrappers
+	float.Frame = true
+	outOfPlace := Options{ReturnErrors: true, NoAlias: true}
+	sign := Options{ReturnErrors: true, RuntimeSign: true, Direction: true, NormalizeInverse: true}
+
+	files := map[string]*jen.File{
+		"cmplx_4.go":         generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: cmplx}),
+		"float_4.go":         generateNaive(t, 4, Dft{Func: "DftFloat4", Options: float}),
+		"cmplx_4_noalias.go": generateNaive(t, 4, Dft{Func: "DftCmplx4NoAlias", Options: outOfPlace}),
+		"cmplx_4_sign.go":    generateNaive(t, 4, Dft{Func: "DftCmplx4Sign", Options: sign}),
+		"errors.go":          errorsSupport("dft"),
+		"transformer.go":     transformerSupport("dft"),
+		"frame.go":           frameSupport("dft"),
+		"direction.go":       directionSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func checkErr(t *testing.T, what string, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("%s: unexpected error %v", what, err)
+	case want != "" && (err == nil || err.Error() != want):
+		t.Fatalf("%s: got error %v, want %q", what, err, want)
+	}
+}
+
+func TestReturnErrors(t *testing.T) {
+	buf := make([]complex128, 8)
+	xi, xo := buf[:4], buf[4:]
+
+	checkErr(t, "success", DftCmplx4(xi, xo), "")
+	checkErr(t, "in-place", DftCmplx4(xi, xi), "")
+	checkErr(t, "short input", DftCmplx4(xi[:3], xo), "dft: DftCmplx4: xi has length 3, need 4")
+	checkErr(t, "short output", DftCmplx4(xi, xo[:2]), "dft: DftCmplx4: xo has length 2, need 4")
+	checkErr(t, "partial overlap", DftCmplx4(buf[:4], buf[2:6]), "dft: DftCmplx4: xi and xo overlap without being the same slice")
+
+	checkErr(t, "out-of-place", DftCmplx4NoAlias(xi, xo), "")
+	checkErr(t, "out-of-place in-place", DftCmplx4NoAlias(xi, xi), "dft: DftCmplx4NoAlias: xi and xo of an out-of-place transform overlap")
+
+	f := make([]float64, 16)
+	ri, ii, ro, io := f[:4], f[4:8], f[8:12], f[12:]
+	checkErr(t, "float success", DftFloat4(ri, ii, ro, io), "")
+	checkErr(t, "float short", DftFloat4(ri, ii[:1], ro, io), "dft: DftFloat4: ii has length 1, need 4")
+	checkErr(t, "float overlap", DftFloat4(ri, ii, ro, f[5:9]), "dft: DftFloat4: ii and io overlap without being the same slice")
+
+	// Variants rendered like the transform return errors too.
+	checkErr(t, "scaled", DftCmplx4Scaled(xi, xo[:3], 2), "dft: DftCmplx4Scaled: xo has length 3, need 4")
+	checkErr(t, "scratch", DftCmplx4Scratch(xi, xo, nil), "dft: DftCmplx4Scratch: scratch has length 0, need 4")
+
+	// Wrappers return their own errors and the transform's.
+	out, err := DftCmplx4Into(xi, make([]complex128, 0, 3))
+	checkErr(t, "into capacity", err, "dft: DftCmplx4Into: out has capacity 3, need 4")
+	if out != nil {
+		t.Fatalf("into capacity: got output %v, want nil", out)
+	}
+	_, err = DftCmplx4Into(buf[:4], buf[1:5])
+	checkErr(t, "into overlap", err, "dft: DftCmplx4: xi and xo overlap without being the same slice")
+	out, err = DftCmplx4Into(xi, make([]complex128, 0, 8))
+	checkErr(t, "into success", err, "")
+	if len(out) != 4 {
+		t.Fatalf("into success: got length %d, want 4", len(out))
+	}
+	_, _, err = DftFloat4Into(ri, ii, ro, make([]float64, 2))
+	checkErr(t, "float into capacity", err, "dft: DftFloat4Into: io has capacity 2, need 4")
+
+	_, err = DftCmplx4Residual(buf[:4], buf[2:6])
+	checkErr(t, "residual", err, "dft: DftCmplx4: xi and xo overlap without being the same slice")
+	_, err = DftCmplx4Pad(buf[:5])
+	checkErr(t, "pad", err, "dft: DftCmplx4Pad: input length 5 exceeds 4")
+	checkErr(t, "batch", DftCmplx4Batch(buf, buf[2:], nil), "dft: DftCmplx4: xi and xo overlap without being the same slice")
+	checkErr(t, "batch context", DftCmplx4BatchContext(context.Background(), buf, buf, nil), "")
+	checkErr(t, "columns rows", DftCmplx4Columns(buf, 2, 4), "dft: DftCmplx4Columns: columns have 2 rows, need 4")
+	checkErr(t, "columns data", DftCmplx4Columns(buf, 4, 4), "dft: DftCmplx4Columns: data has length 8, need 16")
+	checkErr(t, "columns success", DftCmplx4Columns(buf, 4, 2), "")
+	checkErr(t, "conv", DftCmplx4ConvStep(xi, xo, xo[:1]), "dft: DftCmplx4: xo has length 1, need 4")
+	checkErr(t, "psd", DftFloat4PSD(ri, ro), "")
+	checkErr(t, "frame", (&Frame{Re: ri, Im: ii[:3]}).Dft4(), "dft: DftFloat4: ii has length 3, need 4")
+	checkErr(t, "direction", DftCmplx4SignDirection(xi, xo[:2], Inverse), "dft: DftCmplx4Sign: xo has length 2, need 4")
+	checkErr(t, "direction success", DftCmplx4SignDirection(xi, xo, Inverse), "")
+
+	var plan DftCmplx4Plan
+	checkErr(t, "plan", plan.Transform(xi, xo[:2]), "dft: DftCmplx4: xo has length 2, need 4")
+	_, err = DftCmplx4WithPlan(xi, xo)
+	checkErr(t, "with plan", err, "")
+
+	var w bytes.Buffer
+	checkErr(t, "stream", DftCmplx4Stream(bytes.NewReader(make([]byte, 64)), &w), "")
+
+	// Transformer's signature is fixed, so it panics with the transform's
+	// error instead.
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			checkErr(t, "transformer", err, "dft: DftCmplx4: xi and xo overlap without being the same slice")
+		}()
+		DftCmplx4Transformer{}.Transform(buf[:4], buf[1:5])
+	}()
+}
+`)
+}
@@ -0,0 +1,31 @@
+package genfft
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// metricsVar returns the name of the variable holding a transform's metrics.
+func metricsVar(name string) string {
+	return strings.ToLower(name[:1]) + name[1:] + "Metrics"
+}
+
+// genMetricsVar renders the expvar map a transform records its metrics in,
+// published as dft.Name so they're served over HTTP by expvar's handler.
+func genMetricsVar(f *jen.File, name string) {
+	f.Comment(metricsVar(name) + " counts calls to " + name + " and the nanoseconds spent in them.")
+	f.Var().Id(metricsVar(name)).Op("=").Qual("expvar", "NewMap").Call(jen.Lit("dft." + name))
+	f.Line()
+}
+
+// genMetrics renders a deferred call recording a transform's call count and
+// cumulative time when it returns.
+func genMetrics(g *jen.Group, name string) {
+	metrics := jen.Id(metricsVar(name))
+	g.Defer().Func().Params(jen.Id("start").Qual("time", "Time")).Block(
+		metrics.Clone().Dot("Add").Call(jen.Lit("calls"), jen.Lit(1)),
+		metrics.Clone().Dot("Add").Call(jen.Lit("nanos"), jen.Int64().Call(jen.Qual("time", "Since").Call(jen.Id("start")))),
+	).Call(jen.Qual("time", "Now").Call())
+	g.Line()
+}
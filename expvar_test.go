@@ -0,0 +1,52 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestExpvar(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go":        generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Expvar: true}}),
+		"cmplx_8_staged.go": generateNaive(t, 8, Dft{Func: "DftCmplx8Staged", Options: Options{Expvar: true, Stages: 2}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"expvar"
+	"testing"
+)
+
+func metric(t *testing.T, name, key string) int64 {
+	m, ok := expvar.Get("dft." + name).(*expvar.Map)
+	if !ok {
+		t.Fatalf("dft.%s isn't published", name)
+	}
+	v, ok := m.Get(key).(*expvar.Int)
+	if !ok {
+		t.Fatalf("dft.%s has no %s", name, key)
+	}
+	return v.Value()
+}
+
+func TestExpvar(t *testing.T) {
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	for n := 0; n < 3; n++ {
+		DftCmplx8(xi, xo)
+	}
+	DftCmplx8Staged(xi, xo)
+
+	if calls := metric(t, "DftCmplx8", "calls"); calls != 3 {
+		t.Fatalf("DftCmplx8 calls %d, want 3", calls)
+	}
+	if calls := metric(t, "DftCmplx8Staged", "calls"); calls != 1 {
+		t.Fatalf("DftCmplx8Staged calls %d, want 1", calls)
+	}
+	if nanos := metric(t, "DftCmplx8", "nanos"); nanos < 0 {
+		t.Fatalf("DftCmplx8 nanos %d", nanos)
+	}
+}
+`)
+}
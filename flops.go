@@ -0,0 +1,107 @@
+package genfft
+
+import (
+	"path/filepath"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// opCount counts the real additions and multiplications an expression
+// performs. Complex additions and multiplications by real constants take two
+// each, negations fold into the additions consuming them and multiplying by
+// the imaginary constant only swaps parts, so neither is counted.
+func (e Expr) opCount(complexArgs bool) (adds, mults int) {
+	if e.Ident != "" {
+		return 0, 0
+	}
+
+	for _, sub := range e.Sub {
+		a, m := sub.opCount(complexArgs)
+		adds, mults = adds+a, mults+m
+	}
+
+	width := 1
+	if complexArgs {
+		width = 2
+	}
+
+	switch {
+	case e.Op == ":=" || len(e.Sub) == 1:
+	case e.Op == "*":
+		factors := 0
+		for _, sub := range e.Sub {
+			if sub.Ident != "I" {
+				factors++
+			}
+		}
+		if factors > 1 {
+			mults += (factors - 1) * width
+		}
+	default:
+		adds += (len(e.Sub) - 1) * width
+	}
+
+	return adds, mults
+}
+
+// OpCount returns the number of real additions and multiplications performed
+// by one evaluation of the program.
+func (p Program) OpCount() (adds, mults int) {
+	complexArgs := !p.Float()
+	for _, expr := range p.Statements {
+		a, m := expr.opCount(complexArgs)
+		adds, mults = adds+a, mults+m
+	}
+
+	return adds, mults
+}
+
+// Flops returns the number of real floating-point operations performed by
+// one evaluation of the program.
+func (p Program) Flops() int {
+	adds, mults := p.OpCount()
+	return adds + mults
+}
+
+// flopsSupport renders, for each package directory, a test file with the
+// operation count of every transform and a helper reporting benchmark
+// throughput from it.
+func flopsSupport(dfts []Dft) map[string]*jen.File {
+	counts := map[string]jen.Dict{}
+	for _, dft := range dfts {
+		dir := filepath.Dir(dft.Prefix)
+		if counts[dir] == nil {
+			counts[dir] = jen.Dict{}
+		}
+		prog, err := dft.Parse()
+		if err != nil {
+			log.Warnf("skipping operation count of %s: %+v\n", dft.Func, err)
+			continue
+		}
+		counts[dir][jen.Lit(dft.Func)] = jen.Lit(prog.Flops())
+	}
+
+	files := map[string]*jen.File{}
+	for dir, dict := range counts {
+		f := jen.NewFilePathName("dft", "dft")
+
+		f.Comment("flops holds the number of floating-point operations performed by each transform.")
+		f.Var().Id("flops").Op("=").Map(jen.String()).Int().Values(dict)
+		f.Line()
+
+		f.Comment("reportFlops reports the throughput of b.N evaluations of the named transform\ntaking elapsed in total.")
+		f.Func().Id("reportFlops").Params(
+			jen.Id("b").Op("*").Qual("testing", "B"),
+			jen.Id("name").String(),
+			jen.Id("elapsed").Qual("time", "Duration"),
+		).Block(
+			jen.Id("ops").Op(":=").Float64().Call(jen.Id("flops").Index(jen.Id("name"))).Op("*").Float64().Call(jen.Id("b").Dot("N")),
+			jen.Id("b").Dot("ReportMetric").Call(jen.Id("ops").Op("/").Id("elapsed").Dot("Seconds").Call().Op("/").Lit(1e9), jen.Lit("GFLOP/s")),
+		)
+
+		files[filepath.Join(dir, "flops_test.go")] = f
+	}
+
+	return files
+}
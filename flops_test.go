@@ -0,0 +1,60 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestFlops(t *testing.T) {
+	dft := Dft{Prefix: copyTestdata(t, t.TempDir(), "cmplx_3"), Func: "DftCmplx3"}
+
+	// Six complex additions and two multiplications by real constants.
+	if got := mustParse(t, dft).Flops(); got != 16 {
+		t.Fatalf("DftCmplx3 performs 16 flops, counted %d", got)
+	}
+}
+
+func TestReportFlops(t *testing.T) {
+	alst, cout := naiveSchedule(8, false)
+	dft := Dft{Prefix: writeSchedule(t, t.TempDir(), "DftCmplx8", alst, cout), Func: "DftCmplx8"}
+
+	files := map[string]*jen.File{
+		"cmplx_8.go": mustGenerate(t, dft),
+	}
+	// Transforms whose schedules can't be read are skipped.
+	missing := Dft{Prefix: dft.Prefix + "_missing", Func: "DftCmplx4"}
+	for _, f := range flopsSupport([]Dft{dft, missing}) {
+		files["flops_test.go"] = f
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportFlops(t *testing.T) {
+	if flops["DftCmplx8"] == 0 {
+		t.Fatal("missing operation count for DftCmplx8")
+	}
+	if _, ok := flops["DftCmplx4"]; ok {
+		t.Fatal("counted operations of a transform that couldn't be parsed")
+	}
+
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	result := testing.Benchmark(func(b *testing.B) {
+		start := time.Now()
+		for n := 0; n < b.N; n++ {
+			DftCmplx8(xi, xo)
+		}
+		reportFlops(b, "DftCmplx8", time.Since(start))
+	})
+
+	if gflops := result.Extra["GFLOP/s"]; !(gflops > 0) {
+		t.Fatalf("reported %v GFLOP/s", gflops)
+	}
+}
+`)
+}
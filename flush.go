@@ -0,0 +1,81 @@
+package genfft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Operators flushing an intermediate value to zero when its magnitude is
+// below a threshold, given as their second operand.
+const (
+	flushFloatOp = "FLUSH64"
+	flushCmplxOp = "FLUSH128"
+)
+
+func init() {
+	RegisterLowering(flushFloatOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Id("flushFloat").Call(operands...)
+	})
+	RegisterLowering(flushCmplxOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Id("flushCmplx").Call(operands...)
+	})
+}
+
+// flushIntermediates returns the program's statements with every temporary
+// flushed to zero when its magnitude is below threshold. Outputs are left
+// alone, since they're only ever as small as their sums.
+func (p Program) flushIntermediates(name string, threshold float64) ([]Expr, error) {
+	if threshold < 0 {
+		return nil, fmt.Errorf("%s: flush threshold must be positive, got %g", name, threshold)
+	}
+	if p.Options.Generic || p.Options.Compensated {
+		return nil, fmt.Errorf("%s: flushing to zero requires a non-generic, uncompensated function", name)
+	}
+
+	op := flushCmplxOp
+	if p.Float() {
+		op = flushFloatOp
+	}
+	limit := Expr{Ident: strconv.FormatFloat(threshold, 'g', -1, 64)}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		if expr.Op == ":=" && len(expr.Sub) == 2 && !strings.HasSuffix(expr.Sub[0].Ident, "]") {
+			expr = Expr{Pos: expr.Pos, Op: expr.Op, Sub: []Expr{
+				expr.Sub[0],
+				{Pos: expr.Pos, Op: op, Sub: []Expr{expr.Sub[1], limit}},
+			}}
+		}
+		statements[idx] = expr
+	}
+
+	return statements, nil
+}
+
+// flushSupport renders the helpers flushing values too small to be normal,
+// which are slow to compute with on some hardware, to zero.
+func flushSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("flushFloat returns zero if x has magnitude below threshold, and x otherwise.")
+	f.Func().Id("flushFloat").Params(jen.List(jen.Id("x"), jen.Id("threshold")).Float64()).Float64().Block(
+		jen.If(jen.Qual("math", "Abs").Call(jen.Id("x")).Op("<").Id("threshold")).Block(
+			jen.Return(jen.Lit(0)),
+		),
+		jen.Return(jen.Id("x")),
+	)
+	f.Line()
+
+	f.Comment("flushCmplx flushes the real and imaginary parts of x to zero independently.")
+	f.Func().Id("flushCmplx").Params(jen.Id("x").Complex128(), jen.Id("threshold").Float64()).Complex128().Block(
+		jen.Return(jen.Complex(
+			jen.Id("flushFloat").Call(jen.Real(jen.Id("x")), jen.Id("threshold")),
+			jen.Id("flushFloat").Call(jen.Imag(jen.Id("x")), jen.Id("threshold")),
+		)),
+	)
+
+	return f
+}
@@ -0,0 +1,43 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestFlushToZero(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_2.go": generateNaive(t, 2, Dft{Func: "DftFloat2", Options: Options{FlushToZero: 1e-30}}),
+		"cmplx_2.go": generateNaive(t, 2, Dft{Func: "DftCmplx2", Options: Options{FlushToZero: 1e-30}}),
+		"flush.go":   flushSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestFlushToZero(t *testing.T) {
+	for _, tc := range []struct {
+		in, want float64
+	}{
+		{1e-40, 0},
+		{-1e-31, 0},
+		{1e-20, 1e-20},
+		{-3, -3},
+	} {
+		ro, io := make([]float64, 2), make([]float64, 2)
+		DftFloat2([]float64{tc.in, 0}, []float64{0, tc.in}, ro, io)
+		if ro[0] != tc.want || io[0] != tc.want {
+			t.Errorf("float %g: got %g%+gi, want %g%+gi", tc.in, ro[0], io[0], tc.want, tc.want)
+		}
+
+		xo := make([]complex128, 2)
+		DftCmplx2([]complex128{complex(tc.in, 0), complex(0, tc.in)}, xo)
+		if xo[0] != complex(tc.want, tc.want) {
+			t.Errorf("cmplx %g: got %g, want %g", tc.in, xo[0], complex(tc.want, tc.want))
+		}
+	}
+}
+`)
+}
@@ -0,0 +1,50 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// frameMethod returns the name of the Frame method wrapping a float
+// transform, the transform's name without "Float".
+func frameMethod(name string) string {
+	return strings.Replace(name, "Float", "", 1)
+}
+
+// genFrameMethod renders a method computing a float transform of a Frame
+// in-place.
+func (p Program) genFrameMethod(f *jen.File, name string) error {
+	if !p.Float() {
+		return fmt.Errorf("%s: frame methods require a float schedule", name)
+	}
+
+	method := frameMethod(name)
+	extraParams, extraArgs := p.passThrough()
+
+	re, im := jen.Id("f").Dot("Re"), jen.Id("f").Dot("Im")
+	args := append([]jen.Code{re, im, re, im}, extraArgs...)
+
+	f.Line()
+	f.Comment(fmt.Sprintf("%s computes %s of the frame in-place.", method, name))
+	f.Func().Params(jen.Id("f").Op("*").Id("Frame")).Id(method).Params(extraParams...).Add(p.wrapperResults()).Block(
+		p.callTransform(name, nil, args...),
+		p.wrapperReturn(),
+	)
+
+	return nil
+}
+
+// frameSupport renders the struct-of-arrays type float transforms operate on
+// through Frame methods.
+func frameSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("Frame holds complex samples as separate slices of real and imaginary parts.")
+	f.Type().Id("Frame").Struct(
+		jen.List(jen.Id("Re"), jen.Id("Im")).Index().Float64(),
+	)
+
+	return f
+}
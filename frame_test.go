@@ -0,0 +1,37 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestFrame(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Frame: true}}),
+		"frame.go":   frameSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestFrame(t *testing.T) {
+	xi := randCmplx(8)
+	frame := Frame{Re: make([]float64, 8), Im: make([]float64, 8)}
+	for idx, x := range xi {
+		frame.Re[idx], frame.Im[idx] = real(x), imag(x)
+	}
+
+	ro, io := make([]float64, 8), make([]float64, 8)
+	DftFloat8(frame.Re, frame.Im, ro, io)
+	frame.Dft8()
+
+	for idx := range ro {
+		if frame.Re[idx] != ro[idx] || frame.Im[idx] != io[idx] {
+			t.Fatalf("frame output differs at %d: %v+%vi != %v+%vi", idx, frame.Re[idx], frame.Im[idx], ro[idx], io[idx])
+		}
+	}
+}
+`)
+}
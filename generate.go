@@ -0,0 +1,98 @@
+package genfft
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Generate parses a schedule and the C output defining its constants, and
+// renders the transform as a function named fnName in package pkg, for
+// callers generating transforms without config.json or files on disk. The C
+// output may be nil if the schedule defines its constants inline.
+func Generate(alst, cout io.Reader, pkg, fnName string) (*jen.File, error) {
+	prog, err := ParseProgram(alst, cout)
+	if err != nil {
+		return nil, err
+	}
+
+	return prog.Render(pkg, fnName)
+}
+
+// ParseProgram parses a schedule and the C output defining its constants,
+// which may be nil if the schedule defines them inline. The program is
+// rendered with its default options unless the caller sets them.
+func ParseProgram(alst, cout io.Reader) (*Program, error) {
+	alstBytes, err := io.ReadAll(alst)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	var coutBytes []byte
+	if cout != nil {
+		coutBytes, err = io.ReadAll(cout)
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+	}
+
+	prog, err := parseSchedule("schedule", alstBytes)
+	if err != nil {
+		return nil, err
+	}
+	prog.Constants = mergeConstants(prog.Constants, ParseConstants(bytes.NewReader(coutBytes)))
+
+	// Catch schedules paired with constants for a different transform, and
+	// malformed schedules before they render uncompilable go.
+	if err := prog.CheckSize(bytes.NewReader(coutBytes)); err != nil {
+		return nil, err
+	}
+	if err := prog.CheckTemporaries(); err != nil {
+		return nil, err
+	}
+
+	return prog, nil
+}
+
+// goGenerateDirective returns a go:generate directive regenerating the
+// transform with the given prefix. go generate runs commands in the
+// directory of the generated file, so the directive changes back to the
+// directory prefixes are relative to, the one holding config.json.
+func goGenerateDirective(prefix string) string {
+	args := []string{"genfft"}
+	if root, err := filepath.Rel(filepath.Dir(prefix), "."); err == nil && root != "." {
+		args = append(args, "-C", filepath.ToSlash(root))
+	}
+	args = append(args, filepath.ToSlash(prefix))
+
+	return fmt.Sprintf("//go:generate %s", strings.Join(args, " "))
+}
+
+// SelectPrefixes returns the transforms with the given prefixes, in the
+// order of the config, or an error naming a prefix matching no transform.
+func SelectPrefixes(dfts []Dft, prefixes []string) ([]Dft, error) {
+	wanted := map[string]bool{}
+	for _, prefix := range prefixes {
+		wanted[filepath.Clean(prefix)] = false
+	}
+
+	var selected []Dft
+	for _, dft := range dfts {
+		if _, ok := wanted[filepath.Clean(dft.Prefix)]; ok {
+			wanted[filepath.Clean(dft.Prefix)] = true
+			selected = append(selected, dft)
+		}
+	}
+
+	for _, prefix := range prefixes {
+		if !wanted[filepath.Clean(prefix)] {
+			return nil, fmt.Errorf("no transform has prefix %s", prefix)
+		}
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,117 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestGenerate(t *testing.T) {
+	alst, cout := naiveSchedule(8, false)
+	f, err := Generate(strings.NewReader(alst), strings.NewReader(cout), "dft", "DftCmplx8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goTest(t, map[string]*jen.File{"cmplx_8.go": f}, `package dft
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	xi := randCmplx(8)
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+
+	xo := make([]complex128, 8)
+	DftCmplx8(xi, xo)
+	if err := dftError(xo, naive); err > 1e-12 {
+		t.Fatalf("error %g exceeds tolerance", err)
+	}
+}
+`)
+}
+
+func TestGen(t *testing.T) {
+	alst, cout := naiveSchedule(3, false)
+	prog, err := ParseProgram(strings.NewReader(alst), strings.NewReader(cout))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := prog.Gen("dft", "DftCmplx3").GoString(), mustRender(t, prog, "DftCmplx3").GoString(); got != want {
+		t.Fatalf("Gen and Render differ:\n%s\n%s", got, want)
+	}
+
+	// Render's errors are raised as panics.
+	defer func() {
+		if err, ok := recover().(error); !ok || !strings.Contains(err.Error(), "power-of-two size") {
+			t.Fatalf("expected a panic with Render's error, got %v", err)
+		}
+	}()
+	prog.Options = Options{BitReverse: true}
+	prog.Gen("dft", "DftCmplx3")
+}
+
+func TestRenderErrors(t *testing.T) {
+	alst, cout := naiveSchedule(3, false)
+	prog, err := ParseProgram(strings.NewReader(alst), strings.NewReader(cout))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		opts Options
+		want string
+	}{
+		{Options{BitReverse: true}, "power-of-two size"},
+		{Options{Contraction: "fma"}, "requires a float schedule"},
+		{Options{Lanes: 3}, "lanes must be 2 or 4"},
+		{Options{Frame: true}, "frame methods require a float schedule"},
+		{Options{TinyGo: true, NoAlias: true}, "can't use unsafe"},
+	} {
+		prog.Options = tc.opts
+		if _, err := prog.Render("dft", "DftCmplx3"); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tc.opts, tc.want, err)
+		}
+	}
+
+	// Operators without a lowering are caught before rendering.
+	if _, err := Generate(strings.NewReader("(:= xo[0] (VSQRT xi[0]))\n"), nil, "dft", "DftCmplx1"); err == nil || !strings.Contains(err.Error(), "no registered lowering") {
+		t.Errorf("expected an error for an operator without a lowering, got %v", err)
+	}
+}
+
+func TestGoGenerate(t *testing.T) {
+	dft := Dft{Prefix: "testdata/cmplx_3", Func: "DftCmplx3", Options: Options{GoGenerate: true}}
+	src := mustGenerate(t, dft).GoString()
+	if !strings.Contains(src, "//go:generate genfft -C .. testdata/cmplx_3\n") {
+		t.Fatalf("expected go:generate directive for testdata/cmplx_3:\n%s", src)
+	}
+
+	for prefix, expected := range map[string]string{
+		"cmplx_3":          "//go:generate genfft cmplx_3",
+		"dft/cmplx_3":      "//go:generate genfft -C .. dft/cmplx_3",
+		"dft/float/dft_16": "//go:generate genfft -C ../.. dft/float/dft_16",
+	} {
+		if directive := goGenerateDirective(prefix); directive != expected {
+			t.Errorf("%s: expected %q, got %q", prefix, expected, directive)
+		}
+	}
+}
+
+func TestSelectPrefixes(t *testing.T) {
+	dfts := []Dft{{Prefix: "dft/cmplx_3"}, {Prefix: "dft/cmplx_4"}, {Prefix: "dft/cmplx_5"}}
+
+	selected, err := SelectPrefixes(dfts, []string{"dft/cmplx_5", "./dft/cmplx_3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].Prefix != "dft/cmplx_3" || selected[1].Prefix != "dft/cmplx_5" {
+		t.Fatalf("expected cmplx_3 and cmplx_5 in config order, got %+v", selected)
+	}
+
+	if _, err := SelectPrefixes(dfts, []string{"dft/cmplx_6"}); err == nil {
+		t.Fatal("expected an error selecting an unknown prefix")
+	}
+}
@@ -0,0 +1,195 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// GenGeneric renders a go-representation of an expression in terms of the
+// methods of the Complex constraint. Multiplying by a real constant lowers to
+// Scale and multiplying by the imaginary constant lowers to MulI.
+func (e Expr) GenGeneric(constants map[string]bool) *jen.Statement {
+	// Expressions with an identifier are just that identifier.
+	if e.Ident != "" {
+		return genIdent(e.Ident)
+	}
+
+	if c, ok := e.lower(func(sub Expr) *jen.Statement { return sub.GenGeneric(constants) }); ok {
+		return c
+	}
+
+	// Expressions with only one sub-expression are negations.
+	if len(e.Sub) == 1 {
+		return e.Sub[0].GenGeneric(constants).Dot("Neg").Call()
+	}
+
+	switch e.Op {
+	case ":=":
+		// When the left side of an expression is an indexed identifier, assign only.
+		op := ":="
+		if strings.HasSuffix(e.Sub[0].Ident, "]") {
+			op = "="
+		}
+		return genIdent(e.Sub[0].Ident).Op(op).Add(e.Sub[1].GenGeneric(constants))
+
+	case "*":
+		// Constants can't have methods, so multiply the first non-constant
+		// operand by everything else.
+		var (
+			lt      *jen.Statement
+			factors []Expr
+		)
+		for _, sub := range e.Sub {
+			if lt == nil && !(sub.Ident == "I" || constants[sub.Ident]) {
+				lt = sub.GenGeneric(constants)
+				continue
+			}
+			factors = append(factors, sub)
+		}
+		// Products of constants alone have no receiver, and can't be made
+		// generic. genGeneric rejects them upfront, so just render them.
+		if lt == nil {
+			return e.Gen()
+		}
+
+		for _, sub := range factors {
+			switch {
+			case sub.Ident == "I":
+				lt.Dot("MulI").Call()
+			case constants[sub.Ident]:
+				lt.Dot("Scale").Call(jen.Id(sub.Ident))
+			default:
+				lt.Dot("Mul").Call(sub.GenGeneric(constants))
+			}
+		}
+		return lt
+	}
+
+	// Render the left side of the expression.
+	lt := e.Sub[0].GenGeneric(constants)
+	for _, sub := range e.Sub[1:] {
+		switch {
+		case e.Op == "-":
+			lt.Dot("Sub").Call(sub.GenGeneric(constants))
+
+		// Flatten add followed by unary subtraction.
+		case sub.Op == "-" && len(sub.Sub) == 1:
+			lt.Dot("Sub").Call(sub.Sub[0].GenGeneric(constants))
+
+		default:
+			lt.Dot("Add").Call(sub.GenGeneric(constants))
+		}
+	}
+
+	return lt
+}
+
+// checkGeneric returns an error if e or any of its sub-expressions is a
+// product of constants alone, which GenGeneric has no operand to call
+// methods on for.
+func (e Expr) checkGeneric(constants map[string]bool) error {
+	if _, lowered := lowerings[e.Op]; !lowered && e.Op == "*" && e.binary() {
+		product := true
+		for _, sub := range e.Sub {
+			if !(sub.Ident == "I" || constants[sub.Ident]) {
+				product = false
+			}
+		}
+		if product {
+			return fmt.Errorf("product of constants %s can't be made generic", e)
+		}
+	}
+
+	for _, sub := range e.Sub {
+		if err := sub.checkGeneric(constants); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// genGeneric renders a complex program as a function generic over any type
+// satisfying the Complex constraint.
+func (p Program) genGeneric(f *jen.File, name string) error {
+	if p.Float() {
+		return fmt.Errorf("%s: generic transforms require a complex schedule", name)
+	}
+
+	constants := map[string]bool{}
+	for _, c := range p.Constants {
+		constants[c.Name] = true
+	}
+
+	for _, expr := range p.Statements {
+		if err := expr.checkGeneric(constants); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	params := []jen.Code{jen.List(jen.Id("xi"), jen.Id("xo")).Index().Id("T")}
+	if strides := p.Strides(); len(strides) > 0 {
+		params = append(params, strideParams(strides))
+	}
+	if p.Options.OutputBase {
+		params = append(params, jen.Id("obase").Int())
+	}
+
+	if p.Options.Expvar {
+		genMetricsVar(f, name)
+	}
+
+	for _, line := range p.funcDoc(name) {
+		f.Comment(line)
+	}
+	f.Func().Id(name).Types(
+		jen.Id("T").Id("Complex").Types(jen.Id("T")),
+	).Params(params...).BlockFunc(func(g *jen.Group) {
+		if p.Options.Expvar {
+			genMetrics(g, name)
+		}
+
+		if p.Options.DebugTiming {
+			genTiming(g, name)
+		}
+
+		if !p.Options.NoLenGuard {
+			p.genLenGuard(g, name)
+		}
+
+		p.genConstants(g)
+
+		// Render the statements.
+		for _, expr := range p.Statements {
+			g.Add(expr.GenGeneric(constants))
+		}
+	})
+
+	return nil
+}
+
+// genericSupport renders the constraint satisfied by number types usable with
+// generic transforms.
+func genericSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("Complex is implemented by complex number types usable with generic transforms.")
+	f.Type().Id("Complex").Types(jen.Id("T").Any()).Interface(
+		jen.Comment("Add returns the sum of the receiver and x."),
+		jen.Id("Add").Params(jen.Id("x").Id("T")).Id("T"),
+		jen.Comment("Sub returns the difference of the receiver and x."),
+		jen.Id("Sub").Params(jen.Id("x").Id("T")).Id("T"),
+		jen.Comment("Mul returns the product of the receiver and x."),
+		jen.Id("Mul").Params(jen.Id("x").Id("T")).Id("T"),
+		jen.Comment("Neg returns the negation of the receiver."),
+		jen.Id("Neg").Params().Id("T"),
+		jen.Comment("Scale returns the receiver multiplied by a real constant."),
+		jen.Id("Scale").Params(jen.Id("k").Float64()).Id("T"),
+		jen.Comment("MulI returns the receiver multiplied by the imaginary unit."),
+		jen.Id("MulI").Params().Id("T"),
+	)
+
+	return f
+}
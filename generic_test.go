@@ -0,0 +1,84 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestGeneric(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]*jen.File{"generic.go": genericSupport("dft")}
+	for _, dft := range []Dft{
+		{Prefix: copyTestdata(t, dir, "cmplx_3"), Func: "DftCmplx3"},
+		{Prefix: copyTestdata(t, dir, "cmplx_3"), Func: "DftCmplx3Generic", Options: Options{Generic: true}},
+	} {
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	alst, cout := naiveSchedule(8, false)
+	prefix := writeSchedule(t, dir, "cmplx_8", alst, cout)
+	files["cmplx_8.go"] = mustGenerate(t, Dft{Prefix: prefix, Func: "DftCmplx8Generic", Options: Options{Generic: true}})
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+// c128 wraps complex128 to satisfy Complex.
+type c128 complex128
+
+func (a c128) Add(b c128) c128       { return a + b }
+func (a c128) Sub(b c128) c128       { return a - b }
+func (a c128) Mul(b c128) c128       { return a * b }
+func (a c128) Neg() c128             { return -a }
+func (a c128) Scale(k float64) c128  { return a * c128(complex(k, 0)) }
+func (a c128) MulI() c128            { return a * 1i }
+
+func wrap(x []complex128) []c128 {
+	w := make([]c128, len(x))
+	for idx := range x {
+		w[idx] = c128(x[idx])
+	}
+	return w
+}
+
+func unwrap(w []c128) []complex128 {
+	x := make([]complex128, len(w))
+	for idx := range w {
+		x[idx] = complex128(w[idx])
+	}
+	return x
+}
+
+func TestGeneric(t *testing.T) {
+	xi := randCmplx(3)
+	xo := make([]complex128, 3)
+	DftCmplx3(xi, xo)
+
+	wo := make([]c128, 3)
+	DftCmplx3Generic(wrap(xi), wo)
+	if err := dftError(unwrap(wo), xo); err > 1e-15 {
+		t.Errorf("DftCmplx3Generic: error %g", err)
+	}
+
+	xi = randCmplx(8)
+	wo = make([]c128, 8)
+	DftCmplx8Generic(wrap(xi), wo)
+	naiveDFT(xi, -1.0)
+	if err := dftError(unwrap(wo), xi); err > 1e-14 {
+		t.Errorf("DftCmplx8Generic: error %g", err)
+	}
+
+	want := "dft: DftCmplx8Generic: xi has length 7, need 8"
+	func() {
+		defer func() {
+			if msg := recover(); msg != want {
+				t.Fatalf("short input: got panic %v, want %q", msg, want)
+			}
+		}()
+		DftCmplx8Generic(wrap(xi[:7]), wo)
+	}()
+}
+`)
+}
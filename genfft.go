@@ -0,0 +1,1021 @@
+package genfft
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+	"github.com/alecthomas/participle/v2/lexer/stateful"
+	"github.com/dave/jennifer/jen"
+)
+
+// Options control how a program is rendered.
+type Options struct {
+	// Stages splits the transform into this many helper functions.
+	Stages int `json:"stages,omitempty"`
+
+	// TinyGo restricts the transform to constructs suited to TinyGo: no
+	// math.FMA, no unsafe, and no very large functions.
+	TinyGo bool `json:"tinyGo,omitempty"`
+
+	// NoAlias documents that input and output must not overlap, asserted
+	// when built with the debug tag.
+	NoAlias bool `json:"noAlias,omitempty"`
+
+	// RuntimeSign adds a sign argument selecting a forward (-1) or inverse
+	// (+1) transform at runtime.
+	RuntimeSign bool `json:"runtimeSign,omitempty"`
+
+	// SignMultiplier adds a float64 sign argument used as a multiplier,
+	// selecting a forward (-1) or inverse (+1) transform without branches.
+	SignMultiplier bool `json:"signMultiplier,omitempty"`
+
+	// Generic renders a complex transform generic over any type satisfying
+	// the Complex constraint.
+	Generic bool `json:"generic,omitempty"`
+
+	// Into adds a wrapper computing into a caller-provided buffer and
+	// returning it resliced to the transform length.
+	Into bool `json:"into,omitempty"`
+
+	// BitReverse writes outputs of power-of-two transforms in bit-reversed
+	// order.
+	BitReverse bool `json:"bitReverse,omitempty"`
+
+	// Canonical sorts the operands of sums and products, and independent
+	// statements, into a fixed order so equivalent schedules round
+	// identically.
+	Canonical bool `json:"canonical,omitempty"`
+
+	// CSE assigns operations repeated in the schedule to new temporaries,
+	// computing each once.
+	CSE bool `json:"cse,omitempty"`
+
+	// InPlaceTag gates the transform behind the inplace build tag, which
+	// selects a variant specialized for in-place use instead.
+	InPlaceTag bool `json:"inPlaceTag,omitempty"`
+
+	// Locality reorders independent statements so consecutive memory
+	// accesses touch nearby indices.
+	Locality bool `json:"locality,omitempty"`
+
+	// Transformer adds a type implementing the Transformer interface with a
+	// complex transform, for callers that mock transforms in tests.
+	Transformer bool `json:"transformer,omitempty"`
+
+	// Frame adds a method on the Frame type computing a float transform of
+	// the frame in-place.
+	Frame bool `json:"frame,omitempty"`
+
+	// OutputBase adds an obase argument offsetting every output index.
+	OutputBase bool `json:"outputBase,omitempty"`
+
+	// StrideCheck panics upfront if a strided slice is too short for the
+	// elements the transform accesses.
+	StrideCheck bool `json:"strideCheck,omitempty"`
+
+	// ReturnErrors makes the transform and its wrappers return an error for
+	// slices that are too short or overlap, rather than panicking.
+	ReturnErrors bool `json:"returnErrors,omitempty"`
+
+	// NoLenGuard omits the check at the top of the transform that its
+	// slices are long enough, for maximum speed.
+	NoLenGuard bool `json:"noLenGuard,omitempty"`
+
+	// Align panics upfront unless every slice starts on a multiple of this
+	// many bytes, as SIMD code using aligned loads requires.
+	Align int `json:"align,omitempty"`
+
+	// Residual adds a variant returning the relative difference between the
+	// input energy and the output energy over N, which Parseval's theorem
+	// says are equal.
+	Residual bool `json:"residual,omitempty"`
+
+	// PSD adds a variant computing the power spectral density of a real
+	// signal.
+	PSD bool `json:"psd,omitempty"`
+
+	// Magnitude adds a variant computing the magnitude spectrum of a real
+	// float32 signal.
+	Magnitude bool `json:"magnitude,omitempty"`
+
+	// Halfcomplex adds a variant computing the transform of a real signal in
+	// FFTW's halfcomplex layout.
+	Halfcomplex bool `json:"halfcomplex,omitempty"`
+
+	// CrossSpectrum adds a variant computing the cross-spectrum of two real
+	// signals.
+	CrossSpectrum bool `json:"crossSpectrum,omitempty"`
+
+	// ConvStep adds a variant computing one step of fast convolution with a
+	// kernel's spectrum.
+	ConvStep bool `json:"convStep,omitempty"`
+
+	// RealInput adds a runtime check dispatching float transforms whose
+	// imaginary input is all zero to a simplified path.
+	RealInput bool `json:"realInput,omitempty"`
+
+	// ImagInput adds the same check for float transforms whose real input is
+	// all zero.
+	ImagInput bool `json:"imagInput,omitempty"`
+
+	// Contraction controls fusing multiplies and adds, "fma" computes every
+	// sum of products with math.FMA and "none" rounds every product before
+	// it's added, so results are the same on every platform.
+	Contraction string `json:"contraction,omitempty"`
+
+	// FlushToZero flushes temporaries with magnitude below the threshold to
+	// zero, avoiding slow arithmetic on subnormals at the cost of accuracy.
+	FlushToZero float64 `json:"flushToZero,omitempty"`
+
+	// QuietNaN converts signaling NaNs read from the input to quiet NaNs.
+	QuietNaN bool `json:"quietNaN,omitempty"`
+
+	// SplitPasses computes every real output of a float schedule before any
+	// imaginary output.
+	SplitPasses bool `json:"splitPasses,omitempty"`
+
+	// Lanes groups runs of 2 or 4 independent statements performing the same
+	// operations into tuple assignments, structured for packing into vector
+	// lanes.
+	Lanes int `json:"lanes,omitempty"`
+
+	// Pad adds a variant zero-padding shorter input to the transform length
+	// and returning the result by value.
+	Pad bool `json:"pad,omitempty"`
+
+	// Batch adds a variant computing consecutive transforms of contiguous
+	// blocks, reporting progress to an optional callback.
+	Batch bool `json:"batch,omitempty"`
+
+	// BatchContext adds a batch variant checking a context for cancellation
+	// between transforms.
+	BatchContext bool `json:"batchContext,omitempty"`
+
+	// Ring adds a variant reading its input from a ring buffer starting at a
+	// given offset.
+	Ring bool `json:"ring,omitempty"`
+
+	// Columns adds a wrapper transforming each column of a row-major matrix.
+	Columns bool `json:"columns,omitempty"`
+
+	// Scaled adds a variant multiplying every output by a scale factor as
+	// it's written.
+	Scaled bool `json:"scaled,omitempty"`
+
+	// Scratch adds a variant keeping its temporaries in a caller-provided
+	// slice rather than on the stack.
+	Scratch bool `json:"scratch,omitempty"`
+
+	// Direction adds a wrapper taking the direction of a transform with a
+	// runtime sign as a Direction, and NormalizeInverse scales its inverse
+	// by 1/n.
+	Direction        bool `json:"direction,omitempty"`
+	NormalizeInverse bool `json:"normalizeInverse,omitempty"`
+
+	// Accessor adds a variant reading and writing its elements through the
+	// Accessor interface.
+	Accessor bool `json:"accessor,omitempty"`
+
+	// Plan adds a variant returning a plan built on its first call, which
+	// callers can reuse for later transforms.
+	Plan bool `json:"plan,omitempty"`
+
+	// Stream adds a wrapper reading input samples from an io.Reader and
+	// writing the transform to an io.Writer.
+	Stream bool `json:"stream,omitempty"`
+
+	// Indexed adds a variant reading and writing elements through maps from
+	// logical to physical slice positions.
+	Indexed bool `json:"indexed,omitempty"`
+
+	// Windows adds Hann, Hamming and Blackman window helpers of the
+	// transform's size to the package.
+	Windows bool `json:"windows,omitempty"`
+
+	// Views adds helpers reinterpreting interleaved []float64 as
+	// []complex128 and back without copying.
+	Views bool `json:"views,omitempty"`
+
+	// Compensated lowers additions of three or more terms to compensated
+	// sums, reducing rounding error for long add chains.
+	Compensated bool `json:"compensated,omitempty"`
+
+	// MinimizeLive reorders independent statements to reduce the number of
+	// temporaries live at once.
+	MinimizeLive bool `json:"minimizeLive,omitempty"`
+
+	// AssertSize emits a constant holding the transform's length and a
+	// compile-time assertion that it equals the given size.
+	AssertSize int `json:"assertSize,omitempty"`
+
+	// Expvar records each transform's call count and cumulative time in an
+	// expvar map.
+	Expvar bool `json:"expvar,omitempty"`
+
+	// DebugTiming logs the time taken by each call in builds with the debug
+	// tag, and compiles to nothing otherwise.
+	DebugTiming bool `json:"debugTiming,omitempty"`
+
+	// GoGenerate records a go:generate directive regenerating the
+	// transform in its file.
+	GoGenerate bool `json:"goGenerate,omitempty"`
+
+	// Inverse renders the inverse transform of a forward schedule, without
+	// scaling by 1/N.
+	Inverse bool `json:"inverse,omitempty"`
+
+	// Strided indexes inputs and outputs of a unit-stride schedule by is and
+	// os arguments, as FFTW's stride codelets do.
+	Strided bool `json:"strided,omitempty"`
+
+	// Dispatch includes the transform in its package's DftCmplx or DftFloat
+	// function, which calls the transform of the length it's given.
+	Dispatch bool `json:"dispatch,omitempty"`
+
+	// InlineTwiddles computes the twiddle factors of a twiddle codelet with
+	// math.Sincos in each iteration, given the length of the transform it's
+	// a pass of, rather than reading them from a precomputed W slice.
+	InlineTwiddles bool `json:"inlineTwiddles,omitempty"`
+
+	// Precision is the precision of the transform's slices, float64 by
+	// default or float32 for []float32 and []complex64 slices.
+	Precision string `json:"precision,omitempty"`
+
+	// Constants overrides the values of constants by name.
+	Constants map[string]string `json:"constants,omitempty"`
+}
+
+// Program is a list of constants and expressions.
+type Program struct {
+	Constants  []Constant
+	Statements []Expr `@@+`
+	Options    Options
+
+	// inPlace renders the variant specialized for in-place use.
+	inPlace bool
+
+	// accessor renders the variant reading and writing through accessors.
+	accessor bool
+
+	// prefix is the prefix of the schedule the program was parsed from.
+	prefix string
+
+	// pool collects the constants of transforms rendered to the same file,
+	// which share a package-level constant block.
+	pool *constPool
+}
+
+// Float reports whether the program is a float dft, one taking separate real
+// and imaginary slices rather than complex slices.
+func (p Program) Float() bool {
+	// For each statement.
+	for _, s := range p.Statements {
+		// If the inputs contain "ri", it's a float dft.
+		for _, input := range s.Inputs() {
+			if input == "ri" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// TransformLength returns the size of the transform, one more than the
+// largest input index.
+func (p Program) TransformLength() (n int) {
+	for _, s := range p.Statements {
+		for _, id := range s.Idents() {
+			name, k, ok := elementIndex(id)
+			if !ok || (name != "xi" && name != "ri") {
+				continue
+			}
+
+			if k >= n {
+				n = k + 1
+			}
+		}
+	}
+
+	return
+}
+
+// Args returns the program's slice arguments and their element type.
+func (p Program) Args() (args []jen.Code, argType jen.Code) {
+	single := p.Options.Precision == "float32"
+
+	if p.Float() {
+		// Float dfts take separate real and imaginary slices.
+		args = []jen.Code{
+			jen.Id("ri"), jen.Id("ii"),
+			jen.Id("ro"), jen.Id("io"),
+		}
+		if single {
+			return args, jen.Float32()
+		}
+		return args, jen.Float64()
+	}
+
+	// Otherwise it's a complex dft.
+	if single {
+		return []jen.Code{jen.Id("xi"), jen.Id("xo")}, jen.Complex64()
+	}
+	return []jen.Code{jen.Id("xi"), jen.Id("xo")}, jen.Complex128()
+}
+
+// Gen creates a go-representation of the program, like Render, for callers
+// whose options are known to render. It panics with Render's error rather
+// than exiting, so a caller can still recover from it.
+func (p Program) Gen(path, name string) *jen.File {
+	f, err := p.Render(path, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return f
+}
+
+// Render creates a go-representation of the program, or returns an error if
+// its options can't be rendered together or don't suit its schedule.
+func (p Program) Render(path, name string) (*jen.File, error) {
+	f := jen.NewFilePathName(path, path)
+	if err := p.renderTo(f, name); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// renderTo renders the program as the function name and its wrappers in f.
+func (p Program) renderTo(f *jen.File, name string) error {
+	if err := p.checkOperators(); err != nil {
+		return err
+	}
+
+	if err := p.Options.checkPrecision(name); err != nil {
+		return err
+	}
+
+	if err := p.Options.checkInverse(name); err != nil {
+		return err
+	}
+
+	if p.Twiddle() {
+		if err := p.checkTwiddle(name); err != nil {
+			return err
+		}
+	} else if p.Options.InlineTwiddles {
+		return fmt.Errorf("%s: inline twiddles require a twiddle codelet", name)
+	}
+
+	if p.Options.TinyGo {
+		opts, err := p.Options.tinyGo(name, len(p.Statements))
+		if err != nil {
+			return err
+		}
+		p.Options = opts
+	}
+
+	var err error
+	if p.Options.Strided {
+		if p.Statements, err = p.stridedStatements(name); err != nil {
+			return err
+		}
+	}
+
+	args, argType := p.Args()
+
+	// Add arguments, and their type ([]float64, []complex128).
+	params := []jen.Code{jen.List(args...).Index().Add(argType)}
+	if strides := p.Strides(); len(strides) > 0 {
+		params = append(params, strideParams(strides))
+	}
+	if p.Options.OutputBase {
+		params = append(params, jen.Id("obase").Int())
+	}
+	if p.Options.RuntimeSign {
+		params = append(params, jen.Id("sign").Int())
+	}
+	if p.Options.SignMultiplier {
+		if p.Options.RuntimeSign || p.Options.Generic || p.Options.Stages > 1 {
+			return fmt.Errorf("%s: sign multipliers require a single non-generic function without runtimeSign", name)
+		}
+		params = append(params, jen.Id("sign").Float64())
+	}
+
+	// Always include the imaginary constant first, unless it's chosen at
+	// runtime or lowered to a method call. Other constants are untyped, so
+	// take the precision of the slices they multiply.
+	if !p.Float() && !p.Options.RuntimeSign && !p.Options.SignMultiplier && !p.Options.Generic {
+		i := Constant{"I", "1i"}
+		if p.Options.Inverse {
+			i.Value = "-1i"
+		}
+		if p.Options.Precision == "float32" {
+			i.Value = "complex64(" + i.Value + ")"
+		}
+		p.Constants = append([]Constant{i}, p.Constants...)
+	}
+
+	if p.Options.Inverse && p.Float() {
+		p.Statements = p.invertedStatements()
+	}
+
+	if len(p.Options.Constants) > 0 {
+		p.Constants = p.overrideConstants(name)
+	}
+
+	if p.Options.Canonical {
+		p.Statements = p.canonicalOrder()
+	}
+
+	if p.Options.CSE {
+		p.Statements = p.eliminateCommon()
+	}
+
+	if p.Options.BitReverse {
+		if p.Statements, err = p.bitReverseOutputs(name); err != nil {
+			return err
+		}
+	}
+
+	if p.Options.Locality {
+		p.Statements = p.localityOrder()
+	}
+
+	if p.Options.MinimizeLive {
+		p.Statements = p.liveOrder()
+	}
+
+	if p.Options.SplitPasses {
+		if p.Statements, err = p.splitOrder(name); err != nil {
+			return err
+		}
+	}
+
+	if p.Options.Lanes > 0 {
+		if p.Statements, err = p.laneOrder(name); err != nil {
+			return err
+		}
+	}
+
+	if p.Options.Contraction != "" && p.Options.Generic {
+		return fmt.Errorf("%s: contraction control requires a non-generic function", name)
+	}
+	switch p.Options.Contraction {
+	case "":
+	case "fma":
+		if p.Statements, err = p.contract(name); err != nil {
+			return err
+		}
+	case "none":
+		p.Statements = p.roundProducts()
+	default:
+		return fmt.Errorf("%s: unknown contraction %q", name, p.Options.Contraction)
+	}
+
+	if p.Options.FlushToZero != 0 {
+		if p.Statements, err = p.flushIntermediates(name, p.Options.FlushToZero); err != nil {
+			return err
+		}
+	}
+
+	if p.Options.QuietNaN {
+		if p.Statements, err = p.quietInputs(name); err != nil {
+			return err
+		}
+	}
+
+	// Constants the schedule no longer references, or never did, are dropped.
+	p.Constants = p.referencedConstants()
+	if p.pool != nil {
+		if err := p.pool.add(name, p.Constants); err != nil {
+			return err
+		}
+	}
+
+	// Wrappers index outputs from the start of their slices.
+	wrapped := p
+	if p.Options.OutputBase {
+		p.Statements = p.offsetOutputs()
+	}
+
+	if p.Options.InPlaceTag {
+		f.HeaderComment(inPlaceConstraint(p.inPlace))
+	}
+	if p.Options.GoGenerate {
+		f.HeaderComment(goGenerateDirective(p.prefix))
+	}
+
+	if (p.Options.RealInput || p.Options.ImagInput) && (p.Options.Generic || p.Options.Stages > 1) {
+		return fmt.Errorf("%s: the real and imaginary input paths require a single non-generic function", name)
+	}
+
+	if (p.Options.StrideCheck || p.Options.Align != 0 || p.Options.ReturnErrors) && (p.Options.Generic || p.Options.Stages > 1) {
+		return fmt.Errorf("%s: stride checks, alignment checks and error returns require a single non-generic function", name)
+	}
+
+	switch {
+	// Render twiddle codelets inside the loop over their transforms.
+	case p.Twiddle():
+		p.genTwiddle(f, name)
+
+	// Render generic transforms in terms of method calls.
+	case p.Options.Generic:
+		err = p.genGeneric(f, name)
+
+	// Split large transforms into helper functions.
+	case p.Options.Stages > 1:
+		p.genStages(f, name, args, argType, params)
+
+	default:
+		doc := p.funcDoc(name)
+		if p.inPlace {
+			doc = []string{inPlaceDoc(name, p.Float())}
+		}
+		err = p.genFunc(f, name, doc, params)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Add convenience wrappers around the transform.
+	if err := wrapped.genWrappers(f, name); err != nil {
+		return err
+	}
+
+	if p.Options.AssertSize > 0 {
+		wrapped.genAssertSize(f, name)
+	}
+
+	return nil
+}
+
+// genFunc renders the program as a single named function documented by the
+// lines of doc.
+func (p Program) genFunc(f *jen.File, name string, doc []string, params []jen.Code) error {
+	if p.Options.Expvar {
+		genMetricsVar(f, name)
+	}
+	for _, line := range doc {
+		f.Comment(line)
+	}
+
+	// Define a named function.
+	fn := f.Func().Id(name).Params(params...)
+	if p.Options.ReturnErrors {
+		fn.Error()
+	}
+
+	// Checks failing partway through leave the function incomplete, but it's
+	// discarded along with the file.
+	var err error
+	fn.BlockFunc(func(g *jen.Group) {
+		if p.Options.Expvar {
+			genMetrics(g, name)
+		}
+
+		if p.Options.DebugTiming {
+			genTiming(g, name)
+		}
+
+		if p.Options.NoAlias && !p.Options.ReturnErrors {
+			genAssertDisjoint(g, !p.Float())
+		}
+
+		if !p.Options.NoLenGuard && !p.accessor {
+			p.genLenGuard(g, name)
+		}
+
+		if p.Options.StrideCheck {
+			if err = p.genStrideCheck(g, name); err != nil {
+				return
+			}
+		}
+
+		if p.Options.ReturnErrors && !p.accessor {
+			p.genOverlapErrors(g, name)
+		}
+
+		if p.Options.Align != 0 {
+			if err = p.genAssertAligned(g, name); err != nil {
+				return
+			}
+		}
+
+		if p.Options.RuntimeSign && p.Float() {
+			genSignSwap(g)
+		}
+
+		if p.inPlace {
+			p.genInPlaceCopy(g)
+		}
+
+		p.genConstants(g)
+
+		if p.Options.RuntimeSign && uses(p.Statements, "I") {
+			genSignI(g)
+		}
+
+		if p.Options.SignMultiplier && (p.Float() || uses(p.Statements, "I")) {
+			genSignMultiplier(g, !p.Float())
+		}
+
+		if p.Options.RealInput {
+			if err = p.genRealInput(g, name); err != nil {
+				return
+			}
+		}
+
+		if p.Options.ImagInput {
+			if err = p.genImagInput(g, name); err != nil {
+				return
+			}
+		}
+
+		// Render the statements.
+		if p.Options.Lanes > 0 {
+			for _, group := range laneGroups(p.Statements, p.Options.Lanes) {
+				g.Add(p.genLanes(group))
+			}
+		} else {
+			for _, expr := range p.Statements {
+				g.Add(p.genStatement(expr))
+			}
+		}
+
+		if p.Options.ReturnErrors {
+			g.Return(jen.Nil())
+		}
+	})
+
+	return err
+}
+
+// mapStatement returns a statement of the program with the identifiers it
+// reads adjusted for how the transform is rendered.
+func (p Program) mapStatement(expr Expr) Expr {
+	if p.Options.SignMultiplier && p.Float() {
+		expr = signMultiplied(expr)
+	}
+	if p.inPlace {
+		expr = expr.MapIdents(readOutputs)
+	}
+	return expr
+}
+
+// genStatement renders a statement of the program.
+func (p Program) genStatement(expr Expr) *jen.Statement {
+	expr = p.mapStatement(expr)
+	if p.accessor {
+		return genAccessed(expr)
+	}
+	if p.Options.Compensated {
+		return expr.GenCompensated(compensatedSum(p.Float()))
+	}
+	return expr.Gen()
+}
+
+// genConstants renders the program's constant block, if it has any that
+// aren't shared with other transforms.
+func (p Program) genConstants(g *jen.Group) {
+	constants := p.Constants
+	if p.pool != nil {
+		constants = nil
+		for _, c := range p.Constants {
+			if !c.pooled() {
+				constants = append(constants, c)
+			}
+		}
+	}
+	if len(constants) == 0 {
+		return
+	}
+
+	// Render them.
+	g.Add(jen.Const().DefsFunc(func(d *jen.Group) {
+		for _, c := range constants {
+			d.Add(c.Gen())
+		}
+	}))
+
+	// Add a blank line.
+	g.Line()
+}
+
+// Constant is a named value.
+type Constant struct {
+	Name  string
+	Value string
+}
+
+func (c Constant) Gen() (s *jen.Statement) {
+	return jen.Id(c.Name).Op("=").Id(c.Value)
+}
+
+// Expr is an Ident or an Op and at least one sub-expression.
+type Expr struct {
+	Pos lexer.Position
+
+	Ident string `@Id |`
+	Op    string `"(" @(Op | Id)`
+	Sub   []Expr `@@+ ")"`
+}
+
+func (e Expr) String() string {
+	if e.Ident != "" {
+		return fmt.Sprintf("%q", e.Ident)
+	}
+
+	return fmt.Sprintf("{Op:%q Sub:%+v}", e.Op, e.Sub)
+}
+
+// Inputs walks an expression tree and returns a list of identifiers with indices.
+func (e Expr) Inputs() (i []string) {
+	idx := strings.IndexByte(e.Ident, '[')
+
+	if idx != -1 {
+		i = append(i, e.Ident[:idx])
+	}
+
+	for _, sub := range e.Sub {
+		i = append(i, sub.Inputs()...)
+	}
+
+	return
+}
+
+// splitIndex splits an indexed identifier such as "xi[3]" into its name and index.
+func splitIndex(id string) (name string, k int, ok bool) {
+	idx := strings.IndexByte(id, '[')
+	if idx == -1 || !strings.HasSuffix(id, "]") {
+		return "", 0, false
+	}
+
+	k, err := strconv.Atoi(id[idx+1 : len(id)-1])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return id[:idx], k, true
+}
+
+// binary reports whether e is an operator with two or more sub-expressions.
+func (e Expr) binary() bool {
+	return len(e.Sub) > 1
+}
+
+// additive reports whether e is a binary addition or subtraction.
+func (e Expr) additive() bool {
+	return e.binary() && (e.Op == "+" || e.Op == "-")
+}
+
+// Precedences of rendered expressions, ordered as in Go's grammar.
+const (
+	precAssign = iota
+	precSum
+	precProduct
+	precUnary
+	precOperand
+)
+
+// precedence returns how tightly the rendering of an expression binds.
+// Identifiers, and operators with a registered lowering, which render as
+// calls, are operands needing no parentheses.
+func (e Expr) precedence() int {
+	if e.Ident != "" {
+		return precOperand
+	}
+	if _, ok := lowerings[e.Op]; ok {
+		return precOperand
+	}
+	if len(e.Sub) == 1 {
+		return precUnary
+	}
+
+	switch e.Op {
+	case ":=":
+		return precAssign
+	case "*":
+		return precProduct
+	}
+	return precSum
+}
+
+// genOperand renders a sub-expression, wrapped in parentheses if it binds
+// less tightly than prec.
+func genOperand(sub Expr, prec int) *jen.Statement {
+	if sub.precedence() < prec {
+		return jen.Parens(sub.Gen())
+	}
+	return sub.Gen()
+}
+
+// Gen renders a go-representation of an expression, with only the
+// parentheses its operators' precedence requires. Go's binary operators are
+// left-associative, so right operands binding no tighter than their operator
+// are wrapped to preserve the schedule's evaluation order.
+func (e Expr) Gen() (c *jen.Statement) {
+	// Expressions with an identifier are just that identifier.
+	if e.Ident != "" {
+		return genIdent(e.Ident)
+	}
+
+	// Operators with a registered lowering render as it says.
+	if c, ok := e.lower(Expr.Gen); ok {
+		return c
+	}
+
+	prec := e.precedence()
+
+	// Expressions with only one sub-expression render the operator and that
+	// sub-expression. Negation is exact, so negating a product may negate its
+	// first factor instead, but a sum or another negation must be wrapped.
+	if len(e.Sub) == 1 {
+		if sub := e.Sub[0]; sub.precedence() == precUnary {
+			return jen.Op(e.Op).Parens(sub.Gen())
+		}
+		return jen.Op(e.Op).Add(genOperand(e.Sub[0], precProduct))
+	}
+
+	// When the left side of an expression is an indexed identifier, assign only.
+	if e.Op == ":=" && strings.HasSuffix(e.Sub[0].Ident, "]") {
+		e.Op = "="
+	}
+
+	lt := genOperand(e.Sub[0], prec)
+	for _, sub := range e.Sub[1:] {
+		// Flatten add followed by unary subtraction, subtracting the whole
+		// operand.
+		if e.Op == "+" && sub.Op == "-" && sub.precedence() == precUnary {
+			lt.Add(jen.Op("-")).Add(genOperand(sub.Sub[0], prec+1))
+			continue
+		}
+
+		lt.Add(jen.Op(e.Op)).Add(genOperand(sub, prec+1))
+	}
+
+	return lt
+}
+
+// Dft describes a single transform to generate.
+type Dft struct {
+	// Prefix may be a glob, generating a transform for each schedule it
+	// matches, named DftCmplxN or DftFloatN after the size the schedule's
+	// filename ends with.
+	Prefix string `json:"prefix"`
+	Func   string `json:"func"`
+
+	// Corpus generates a function for each section of the schedule file,
+	// named by the section's marker.
+	Corpus bool `json:"corpus,omitempty"`
+
+	Options
+
+	// corpus is the prefix of the corpus a section's schedule is read from.
+	corpus string
+}
+
+// Token rules for schedule files.
+var def = stateful.MustSimple([]stateful.Rule{
+	{Name: "Lt", Pattern: `\(`},
+	{Name: "Rt", Pattern: `\)`},
+	{Name: "Id", Pattern: `[a-zA-Z][a-zA-Z0-9_]*(\[(\d+|WS\(\s*[a-zA-Z]\w*\s*,\s*\d+\s*\))\])?`},
+	{Name: "Op", Pattern: `(:=|[+\-*])`},
+	{Name: "eol", Pattern: `[\r\n]+`},
+	{Name: "sp", Pattern: `\s+`},
+})
+
+// Constant regular expression.
+var constRe = regexp.MustCompile(`^\s*DV?K\((.*?), (.*?)\);$`)
+
+// Start of a constant definition, which may continue onto following lines.
+var constStartRe = regexp.MustCompile(`^\s*DV?K\(`)
+
+// Build a parser from main.Program
+var parser = participle.MustBuild(&Program{}, participle.Lexer(def))
+
+// ParseConstants scans C output for DK and DVK constant definitions.
+func ParseConstants(r io.Reader) (constants []Constant) {
+	// Create a new line scanner.
+	coutScanner := bufio.NewScanner(r)
+
+	// Scan lines from the C output.
+	for coutScanner.Scan() {
+		line := coutScanner.Text()
+
+		// Join constant definitions wrapped across several lines.
+		for constStartRe.MatchString(line) && !strings.HasSuffix(strings.TrimSpace(line), ";") && coutScanner.Scan() {
+			line = strings.TrimRight(line, " \t")
+			if !strings.HasSuffix(line, "(") {
+				line += " "
+			}
+			line += strings.TrimSpace(coutScanner.Text())
+		}
+
+		// If the line isn't a constant, bail.
+		if !constRe.MatchString(line) {
+			continue
+		}
+
+		// Parse the constant and append it to the list.
+		m := constRe.FindStringSubmatch(line)
+		constants = append(
+			constants,
+			Constant{Name: m[1], Value: m[2]}.signed(),
+		)
+	}
+
+	return
+}
+
+// Generate parses the schedule and constants for a dft and renders them as go.
+func (dft Dft) Generate() (*jen.File, error) {
+	prog, err := dft.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return prog.Render("dft", dft.Func)
+}
+
+// parseSchedule parses a schedule and any constants defined inline in it,
+// naming the schedule by filename in errors.
+func parseSchedule(filename string, alst []byte) (*Program, error) {
+	prog := &Program{}
+
+	err := parser.Parse(filename, bytes.NewReader(stripConstants(alst)), prog)
+	if err != nil {
+		return nil, fmt.Errorf("parser.Parse: %w", err)
+	}
+
+	// Constants may be defined inline in the schedule.
+	prog.Constants = ParseConstants(bytes.NewReader(alst))
+
+	return prog, nil
+}
+
+// Parse parses the schedule and constants for a dft.
+func (dft Dft) Parse() (*Program, error) {
+	alstFilename := dft.Prefix + ".alst"
+	coutFilename := dft.Prefix + ".cout"
+	if dft.corpus != "" {
+		alstFilename = dft.corpus + ".alst"
+		coutFilename = dft.corpus + ".cout"
+	}
+
+	// Read the schedule file.
+	alst, err := os.ReadFile(alstFilename)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	// Sections of a corpus declare their size instead of C output does.
+	size := 0
+	if dft.corpus != "" {
+		alst, size, err = corpusSection(alst, dft.Func)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", alstFilename, err)
+		}
+	}
+
+	prog, err := parseSchedule(alstFilename, alst)
+	if err != nil {
+		return nil, err
+	}
+	prog.Options = dft.Options
+	prog.prefix = dft.Prefix
+
+	// Read the C output, which is optional if the schedule defines its
+	// constants inline or is shared by a corpus.
+	cout, err := os.ReadFile(coutFilename)
+	if err != nil && !(os.IsNotExist(err) && (len(prog.Constants) > 0 || dft.corpus != "")) {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	prog.Constants = mergeConstants(prog.Constants, ParseConstants(bytes.NewReader(cout)))
+
+	// Catch schedules paired with constants for a different transform.
+	if dft.corpus != "" {
+		if length := prog.TransformLength(); length != size {
+			return nil, fmt.Errorf("%s: section %s declares size %d, but the schedule has length %d", alstFilename, dft.Func, size, length)
+		}
+	} else if err := prog.CheckSize(bytes.NewReader(cout)); err != nil {
+		return nil, fmt.Errorf("%s and %s: %w", alstFilename, coutFilename, err)
+	}
+
+	// Catch malformed schedules before they render uncompilable go.
+	if err := prog.CheckTemporaries(); err != nil {
+		return nil, fmt.Errorf("%s: %w", alstFilename, err)
+	}
+
+	return prog, nil
+}
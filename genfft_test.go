@@ -0,0 +1,394 @@
+package genfft
+
+import (
+	"fmt"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"math"
+	"math/cmplx"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// naiveSchedule builds an unoptimized O(n^2) schedule and its constants in the
+// same format as FFTW's genfft, for testing sizes without a committed schedule.
+func naiveSchedule(n int, float bool) (alst, cout string) {
+	var a, c strings.Builder
+
+	// konst returns the name of a constant for |v|, declaring it if necessary.
+	declared := map[string]bool{}
+	konst := func(v float64) string {
+		v = math.Abs(v)
+		name := fmt.Sprintf("KP%09d", int64(math.Round(v*1e9)))
+		if !declared[name] {
+			declared[name] = true
+			fmt.Fprintf(&c, "DVK(%s, %+.45f);\n", name, v)
+		}
+		return name
+	}
+
+	// scale returns term multiplied by v, or nothing if v is zero.
+	scale := func(v float64, term string) []string {
+		switch {
+		case math.Abs(v) < 1e-12:
+			return nil
+		case math.Abs(v-1) < 1e-12:
+			return []string{term}
+		case math.Abs(v+1) < 1e-12:
+			return []string{"(- " + term + ")"}
+		case v < 0:
+			return []string{fmt.Sprintf("(- (* %s %s))", konst(v), term)}
+		}
+		return []string{fmt.Sprintf("(* %s %s)", konst(v), term)}
+	}
+
+	sum := func(terms []string) string {
+		if len(terms) == 1 {
+			return terms[0]
+		}
+		return "(+ " + strings.Join(terms, " ") + ")"
+	}
+
+	re := func(j int) string { return fmt.Sprintf("T%d", j+1) }
+	im := func(j int) string { return fmt.Sprintf("T%d", n+j+1) }
+
+	for j := 0; j < n; j++ {
+		if float {
+			fmt.Fprintf(&a, "(:= %s ri[%d])\n", re(j), j)
+			fmt.Fprintf(&a, "(:= %s ii[%d])\n", im(j), j)
+		} else {
+			fmt.Fprintf(&a, "(:= %s xi[%d])\n", re(j), j)
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		var rt, it []string
+		for j := 0; j < n; j++ {
+			theta := 2 * math.Pi * float64(j*k%n) / float64(n)
+			cos, sin := math.Cos(theta), math.Sin(theta)
+			if float {
+				rt = append(rt, scale(cos, re(j))...)
+				rt = append(rt, scale(sin, im(j))...)
+				it = append(it, scale(cos, im(j))...)
+				it = append(it, scale(-sin, re(j))...)
+			} else {
+				rt = append(rt, scale(cos, re(j))...)
+				rt = append(rt, scale(-sin, "(* I "+re(j)+")")...)
+			}
+		}
+
+		if float {
+			fmt.Fprintf(&a, "(:= ro[%d] %s)\n", k, sum(rt))
+			fmt.Fprintf(&a, "(:= io[%d] %s)\n", k, sum(it))
+		} else {
+			fmt.Fprintf(&a, "(:= xo[%d] %s)\n", k, sum(rt))
+		}
+	}
+
+	return a.String(), c.String()
+}
+
+// writeSchedule writes a schedule and its constants to dir, returning their prefix.
+func writeSchedule(t *testing.T, dir, name, alst, cout string) string {
+	t.Helper()
+	prefix := filepath.Join(dir, name)
+	if err := os.WriteFile(prefix+".alst", []byte(alst), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prefix+".cout", []byte(cout), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return prefix
+}
+
+// testPrelude is shared by every scratch package built by goTest.
+const testPrelude = `package dft
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+)
+
+func naiveDFT(f []complex128, sign float64) {
+	n := len(f)
+	h := make([]complex128, n)
+	phi := sign * 2.0 * math.Pi / float64(n)
+	for w := 0; w < n; w++ {
+		var t complex128
+		for k := 0; k < n; k++ {
+			t += f[k] * cmplx.Rect(1, phi*float64(k)*float64(w))
+		}
+		h[w] = t
+	}
+	copy(f, h)
+}
+
+func dftError(i, j []complex128) float64 {
+	var err float64
+	for idx := range i {
+		err += cmplx.Abs(i[idx] - j[idx])
+	}
+	return err / float64(len(i))
+}
+
+func randCmplx(n int) []complex128 {
+	out := make([]complex128, n)
+	for idx := range out {
+		out[idx] = complex(rand.NormFloat64(), rand.NormFloat64())
+	}
+	return out
+}
+`
+
+// scratchMod returns a go.mod for a scratch package of generated code, with
+// the go directive of genfft's own go.mod, which covers every language
+// feature the generator may emit.
+func scratchMod(t *testing.T) []byte {
+	t.Helper()
+	mod, err := os.ReadFile("go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(string(mod), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "go" {
+			return []byte("module dft\n\ngo " + fields[1] + "\n")
+		}
+	}
+
+	t.Fatal("go.mod has no go directive")
+	return nil
+}
+
+// goTest saves generated files into a scratch package along with test source
+// and runs the package's tests, passing any extra arguments to go test.
+func goTest(t *testing.T, files map[string]*jen.File, test string, args ...string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "go.mod"), scratchMod(t), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, f := range files {
+		if err := f.Save(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%+v\n", fmt.Errorf("f.Save: %w", err))
+		}
+	}
+
+	for name, src := range map[string]string{
+		"prelude_test.go": testPrelude,
+		"dft_test.go":     test,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	args = append(append([]string{"test", "-count=1"}, args...), ".")
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test: %v\n%s", err, out)
+	}
+}
+
+// mustParse parses the schedule and constants for a dft, failing the test on
+// any error.
+func mustParse(t *testing.T, dft Dft) *Program {
+	t.Helper()
+	prog, err := dft.Parse()
+	if err != nil {
+		t.Fatalf("%+v\n", err)
+	}
+	return prog
+}
+
+// mustGenerate generates a dft, failing the test on any error.
+func mustGenerate(t *testing.T, dft Dft) *jen.File {
+	t.Helper()
+	f, err := dft.Generate()
+	if err != nil {
+		t.Fatalf("%+v\n", err)
+	}
+	return f
+}
+
+// mustRender renders a program as the function name, failing the test on any
+// error.
+func mustRender(t *testing.T, prog *Program, name string) *jen.File {
+	t.Helper()
+	f, err := prog.Render("dft", name)
+	if err != nil {
+		t.Fatalf("%+v\n", err)
+	}
+	return f
+}
+
+// mustRenderInPlace renders the in-place variant of a program as the
+// function name, failing the test on any error.
+func mustRenderInPlace(t *testing.T, prog *Program, name string) *jen.File {
+	t.Helper()
+	f, err := prog.RenderInPlace("dft", name)
+	if err != nil {
+		t.Fatalf("%+v\n", err)
+	}
+	return f
+}
+
+// parseExpr parses a single schedule expression.
+func parseExpr(t *testing.T, s string) Expr {
+	t.Helper()
+	prog := &Program{}
+	err := parser.ParseString("", s, prog)
+	if err != nil {
+		t.Fatalf("%+v\n", fmt.Errorf("parser.ParseString: %w", err))
+	}
+	return prog.Statements[0]
+}
+
+// evalExpr evaluates an expression tree directly from the schedule.
+func evalExpr(e Expr, env map[string]complex128) complex128 {
+	if e.Ident != "" {
+		return env[e.Ident]
+	}
+
+	if len(e.Sub) == 1 {
+		return -evalExpr(e.Sub[0], env)
+	}
+
+	v := evalExpr(e.Sub[0], env)
+	for _, sub := range e.Sub[1:] {
+		switch e.Op {
+		case "+":
+			v += evalExpr(sub, env)
+		case "-":
+			v -= evalExpr(sub, env)
+		case "*":
+			v *= evalExpr(sub, env)
+		}
+	}
+	return v
+}
+
+// evalGo evaluates a rendered go expression.
+func evalGo(t *testing.T, src string, env map[string]complex128) complex128 {
+	t.Helper()
+	node, err := goparser.ParseExpr(src)
+	if err != nil {
+		t.Fatalf("%+v\n", fmt.Errorf("parser.ParseExpr: %w", err))
+	}
+
+	var eval func(n ast.Expr) complex128
+	eval = func(n ast.Expr) complex128 {
+		switch n := n.(type) {
+		case *ast.Ident:
+			return env[n.Name]
+		case *ast.BasicLit:
+			v, err := strconv.ParseFloat(n.Value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return complex(v, 0)
+		case *ast.ParenExpr:
+			return eval(n.X)
+		case *ast.UnaryExpr:
+			return -eval(n.X)
+		case *ast.BinaryExpr:
+			x, y := eval(n.X), eval(n.Y)
+			switch n.Op {
+			case token.ADD:
+				return x + y
+			case token.SUB:
+				return x - y
+			case token.MUL:
+				return x * y
+			}
+		}
+		t.Fatalf("unsupported expression: %T", n)
+		return 0
+	}
+
+	return eval(node)
+}
+
+func TestExprGen(t *testing.T) {
+	env := map[string]complex128{
+		"I": 1i,
+		"A": 0.5, "B": -1.25, "C": 3.0, "D": 0.75, "E": -2.5,
+		"K":  0.866025403784438646763723170752936183471402627,
+		"T1": 1 + 2i, "T2": -3 + 0.5i, "T3": 0.25 - 1i, "T4": 2 - 2i,
+	}
+
+	testCases := []struct {
+		alst string
+		want string
+	}{
+		// Three-level multiply-add trees.
+		{"(+ (* A (+ (* B C) D)) E)", "A*(B*C+D) + E"},
+		{"(+ E (* A (+ (* B C) (- D))))", "E + A*(B*C-D)"},
+		{"(+ (* (+ (* A B) C) D) E)", "(A*B+C)*D + E"},
+		{"(* I (* K (+ T3 (- T2))))", "I * (K * (T3 - T2))"},
+		{"(+ T1 (- (* K (+ T2 (* A (+ T3 T4))))))", "T1 - K*(T2+A*(T3+T4))"},
+
+		// Subtracting sums must subtract every term.
+		{"(+ T1 (- (+ T2 T3)))", "T1 - (T2 + T3)"},
+		{"(+ T1 (- (+ T2 T3 (* K T4))))", "T1 - (T2 + T3 + K*T4)"},
+		{"(- (+ T1 T2))", "-(T1 + T2)"},
+		{"(- (- T1))", "-(-T1)"},
+
+		// Subtracted sums nested in products keep every term's sign.
+		{"(+ (* K (- T1 (+ T2 T3))) T4)", "K*(T1-(T2+T3)) + T4"},
+		{"(+ (* K (- T1 (+ T2 T3 T4))) A)", "K*(T1-(T2+T3+T4)) + A"},
+		{"(+ (* K (+ T1 (- (+ T2 T3 T4)))) A)", "K*(T1-(T2+T3+T4)) + A"},
+		{"(+ A (- (- T1 (+ T2 T3 T4))))", "A - (T1 - (T2 + T3 + T4))"},
+
+		// Right-hand sums preserve the schedule's evaluation order.
+		{"(+ T1 (+ T2 T3))", "T1 + (T2 + T3)"},
+
+		// Only operands binding less tightly than their operator are
+		// wrapped, and right operands binding no tighter.
+		{"(+ (* A B) (- C D))", "A*B + (C - D)"},
+		{"(* (+ A B) (* C D))", "(A + B) * (C * D)"},
+		{"(* (* A B) (+ C D))", "A * B * (C + D)"},
+		{"(- (+ A B) (* C D) E)", "A + B - C*D - E"},
+		{"(+ A (- (- B)))", "A - -B"},
+		{"(* A (- B))", "A * -B"},
+		{"(- (* A B))", "-A * B"},
+
+		// Assignments are never wrapped.
+		{"(:= T5 (+ T1 T2))", "T5 := T1 + T2"},
+		{"(:= xo[0] (+ T1 (- (+ T2 T3))))", "xo[0] = T1 - (T2 + T3)"},
+	}
+
+	for idx, tc := range testCases {
+		t.Run(strconv.Itoa(idx), func(t *testing.T) {
+			e := parseExpr(t, tc.alst)
+			got := fmt.Sprintf("%#v", e.Gen())
+			if got != tc.want {
+				t.Errorf("%s: got %q, want %q", tc.alst, got, tc.want)
+			}
+
+			// Only the right side of an assignment is evaluated.
+			if e.Op == ":=" {
+				e = e.Sub[1]
+				got = got[strings.Index(got, "= ")+2:]
+			}
+
+			want := evalExpr(e, env)
+			if v := evalGo(t, got, env); cmplx.Abs(v-want) > 1e-15 {
+				t.Errorf("%s: %q evaluates to %v, want %v", tc.alst, got, v, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package genfft
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// sizeSuffixRe matches the size a schedule's filename ends with.
+var sizeSuffixRe = regexp.MustCompile(`(\d+)$`)
+
+// globFunc names the function generated from a schedule matched by a glob
+// after its argument set and the size its filename ends with, falling back
+// to the transform's length if it doesn't end with one. Inverse transforms
+// are named DftCmplxInvN or DftFloatInvN, twiddle codelets TwFloatN, and
+// single precision transforms are suffixed F32.
+func globFunc(prefix string, prog *Program) string {
+	return globName(prefix, prog.Options, prog.TransformLength(), prog.Float(), prog.Twiddle())
+}
+
+// globName names the function generated from a schedule matched by a glob,
+// given the options, length and argument set of its transform.
+func globName(prefix string, opts Options, n int, float, twiddle bool) string {
+	if m := sizeSuffixRe.FindString(filepath.Base(prefix)); m != "" {
+		n, _ = strconv.Atoi(m)
+	}
+
+	kind := ""
+	if opts.Inverse {
+		kind = "Inv"
+	}
+
+	suffix := ""
+	if opts.Precision == "float32" {
+		suffix = "F32"
+	}
+
+	if twiddle {
+		return fmt.Sprintf("TwFloat%d", n)
+	}
+	if float {
+		return fmt.Sprintf("DftFloat%s%d%s", kind, n, suffix)
+	}
+	return fmt.Sprintf("DftCmplx%s%d%s", kind, n, suffix)
+}
+
+// scannedInputRe matches an element of an input slice in a schedule, with
+// its index, including FFTW's strided indices.
+var scannedInputRe = regexp.MustCompile(`\b(xi|ri|ii|W)\[(?:WS\(\w+, )?(\d+)`)
+
+// scannedFunc names the function generated from a schedule matched by a glob
+// that can't be parsed, from the slices its text indexes and the largest
+// input index, so generating it reports why rather than failing the glob.
+func scannedFunc(prefix string, opts Options) string {
+	alst, _ := os.ReadFile(prefix + ".alst")
+
+	n, float, twiddle := 0, false, false
+	for _, m := range scannedInputRe.FindAllStringSubmatch(string(alst), -1) {
+		switch m[1] {
+		case "W":
+			twiddle = true
+			continue
+		case "ri", "ii":
+			float = true
+		}
+		if k, _ := strconv.Atoi(m[2]); k >= n {
+			n = k + 1
+		}
+	}
+
+	return globName(prefix, opts, n, float, twiddle)
+}
+
+// expandGlobs replaces each transform whose prefix is a glob with one per
+// matching schedule, sharing its options. A glob matching nothing is warned
+// about and dropped. Matches that can't be parsed are kept, named by
+// scannedFunc, so they're reported along with any other failures.
+func expandGlobs(dfts []Dft) (expanded []Dft, err error) {
+	for _, dft := range dfts {
+		if !strings.ContainsAny(dft.Prefix, "*?[") {
+			expanded = append(expanded, dft)
+			continue
+		}
+
+		if dft.Func != "" && !dft.Corpus {
+			return nil, fmt.Errorf("%s: func is derived from each match of a glob prefix", dft.Prefix)
+		}
+
+		matches, err := filepath.Glob(dft.Prefix + ".alst")
+		if err != nil {
+			return nil, fmt.Errorf("filepath.Glob: %w", err)
+		}
+		if len(matches) == 0 {
+			log.Warnf("%s matches no schedules\n", dft.Prefix)
+			continue
+		}
+
+		for _, match := range matches {
+			matched := dft
+			matched.Prefix = strings.TrimSuffix(match, ".alst")
+
+			// Sections of a corpus are named by their markers instead.
+			if matched.Corpus {
+				expanded = append(expanded, matched)
+				continue
+			}
+
+			if prog, err := matched.Parse(); err == nil {
+				matched.Func = globFunc(matched.Prefix, prog)
+			} else {
+				matched.Func = scannedFunc(matched.Prefix, matched.Options)
+			}
+
+			expanded = append(expanded, matched)
+		}
+	}
+
+	return expanded, nil
+}
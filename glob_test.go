@@ -0,0 +1,130 @@
+package genfft
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, n := range []int{2, 5} {
+		alst, cout := naiveSchedule(n, false)
+		writeSchedule(t, dir, fmt.Sprintf("dft%d", n), alst, cout)
+	}
+	alst, cout := naiveSchedule(4, true)
+	writeSchedule(t, dir, "dft4", alst, cout)
+
+	config, err := json.Marshal([]Dft{
+		{Prefix: filepath.Join(dir, "dft*"), Options: Options{NoAlias: true}},
+		{Prefix: filepath.Join(dir, "missing*")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFilename := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configFilename, config, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dfts, err := LoadConfig(configFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"DftCmplx2", "DftFloat4", "DftCmplx5"}
+	if len(dfts) != len(expected) {
+		t.Fatalf("expected %d transforms, got %+v", len(expected), dfts)
+	}
+	for idx, dft := range dfts {
+		if dft.Func != expected[idx] {
+			t.Errorf("%s: expected func %s, got %s", dft.Prefix, expected[idx], dft.Func)
+		}
+		if !dft.NoAlias {
+			t.Errorf("%s: options weren't copied from the glob", dft.Prefix)
+		}
+	}
+}
+
+func TestGlobFunc(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	dfts, err := expandGlobs([]Dft{{Prefix: filepath.Join(dir, "cmplx_*"), Func: "DftCmplx3"}})
+	if err == nil {
+		t.Fatalf("expected an error for a glob naming its func, got %+v", dfts)
+	}
+
+	// Filenames not ending with a size are named after the transform's length.
+	if err := os.Rename(prefix+".alst", filepath.Join(dir, "cmplx.alst")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(prefix+".cout", filepath.Join(dir, "cmplx.cout")); err != nil {
+		t.Fatal(err)
+	}
+
+	dfts, err = expandGlobs([]Dft{{Prefix: filepath.Join(dir, "cmpl?")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfts) != 1 || dfts[0].Func != "DftCmplx3" {
+		t.Fatalf("expected DftCmplx3, got %+v", dfts)
+	}
+
+	// Single precision transforms are suffixed F32.
+	dfts, err = expandGlobs([]Dft{{Prefix: filepath.Join(dir, "cmpl?"), Options: Options{Precision: "float32"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfts) != 1 || dfts[0].Func != "DftCmplx3F32" {
+		t.Fatalf("expected DftCmplx3F32, got %+v", dfts)
+	}
+
+	// Inverse transforms are named DftCmplxInvN.
+	dfts, err = expandGlobs([]Dft{{Prefix: filepath.Join(dir, "cmpl?"), Options: Options{Inverse: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfts) != 1 || dfts[0].Func != "DftCmplxInv3" {
+		t.Fatalf("expected DftCmplxInv3, got %+v", dfts)
+	}
+}
+
+func TestGlobBrokenSchedule(t *testing.T) {
+	dir := t.TempDir()
+
+	alst, cout := naiveSchedule(2, false)
+	writeSchedule(t, dir, "dft2", alst, cout)
+
+	// A schedule truncated mid-statement, which can't be parsed.
+	alst, cout = naiveSchedule(4, true)
+	writeSchedule(t, dir, "dft29", alst[:strings.LastIndex(alst[:len(alst)/2], ")")], cout)
+
+	config, err := json.Marshal([]Dft{{Prefix: filepath.Join(dir, "dft*")}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFilename := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configFilename, config, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The broken schedule is kept, named from its text and filename.
+	dfts, err := LoadConfig(configFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dfts) != 2 || dfts[0].Func != "DftCmplx2" || dfts[1].Func != "DftFloat29" {
+		t.Fatalf("expected DftCmplx2 and DftFloat29, got %+v", dfts)
+	}
+
+	// So validating reports it alone.
+	problems := Validate(configFilename)
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "DftFloat29") {
+		t.Fatalf("expected one problem with DftFloat29, got %v", problems)
+	}
+}
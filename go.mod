@@ -1,10 +1,11 @@
 module github.com/bemasher/genfft
 
-go 1.16
+go 1.18
 
 require (
 	github.com/alecthomas/participle/v2 v2.0.0-alpha5
-	github.com/dave/jennifer v1.4.1
+	github.com/dave/jennifer v1.5.0
 	github.com/sirupsen/logrus v1.8.1
-	golang.org/x/sys v0.0.0-20210119212857-b64e53b001e4 // indirect
 )
+
+require golang.org/x/sys v0.0.0-20211019181941-9d821ace8654 // indirect
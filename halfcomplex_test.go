@@ -0,0 +1,68 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestHalfcomplex(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Halfcomplex: true}}),
+		"float_5.go": generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{Halfcomplex: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+// halfcomplex packs a spectrum as FFTW documents for r2hc transforms:
+// r0, r1, r2, ..., r(n/2), i((n+1)/2-1), ..., i2, i1.
+func halfcomplex(x []complex128) []float64 {
+	n := len(x)
+	hc := make([]float64, n)
+	for k := 0; k <= n/2; k++ {
+		hc[k] = real(x[k])
+	}
+	for k := 1; k < (n+1)/2; k++ {
+		hc[n-k] = imag(x[k])
+	}
+	return hc
+}
+
+func checkHalfcomplex(t *testing.T, name string, n int, transform func(in, out []float64)) {
+	x := randCmplx(n)
+	in := make([]float64, n)
+	for k := range x {
+		in[k] = real(x[k])
+		x[k] = complex(in[k], 0)
+	}
+	naiveDFT(x, -1.0)
+	want := halfcomplex(x)
+
+	out := make([]float64, n)
+	transform(in, out)
+	for k := range out {
+		if math.Abs(out[k]-want[k]) > 1e-12 {
+			t.Fatalf("%s: element %d: %g != %g", name, k, out[k], want[k])
+		}
+	}
+
+	// Transforming in place matches FFTW's in-place r2hc output.
+	transform(in, in)
+	for k := range in {
+		if in[k] != out[k] {
+			t.Fatalf("%s: in-place element %d: %g != %g", name, k, in[k], out[k])
+		}
+	}
+}
+
+func TestHalfcomplex(t *testing.T) {
+	checkHalfcomplex(t, "DftCmplx8Halfcomplex", 8, DftCmplx8Halfcomplex)
+	checkHalfcomplex(t, "DftFloat5Halfcomplex", 5, DftFloat5Halfcomplex)
+}
+`)
+}
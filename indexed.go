@@ -0,0 +1,47 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genIndexed renders a variant of the transform indexing its inputs through
+// inIdx and its outputs through outIdx.
+func (p Program) genIndexed(f *jen.File, name string) error {
+	indexed := name + "Indexed"
+
+	mapIndex := func(id string) string {
+		slice, k, ok := splitIndex(id)
+		if !ok {
+			return id
+		}
+		if isOutput(slice) {
+			return fmt.Sprintf("%s[outIdx[%d]]", slice, k)
+		}
+		return fmt.Sprintf("%s[inIdx[%d]]", slice, k)
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(mapIndex)
+	}
+	p.Statements = statements
+
+	// Index maps make the variant general even when built in-place.
+	p.inPlace = false
+
+	args, argType := p.Args()
+	extraParams, _ := p.passThrough()
+	params := append([]jen.Code{
+		jen.List(args...).Index().Add(argType),
+		jen.List(jen.Id("inIdx"), jen.Id("outIdx")).Index().Int(),
+	}, extraParams...)
+
+	f.Line()
+	doc := fmt.Sprintf(
+		"%s computes %s reading logical input k from position inIdx[k] and\nwriting logical output k to position outIdx[k].",
+		indexed, name,
+	)
+	return p.genFunc(f, indexed, []string{doc}, params)
+}
@@ -0,0 +1,60 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestIndexed(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Indexed: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Indexed: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestIndexed(t *testing.T) {
+	xi := randCmplx(8)
+	xo := make([]complex128, 8)
+	DftCmplx8(xi, xo)
+
+	identity := []int{0, 1, 2, 3, 4, 5, 6, 7}
+	got := make([]complex128, 8)
+	DftCmplx8Indexed(xi, got, identity, identity)
+	for k := range xo {
+		if got[k] != xo[k] {
+			t.Fatalf("identity maps: output %d is %v, want %v", k, got[k], xo[k])
+		}
+	}
+
+	// Store the input reversed and the output rotated.
+	inIdx := []int{7, 6, 5, 4, 3, 2, 1, 0}
+	outIdx := []int{3, 4, 5, 6, 7, 0, 1, 2}
+	permuted := make([]complex128, 8)
+	for k, pos := range inIdx {
+		permuted[pos] = xi[k]
+	}
+	DftCmplx8Indexed(permuted, got, inIdx, outIdx)
+	for k, pos := range outIdx {
+		if got[pos] != xo[k] {
+			t.Fatalf("permuted maps: output %d is %v, want %v", k, got[pos], xo[k])
+		}
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for k, pos := range inIdx {
+		ri[pos], ii[pos] = real(xi[k]), imag(xi[k])
+	}
+	ro, io := make([]float64, 8), make([]float64, 8)
+	DftFloat8Indexed(ri, ii, ro, io, inIdx, outIdx)
+	for k, pos := range outIdx {
+		if err := dftError([]complex128{complex(ro[pos], io[pos])}, xo[k:k+1]); err > 1e-13 {
+			t.Fatalf("float permuted maps: output %d error %g", k, err)
+		}
+	}
+}
+`)
+}
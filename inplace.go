@@ -0,0 +1,68 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// RenderInPlace creates a go-representation of the program specialized for
+// in-place use, built in place of Render's output with the inplace tag. It
+// has the same signature, but reads and writes only the output slices.
+func (p Program) RenderInPlace(path, name string) (*jen.File, error) {
+	if p.Options.Stages > 1 || p.Options.Generic || p.Options.OutputBase || p.Options.Strided || len(p.Strides()) > 0 {
+		return nil, fmt.Errorf("%s: in-place variants can't be staged, generic, offset or strided", name)
+	}
+
+	p.inPlace = true
+	return p.Render(path, name)
+}
+
+// inPlaceConstraint returns the build constraint selecting either the
+// in-place variant of a transform or the general one.
+func inPlaceConstraint(inPlace bool) string {
+	if inPlace {
+		return "//go:build inplace\n// +build inplace"
+	}
+	return "//go:build !inplace\n// +build !inplace"
+}
+
+// inPlaceDoc documents the in-place variant of a transform.
+func inPlaceDoc(name string, float bool) string {
+	if float {
+		return fmt.Sprintf("%s computes the transform in ro and io, first copying ri and ii into\nthem unless they're the same slices.", name)
+	}
+	return fmt.Sprintf("%s computes the transform in xo, first copying xi into it unless\nthey're the same slice.", name)
+}
+
+// inPlaceSlices maps each input slice to the output slice it's copied into.
+var inPlaceSlices = map[string]string{"xi": "xo", "ri": "ro", "ii": "io"}
+
+// readOutputs maps an identifier indexing an input slice to the same element
+// of the output slice the input is copied into.
+func readOutputs(id string) string {
+	slice, k, ok := splitIndex(id)
+	if out, isInput := inPlaceSlices[slice]; ok && isInput {
+		return fmt.Sprintf("%s[%d]", out, k)
+	}
+	return id
+}
+
+// genInPlaceCopy renders statements copying the inputs of an in-place
+// variant into its outputs, unless they're the same slices.
+func (p Program) genInPlaceCopy(g *jen.Group) {
+	n := p.TransformLength()
+
+	pairs := [][2]string{{"xi", "xo"}}
+	if p.Float() {
+		pairs = [][2]string{{"ri", "ro"}, {"ii", "io"}}
+	}
+
+	for _, pair := range pairs {
+		in, out := jen.Id(pair[0]), jen.Id(pair[1])
+		g.If(jen.Op("&").Add(in).Index(jen.Lit(0)).Op("!=").Op("&").Add(out).Index(jen.Lit(0))).Block(
+			jen.Copy(out.Clone().Index(jen.Empty(), jen.Lit(n)), in.Clone().Index(jen.Empty(), jen.Lit(n))),
+		)
+	}
+	g.Line()
+}
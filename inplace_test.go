@@ -0,0 +1,73 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestInPlaceTag(t *testing.T) {
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx8", Options: Options{InPlaceTag: true, Into: true}},
+		{Func: "DftFloat8", Options: Options{InPlaceTag: true, RuntimeSign: true}},
+	} {
+		alst, cout := naiveSchedule(8, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, t.TempDir(), dft.Func, alst, cout)
+		prog := mustParse(t, dft)
+		files[dft.Func+".go"] = mustRender(t, prog, dft.Func)
+		files[dft.Func+"_inplace.go"] = mustRenderInPlace(t, prog, dft.Func)
+	}
+
+	src := files["DftCmplx8_inplace.go"].GoString()
+	if !strings.HasPrefix(src, "//go:build inplace\n") || strings.Contains(src, "xi[1]") {
+		t.Fatalf("in-place variant isn't gated or reads its input slice:\n%s", src)
+	}
+	if src := files["DftCmplx8.go"].GoString(); !strings.HasPrefix(src, "//go:build !inplace\n") {
+		t.Fatalf("general variant isn't gated:\n%s", src)
+	}
+
+	test := `package dft
+
+import "testing"
+
+func TestInPlaceTag(t *testing.T) {
+	xi := randCmplx(8)
+	orig := append([]complex128(nil), xi...)
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+
+	// Both variants are correct in-place and out-of-place.
+	xo := DftCmplx8Into(xi, make([]complex128, 8))
+	if err := dftError(xo, naive); err > 1e-13 {
+		t.Fatalf("out-of-place error %g", err)
+	}
+	if xi[3] == xo[3] {
+		t.Fatalf("out-of-place transform modified its input")
+	}
+	DftCmplx8(xi, xi)
+	if err := dftError(xi, naive); err > 1e-13 {
+		t.Fatalf("in-place error %g", err)
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for k, x := range xo {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 8), make([]float64, 8)
+	DftFloat8(ri, ii, ro, io, 1)
+	for k := range ro {
+		if err := dftError([]complex128{complex(ro[k], io[k]) / 8}, orig[k:k+1]); err > 1e-13 {
+			t.Fatalf("float inverse output %d error %g", k, err)
+		}
+	}
+}
+`
+	t.Run("General", func(t *testing.T) {
+		goTest(t, files, test)
+	})
+	t.Run("InPlace", func(t *testing.T) {
+		goTest(t, files, test, "-tags", "inplace")
+	})
+}
@@ -0,0 +1,50 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkInverse returns an error if the transform is inverted along with an
+// option choosing, or assuming, its direction.
+func (o Options) checkInverse(name string) error {
+	if !o.Inverse {
+		return nil
+	}
+
+	if o.RuntimeSign || o.SignMultiplier || o.Direction || o.Generic {
+		return fmt.Errorf("%s: inverse transforms can't choose their direction at runtime or be generic", name)
+	}
+	if o.RealInput || o.ImagInput || o.Halfcomplex || o.ConvStep {
+		return fmt.Errorf("%s: inverse transforms don't support realInput, imagInput, halfcomplex or convStep", name)
+	}
+
+	return nil
+}
+
+// inverseSwap maps the slices of a float transform to the slices computing
+// its inverse.
+var inverseSwap = map[string]string{"ri": "ii", "ii": "ri", "ro": "io", "io": "ro"}
+
+// invertedStatements returns the statements of a float schedule computing the
+// inverse transform. Float schedules have no imaginary constant to negate, but
+// exchanging real and imaginary parts of both input and output conjugates
+// them, which flips the sign of every imaginary term at no cost.
+func (p Program) invertedStatements() []Expr {
+	swap := func(id string) string {
+		bracket := strings.IndexByte(id, '[')
+		if bracket < 0 {
+			return id
+		}
+		if to, ok := inverseSwap[id[:bracket]]; ok {
+			return to + id[bracket:]
+		}
+		return id
+	}
+
+	stmts := make([]Expr, len(p.Statements))
+	for idx, stmt := range p.Statements {
+		stmts[idx] = stmt.MapIdents(swap)
+	}
+	return stmts
+}
@@ -0,0 +1,134 @@
+package genfft
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestInverse(t *testing.T) {
+	inverse := Options{Inverse: true}
+
+	files := map[string]*jen.File{
+		"cmplx_8.go":      generateNaive(t, 8, Dft{Func: "DftCmplx8"}),
+		"cmplx_inv_8.go":  generateNaive(t, 8, Dft{Func: "DftCmplxInv8", Options: inverse}),
+		"float_8.go":      generateNaive(t, 8, Dft{Func: "DftFloat8"}),
+		"float_inv_8.go":  generateNaive(t, 8, Dft{Func: "DftFloatInv8", Options: inverse}),
+		"cmplx_inv_6.go":  generateNaive(t, 6, Dft{Func: "DftCmplxInv6", Options: Options{Inverse: true, Stages: 2}}),
+		"cmplx_inv_4f.go": generateNaive(t, 4, Dft{Func: "DftCmplxInv4F32", Options: Options{Inverse: true, Precision: "float32"}}),
+	}
+
+	if src := files["cmplx_inv_8.go"].GoString(); !regexp.MustCompile(`I\s+= -1i`).MatchString(src) {
+		t.Fatalf("expected a negated imaginary constant:\n%s", src)
+	}
+	if src := files["cmplx_inv_4f.go"].GoString(); !regexp.MustCompile(`I\s+= complex64\(-1i\)`).MatchString(src) {
+		t.Fatalf("expected a negated complex64 imaginary constant:\n%s", src)
+	}
+	if src := files["float_inv_8.go"].GoString(); !strings.Contains(src, "unscaled inverse float DFT") {
+		t.Fatalf("expected the doc comment to describe an inverse transform:\n%s", src)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func floatDft8(fn func(ri, ii, ro, io []float64), xi []complex128) []complex128 {
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+
+	ro, io := make([]float64, 8), make([]float64, 8)
+	fn(ri, ii, ro, io)
+
+	xo := make([]complex128, 8)
+	for idx := range xo {
+		xo[idx] = complex(ro[idx], io[idx])
+	}
+	return xo
+}
+
+func TestInverse(t *testing.T) {
+	xi := randCmplx(8)
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, 1)
+
+	xo := make([]complex128, 8)
+	DftCmplxInv8(xi, xo)
+	if err := dftError(xo, naive); err > 1e-13 {
+		t.Errorf("Cmplx: error %g", err)
+	}
+	if err := dftError(floatDft8(DftFloatInv8, xi), naive); err > 1e-13 {
+		t.Errorf("Float: error %g", err)
+	}
+
+	xi = randCmplx(6)
+	naive = append([]complex128(nil), xi...)
+	naiveDFT(naive, 1)
+	xo = make([]complex128, 6)
+	DftCmplxInv6(xi, xo)
+	if err := dftError(xo, naive); err > 1e-13 {
+		t.Errorf("Staged: error %g", err)
+	}
+
+	xi32, xo32 := []complex64{1, 2, 3, 4}, make([]complex64, 4)
+	DftCmplxInv4F32(xi32, xo32)
+	if d := xo32[1] - (-2 - 2i); real(d)*real(d)+imag(d)*imag(d) > 1e-10 {
+		t.Errorf("F32: unexpected output %v", xo32)
+	}
+}
+
+// Forward then inverse recovers a step input scaled by N.
+func TestInverseRoundTrip(t *testing.T) {
+	step := make([]complex128, 8)
+	for idx := range step[:4] {
+		step[idx] = 1
+	}
+
+	scale := func(xo []complex128) []complex128 {
+		for idx := range xo {
+			xo[idx] /= 8
+		}
+		return xo
+	}
+
+	forward, inverse := make([]complex128, 8), make([]complex128, 8)
+	DftCmplx8(step, forward)
+	DftCmplxInv8(forward, inverse)
+	if err := dftError(scale(inverse), step); err > 1e-14 {
+		t.Errorf("Cmplx: error %g", err)
+	}
+
+	roundTrip := floatDft8(DftFloatInv8, floatDft8(DftFloat8, step))
+	if err := dftError(scale(roundTrip), step); err > 1e-14 {
+		t.Errorf("Float: error %g", err)
+	}
+}
+`)
+}
+
+func TestInverseErrors(t *testing.T) {
+	alst, cout := naiveSchedule(4, false)
+	prefix := writeSchedule(t, t.TempDir(), "cmplx_4", alst, cout)
+
+	for _, tc := range []struct {
+		opts Options
+		want string
+	}{
+		{Options{Inverse: true, RuntimeSign: true}, "can't choose their direction"},
+		{Options{Inverse: true, Generic: true}, "can't choose their direction"},
+		{Options{Inverse: true, RealInput: true}, "don't support realInput"},
+	} {
+		prog, err := Dft{Prefix: prefix, Func: "DftCmplxInv4", Options: tc.opts}.Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = prog.Render("dft", "DftCmplxInv4")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tc.opts, tc.want, err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// laneShape returns a key shared by statements performing the same
+// operations on different operands, which could be packed into adjacent
+// lanes of a vector. Assignments to temporaries and to arguments differ in
+// shape, since they can't share a tuple assignment.
+func laneShape(expr Expr) string {
+	if expr.Op == ":=" && len(expr.Sub) == 2 {
+		kind := ":="
+		if strings.HasSuffix(expr.Sub[0].Ident, "]") {
+			kind = "="
+		}
+		return kind + " " + expr.Sub[1].shape()
+	}
+	return expr.shape()
+}
+
+// shape renders an expression with every identifier elided.
+func (e Expr) shape() string {
+	if e.Ident != "" {
+		return "_"
+	}
+
+	var b strings.Builder
+	b.WriteString("(" + e.Op)
+	for _, sub := range e.Sub {
+		b.WriteString(" " + sub.shape())
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// laneOrder reorders independent statements so that runs of up to lanes
+// statements with the same shape are adjacent. Each step extends the current
+// run with the first ready statement of its shape not depending on it, or
+// starts a new run with the first ready statement.
+func (p Program) laneOrder(name string) ([]Expr, error) {
+	lanes := p.Options.Lanes
+	if lanes != 2 && lanes != 4 {
+		return nil, fmt.Errorf("%s: lanes must be 2 or 4, got %d", name, lanes)
+	}
+	if p.Options.Stages > 1 || p.Options.Generic {
+		return nil, fmt.Errorf("%s: lanes require a single non-generic function", name)
+	}
+
+	deps := Dependencies(p.Statements)
+	var (
+		run   = map[int]bool{}
+		shape string
+	)
+
+	independent := func(idx int) bool {
+		for _, d := range deps[idx] {
+			if run[d] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return Reorder(p.Statements, func(ready []int) int {
+		if len(run) > 0 && len(run) < lanes {
+			for pos, idx := range ready {
+				if laneShape(p.Statements[idx]) == shape && independent(idx) {
+					run[idx] = true
+					return pos
+				}
+			}
+		}
+
+		run = map[int]bool{ready[0]: true}
+		shape = laneShape(p.Statements[ready[0]])
+		return 0
+	}), nil
+}
+
+// laneGroups splits statements into runs of up to lanes adjacent statements
+// with the same shape, none depending on another in its run.
+func laneGroups(statements []Expr, lanes int) (groups [][]Expr) {
+	deps := Dependencies(statements)
+
+	for start := 0; start < len(statements); {
+		end := start + 1
+		for end < len(statements) && end-start < lanes && laneShape(statements[end]) == laneShape(statements[start]) {
+			if len(deps[end]) > 0 && deps[end][len(deps[end])-1] >= start {
+				break
+			}
+			end++
+		}
+
+		groups = append(groups, statements[start:end])
+		start = end
+	}
+
+	return groups
+}
+
+// genLanes renders a run of statements as a single tuple assignment, so each
+// operation is written once across adjacent lanes.
+func (p Program) genLanes(group []Expr) *jen.Statement {
+	if len(group) == 1 {
+		return p.genStatement(group[0])
+	}
+
+	var lhs, rhs []jen.Code
+	for _, expr := range group {
+		expr = p.mapStatement(expr)
+
+		lhs = append(lhs, genIdent(expr.Sub[0].Ident))
+		if p.Options.Compensated {
+			rhs = append(rhs, expr.Sub[1].GenCompensated(compensatedSum(p.Float())))
+		} else {
+			rhs = append(rhs, expr.Sub[1].Gen())
+		}
+	}
+
+	// When the left side is an indexed identifier, assign only.
+	op := ":="
+	if strings.HasSuffix(group[0].Sub[0].Ident, "]") {
+		op = "="
+	}
+	return jen.List(lhs...).Op(op).List(rhs...)
+}
@@ -0,0 +1,99 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestLaneOrder(t *testing.T) {
+	prog := &Program{}
+	err := parser.ParseString("", `
+(:= T1 xi[0])
+(:= T3 (+ T1 xi[1]))
+(:= T2 xi[2])
+(:= T4 (+ T2 xi[3]))
+(:= xo[0] (+ T3 T4))
+(:= xo[1] (+ T3 (- T4)))
+`, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prog.Options.Lanes = 2
+
+	statements, err := prog.laneOrder("DftCmplx4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, group := range laneGroups(statements, 2) {
+		got = append(got, prog.genLanes(group).GoString())
+	}
+
+	want := []string{
+		"T1, T2 := xi[0], xi[2]",
+		"T3, T4 := T1+xi[1], T2+xi[3]",
+		"xo[0] = T3 + T4",
+		"xo[1] = T3 - T4",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Errorf("group %d: got %q, want %q", idx, got[idx], want[idx])
+		}
+	}
+}
+
+func TestLanes(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go":       generateNaive(t, 8, Dft{Func: "DftCmplx8"}),
+		"cmplx_8_lanes.go": generateNaive(t, 8, Dft{Func: "DftCmplx8Lanes", Options: Options{Lanes: 2}}),
+		"float_8_lanes.go": generateNaive(t, 8, Dft{Func: "DftFloat8Lanes", Options: Options{Lanes: 4}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestLanes(t *testing.T) {
+	xi := randCmplx(8)
+	want := append([]complex128(nil), xi...)
+	naiveDFT(want, -1.0)
+
+	xo := make([]complex128, 8)
+	DftCmplx8Lanes(xi, xo)
+	if err := dftError(xo, want); err > 1e-13 {
+		t.Fatalf("cmplx: error %g", err)
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	DftFloat8Lanes(ri, ii, ri, ii)
+	for idx := range xo {
+		xo[idx] = complex(ri[idx], ii[idx])
+	}
+	if err := dftError(xo, want); err > 1e-13 {
+		t.Fatalf("float in-place: error %g", err)
+	}
+}
+
+func BenchmarkLanes(b *testing.B) {
+	xi, xo := randCmplx(8), make([]complex128, 8)
+	b.Run("Schedule", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftCmplx8(xi, xo)
+		}
+	})
+	b.Run("Lanes", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftCmplx8Lanes(xi, xo)
+		}
+	})
+}
+`, "-bench", ".", "-benchtime", "10x")
+}
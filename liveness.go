@@ -0,0 +1,109 @@
+package genfft
+
+// temporaries returns the set of temporaries the statements assign.
+func temporaries(statements []Expr) map[string]bool {
+	temps := map[string]bool{}
+	for _, expr := range statements {
+		if temp, ok := expr.Temporary(); ok {
+			temps[temp] = true
+		}
+	}
+	return temps
+}
+
+// readTemps returns the distinct temporaries in temps a statement reads.
+func readTemps(expr Expr, temps map[string]bool) (read []string) {
+	// The left side of the assignment, if any, is written not read.
+	operands := expr.Sub
+	if expr.Op == ":=" && len(expr.Sub) == 2 {
+		operands = expr.Sub[1:]
+	}
+
+	seen := map[string]bool{}
+	for _, sub := range operands {
+		for _, id := range sub.Idents() {
+			if !temps[id] || seen[id] {
+				continue
+			}
+			seen[id] = true
+			read = append(read, id)
+		}
+	}
+
+	return read
+}
+
+// MaxLive returns the largest number of temporaries live at once when the
+// statements are evaluated in order. A temporary is live from its assignment
+// until its last use.
+func MaxLive(statements []Expr) (max int) {
+	temps := temporaries(statements)
+	lastUse := map[string]int{}
+	for idx, expr := range statements {
+		for _, temp := range readTemps(expr, temps) {
+			lastUse[temp] = idx
+		}
+	}
+
+	live := 0
+	for idx, expr := range statements {
+		if temp, ok := expr.Temporary(); ok {
+			if _, used := lastUse[temp]; used {
+				live++
+			}
+		}
+		if live > max {
+			max = live
+		}
+
+		for _, temp := range readTemps(expr, temps) {
+			if lastUse[temp] == idx {
+				live--
+			}
+		}
+	}
+
+	return max
+}
+
+// liveOrder reorders independent statements to reduce the number of
+// temporaries live at once. Each step greedily picks the ready statement
+// ending the most live ranges less any it starts, keeping the original order
+// between ties.
+func (p Program) liveOrder() []Expr {
+	temps := temporaries(p.Statements)
+
+	// Count the statements reading each temporary.
+	remaining := map[string]int{}
+	for _, expr := range p.Statements {
+		for _, temp := range readTemps(expr, temps) {
+			remaining[temp]++
+		}
+	}
+
+	score := func(expr Expr) (s int) {
+		for _, temp := range readTemps(expr, temps) {
+			if remaining[temp] == 1 {
+				s++
+			}
+		}
+		if temp, ok := expr.Temporary(); ok && remaining[temp] > 0 {
+			s--
+		}
+		return s
+	}
+
+	return Reorder(p.Statements, func(ready []int) int {
+		best, bestScore := 0, 0
+		for pos, idx := range ready {
+			if s := score(p.Statements[idx]); pos == 0 || s > bestScore {
+				best, bestScore = pos, s
+			}
+		}
+
+		for _, temp := range readTemps(p.Statements[ready[best]], temps) {
+			remaining[temp]--
+		}
+		return best
+	})
+}
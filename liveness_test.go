@@ -0,0 +1,67 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestMinimizeLive(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_16.go":      generateNaive(t, 16, Dft{Func: "DftCmplx16"}),
+		"cmplx_16_live.go": generateNaive(t, 16, Dft{Func: "DftCmplx16Live", Options: Options{MinimizeLive: true}}),
+		"float_16_live.go": generateNaive(t, 16, Dft{Func: "DftFloat16Live", Options: Options{MinimizeLive: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestMinimizeLive(t *testing.T) {
+	xi := randCmplx(16)
+	want := make([]complex128, 16)
+	DftCmplx16(xi, want)
+
+	got := make([]complex128, 16)
+	DftCmplx16Live(xi, got)
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Fatalf("out-of-place output differs at %d", idx)
+		}
+	}
+
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	DftFloat16Live(ri, ii, ri, ii)
+	for idx := range want {
+		if err := dftError([]complex128{complex(ri[idx], ii[idx])}, want[idx:idx+1]); err > 1e-13 {
+			t.Fatalf("float in-place output error %g at %d", err, idx)
+		}
+	}
+
+	// Reordering must keep in-place transforms correct.
+	DftCmplx16Live(xi, xi)
+	for idx := range want {
+		if xi[idx] != want[idx] {
+			t.Fatalf("in-place output differs at %d", idx)
+		}
+	}
+}
+
+func BenchmarkMinimizeLive(b *testing.B) {
+	xi, xo := randCmplx(16), make([]complex128, 16)
+	b.Run("Schedule", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftCmplx16(xi, xo)
+		}
+	})
+	b.Run("MinimizeLive", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftCmplx16Live(xi, xo)
+		}
+	})
+}
+`, "-bench", ".", "-benchtime", "10x")
+}
@@ -0,0 +1,40 @@
+package genfft
+
+// memoryIndex returns the index of the first argument element a statement
+// reads or writes.
+func memoryIndex(expr Expr) (int, bool) {
+	for _, id := range expr.Idents() {
+		if s, ok := slot(id); ok {
+			return s.index, true
+		}
+	}
+	return 0, false
+}
+
+// localityOrder reorders independent statements so that each memory access
+// is as close as possible to the one before it. Statements without memory
+// accesses are scheduled as soon as they're ready.
+func (p Program) localityOrder() []Expr {
+	last := 0
+	return Reorder(p.Statements, func(ready []int) int {
+		best, bestDist := 0, -1
+		for pos, idx := range ready {
+			dist := 0
+			if k, ok := memoryIndex(p.Statements[idx]); ok {
+				dist = k - last
+				if dist < 0 {
+					dist = -dist
+				}
+			}
+
+			if bestDist == -1 || dist < bestDist {
+				best, bestDist = pos, dist
+			}
+		}
+
+		if k, ok := memoryIndex(p.Statements[ready[best]]); ok {
+			last = k
+		}
+		return best
+	})
+}
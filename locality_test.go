@@ -0,0 +1,67 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestLocality(t *testing.T) {
+	// Both are strided, so the benchmark transforms columns of a large buffer
+	// directly.
+	files := map[string]*jen.File{
+		"cmplx_16.go":          generateNaive(t, 16, Dft{Func: "DftCmplx16", Options: Options{Strided: true}}),
+		"cmplx_16_locality.go": generateNaive(t, 16, Dft{Func: "DftCmplx16Locality", Options: Options{Strided: true, Locality: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestLocality(t *testing.T) {
+	xi := randCmplx(16)
+	want := make([]complex128, 16)
+	DftCmplx16(xi, want, 1, 1)
+
+	got := make([]complex128, 16)
+	DftCmplx16Locality(xi, got, 1, 1)
+	for idx := range want {
+		if got[idx] != want[idx] {
+			t.Fatalf("out-of-place output differs at %d", idx)
+		}
+	}
+
+	// Reordering must keep in-place transforms correct, strided ones too.
+	data := make([]complex128, 16*3)
+	for k, x := range xi {
+		data[3*k+1] = x
+	}
+	DftCmplx16Locality(data[1:], data[1:], 3, 3)
+	for idx := range want {
+		if data[3*idx+1] != want[idx] {
+			t.Fatalf("in-place output differs at %d", idx)
+		}
+	}
+}
+
+// benchmarkStrided transforms each column of a buffer too large for the
+// cache in place, reading and writing every stride-th element.
+func benchmarkStrided(b *testing.B, fn func(xi, xo []complex128, is, os int)) {
+	const stride = 1 << 14
+	data := randCmplx(16 * stride)
+
+	b.SetBytes(16 * stride * 16)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for offset := 0; offset < stride; offset++ {
+			fn(data[offset:], data[offset:], stride, stride)
+		}
+	}
+}
+
+func BenchmarkStrided(b *testing.B) {
+	b.Run("Schedule", func(b *testing.B) { benchmarkStrided(b, DftCmplx16) })
+	b.Run("Locality", func(b *testing.B) { benchmarkStrided(b, DftCmplx16Locality) })
+}
+`, "-bench", ".", "-benchtime", "10x")
+}
@@ -0,0 +1,85 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Lowering renders an operator applied to its already rendered operands.
+type Lowering func(operands []jen.Code) *jen.Statement
+
+// lowerings holds the registered operator lowerings by operator name.
+var lowerings = map[string]Lowering{}
+
+// RegisterLowering registers how an operator or macro name in schedules
+// lowers to go, such as a VSQRT emitted by an extended generator. Rendering
+// consults registered lowerings before the built-in operators, so they may
+// also replace those. Lowered operators are rendered as operands, never
+// wrapped in parentheses, so lowerings should render calls or other
+// expressions binding as tightly.
+func RegisterLowering(op string, lower Lowering) {
+	lowerings[op] = lower
+}
+
+// builtin reports whether op is an operator rendered without a registered
+// lowering.
+func builtin(op string) bool {
+	switch op {
+	case ":=", "+", "-", "*":
+		return true
+	}
+	return false
+}
+
+// checkOperator returns an error if e or any of its sub-expressions applies an
+// operator that's neither built in nor has a registered lowering.
+func (e Expr) checkOperator() error {
+	if e.Ident == "" && !builtin(e.Op) {
+		if _, ok := lowerings[e.Op]; !ok {
+			return fmt.Errorf("line %d: operator %s has no registered lowering", e.Pos.Line, e.Op)
+		}
+	}
+
+	for _, sub := range e.Sub {
+		if err := sub.checkOperator(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkOperators returns an error naming the first operator in the program
+// that can't be rendered, before rendering gets partway through it.
+func (p Program) checkOperators() error {
+	for _, expr := range p.Statements {
+		if err := expr.checkOperator(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lower renders an expression with a registered lowering, rendering each of
+// its operands with gen. It reports false if the expression's operator is
+// built in and has no registered lowering. Operators that are neither render
+// as a call of the same name, which Render rejects upfront.
+func (e Expr) lower(gen func(Expr) *jen.Statement) (*jen.Statement, bool) {
+	lower, ok := lowerings[e.Op]
+	if !ok {
+		if builtin(e.Op) {
+			return nil, false
+		}
+		lower = func(operands []jen.Code) *jen.Statement {
+			return jen.Id(e.Op).Call(operands...)
+		}
+	}
+
+	operands := make([]jen.Code, len(e.Sub))
+	for idx, sub := range e.Sub {
+		operands[idx] = gen(sub)
+	}
+	return lower(operands), true
+}
@@ -0,0 +1,49 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRegisterLowering(t *testing.T) {
+	RegisterLowering("VSQRT", func(operands []jen.Code) *jen.Statement {
+		return jen.Qual("math", "Sqrt").Call(operands...)
+	})
+	t.Cleanup(func() { delete(lowerings, "VSQRT") })
+
+	expr := parseExpr(t, "(:= ro[0] (* KP500000000 (VSQRT (+ T1 T2))))")
+	if got, want := expr.Gen().GoString(), "ro[0] = KP500000000 * math.Sqrt(T1+T2)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Registered lowerings take precedence over built-in operators.
+	RegisterLowering("+", func(operands []jen.Code) *jen.Statement {
+		return jen.Id("add").Call(operands...)
+	})
+	t.Cleanup(func() { delete(lowerings, "+") })
+
+	if got, want := expr.Gen().GoString(), "ro[0] = KP500000000 * math.Sqrt(add(T1, T2))"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Lowered operators render as operands, needing no parentheses.
+	expr = parseExpr(t, "(:= T3 (* KP500000000 (+ T1 T2) (- (+ T1 T2))))")
+	if got, want := expr.Gen().GoString(), "T3 := KP500000000 * add(T1, T2) * -add(T1, T2)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestUnregisteredLowering(t *testing.T) {
+	// Without a lowering, operators render as calls of the same name, and
+	// rendering programs using them fails upfront.
+	expr := parseExpr(t, "(:= ro[0] (VSQRT T1))")
+	if got, want := expr.Gen().GoString(), "ro[0] = VSQRT(T1)"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	prog := &Program{Statements: []Expr{expr}}
+	if _, err := prog.Render("dft", "DftFloat1"); err == nil {
+		t.Fatal("expected an error for an operator without a lowering")
+	}
+}
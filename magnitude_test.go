@@ -0,0 +1,47 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestMagnitude(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Magnitude: true}}),
+		"float_5.go": generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{Magnitude: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func checkMagnitude(t *testing.T, name string, n int, magnitude func(in, out []float32)) {
+	x := randCmplx(n)
+	in := make([]float32, n)
+	for k := range x {
+		in[k] = float32(real(x[k]))
+		x[k] = complex(float64(in[k]), 0)
+	}
+	naiveDFT(x, -1.0)
+
+	out := make([]float32, n/2+1)
+	magnitude(in, out)
+
+	for k := range out {
+		want := math.Hypot(real(x[k]), imag(x[k]))
+		if math.Abs(float64(out[k])-want) > 1e-6*(1+want) {
+			t.Fatalf("%s: bin %d: %g != %g", name, k, out[k], want)
+		}
+	}
+}
+
+func TestMagnitude(t *testing.T) {
+	checkMagnitude(t, "DftCmplx8Magnitude", 8, DftCmplx8Magnitude)
+	checkMagnitude(t, "DftFloat5Magnitude", 5, DftFloat5Magnitude)
+}
+`)
+}
@@ -0,0 +1,40 @@
+package genfft
+
+import "github.com/dave/jennifer/jen"
+
+// naiveSupport renders a direct O(n²) transform of any size, which callers
+// can validate their own data against or fall back to for sizes without a
+// codelet.
+func naiveSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	x, h, n := jen.Id("x"), jen.Id("h"), jen.Id("n")
+	k, w, t := jen.Id("k"), jen.Id("w"), jen.Id("t")
+
+	f.Comment("NaiveDFT computes the unnormalized transform of x in place by direct summation,")
+	f.Comment("the forward transform unless inverse is set. It takes time proportional to the")
+	f.Comment("square of len(x), but works for any length.")
+	f.Func().Id("NaiveDFT").Params(x.Clone().Index().Complex128(), jen.Id("inverse").Bool()).Block(
+		jen.Id("sign").Op(":=").Lit(-1.0),
+		jen.If(jen.Id("inverse")).Block(
+			jen.Id("sign").Op("=").Lit(1.0),
+		),
+		jen.Line(),
+		n.Clone().Op(":=").Len(x),
+		h.Clone().Op(":=").Make(jen.Index().Complex128(), n),
+		jen.Id("phi").Op(":=").Id("sign").Op("*").Lit(2).Op("*").Qual("math", "Pi").Op("/").Float64().Call(n),
+		jen.For(w.Clone().Op(":=").Range().Add(h)).Block(
+			jen.Var().Add(t).Complex128(),
+			jen.For(k.Clone().Op(":=").Range().Add(x)).Block(
+				jen.Comment("Reducing the phase modulo n keeps its rounding error small."),
+				t.Clone().Op("+=").Add(x).Index(k).Op("*").Qual("math/cmplx", "Rect").Call(
+					jen.Lit(1), jen.Id("phi").Op("*").Float64().Call(jen.Parens(k.Clone().Op("*").Add(w)).Op("%").Add(n)),
+				),
+			),
+			h.Clone().Index(w).Op("=").Add(t),
+		),
+		jen.Copy(x, h),
+	)
+
+	return f
+}
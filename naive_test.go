@@ -0,0 +1,44 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestNaiveDFT(t *testing.T) {
+	files := map[string]*jen.File{
+		"naive.go":   naiveSupport("dft"),
+		"cmplx_3.go": mustGenerate(t, Dft{Prefix: copyTestdata(t, t.TempDir(), "cmplx_3"), Func: "DftCmplx3"}),
+		"cmplx_5.go": generateNaive(t, 5, Dft{Func: "DftCmplx5", Options: Options{RuntimeSign: true}}),
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{RuntimeSign: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestNaiveDFT(t *testing.T) {
+	for _, tc := range []struct {
+		n       int
+		inverse bool
+		dft     func(xi, xo []complex128)
+	}{
+		{3, false, DftCmplx3},
+		{5, false, func(xi, xo []complex128) { DftCmplx5(xi, xo, -1) }},
+		{5, true, func(xi, xo []complex128) { DftCmplx5(xi, xo, 1) }},
+		{8, false, func(xi, xo []complex128) { DftCmplx8(xi, xo, -1) }},
+		{8, true, func(xi, xo []complex128) { DftCmplx8(xi, xo, 1) }},
+	} {
+		xi := randCmplx(tc.n)
+		want := make([]complex128, tc.n)
+		tc.dft(xi, want)
+
+		NaiveDFT(xi, tc.inverse)
+		if err := dftError(xi, want); err > 1e-13 {
+			t.Fatalf("size %d, inverse %v: error %g", tc.n, tc.inverse, err)
+		}
+	}
+}
+`)
+}
@@ -0,0 +1,22 @@
+package genfft
+
+import "fmt"
+
+// offsetOutputs returns the program's statements with obase added to every
+// output index.
+func (p Program) offsetOutputs() []Expr {
+	offset := func(id string) string {
+		slice, k, ok := splitIndex(id)
+		if !ok || !isOutput(slice) {
+			return id
+		}
+		return fmt.Sprintf("%s[obase+%d]", slice, k)
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(offset)
+	}
+
+	return statements
+}
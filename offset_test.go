@@ -0,0 +1,50 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestOutputBase(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{OutputBase: true, Into: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{OutputBase: true, Stages: 2}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestOutputBase(t *testing.T) {
+	xi := randCmplx(8)
+	want := DftCmplx8Into(xi, make([]complex128, 8))
+
+	// Write into the middle of a larger slice, leaving the rest untouched.
+	xo := make([]complex128, 24)
+	DftCmplx8(xi, xo, 5)
+	for idx, x := range xo {
+		switch {
+		case idx < 5 || idx >= 13:
+			if x != 0 {
+				t.Fatalf("DftCmplx8 wrote outside its block at %d", idx)
+			}
+		case x != want[idx-5]:
+			t.Fatalf("DftCmplx8: output %d is %v, want %v", idx-5, x, want[idx-5])
+		}
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 16), make([]float64, 16)
+	DftFloat8(ri, ii, ro, io, 8)
+	for k := range want {
+		if err := dftError([]complex128{complex(ro[8+k], io[8+k])}, want[k:k+1]); err > 1e-13 {
+			t.Fatalf("DftFloat8: output %d error %g", k, err)
+		}
+	}
+}
+`)
+}
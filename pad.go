@@ -0,0 +1,82 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genPad renders a wrapper transforming input no longer than the transform,
+// zero-padded to its length, and returning the result by value. Longer input
+// is rejected with an error.
+func (p Program) genPad(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: padding requires a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	pad := name + "Pad"
+	extraParams, extraArgs := p.passThrough()
+
+	var (
+		inputs, outputs, padded []string
+		elem                    jen.Code
+	)
+	if p.Float() {
+		inputs, outputs = []string{"ri", "ii"}, []string{"ro", "io"}
+		padded = []string{"re", "im"}
+		elem = jen.Float64()
+	} else {
+		inputs, outputs = []string{"xi"}, []string{"xo"}
+		padded = []string{"x"}
+		elem = jen.Complex128()
+	}
+
+	params := append([]jen.Code{jen.List(ids(inputs)...).Index().Add(elem)}, extraParams...)
+	results := []jen.Code{jen.List(ids(outputs)...).Index(jen.Lit(n)).Add(elem), jen.Err().Error()}
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes %s of %s zero-padded to %d elements. It returns an error\nif %s is longer than %d.",
+		pad, name, joinNames(inputs), n, strings.Join(inputs, " or "), n,
+	))
+	f.Func().Id(pad).Params(params...).Parens(jen.List(results...)).BlockFunc(func(g *jen.Group) {
+		for _, in := range inputs {
+			g.If(jen.Len(jen.Id(in)).Op(">").Lit(n)).Block(
+				jen.Err().Op("=").Qual("fmt", "Errorf").Call(
+					jen.Lit(fmt.Sprintf("dft: %s: input length %%d exceeds %d", pad, n)),
+					jen.Len(jen.Id(in)),
+				),
+				jen.Return(),
+			)
+		}
+		g.Line()
+
+		// Pad into separate buffers, since the transform's input and output
+		// may not be allowed to overlap.
+		g.Var().List(ids(padded)...).Index(jen.Lit(n)).Add(elem)
+		var args []jen.Code
+		for idx, in := range inputs {
+			g.Copy(jen.Id(padded[idx]).Index(jen.Empty(), jen.Empty()), jen.Id(in))
+			args = append(args, jen.Id(padded[idx]).Index(jen.Empty(), jen.Empty()))
+		}
+		for _, out := range outputs {
+			args = append(args, jen.Id(out).Index(jen.Empty(), jen.Empty()))
+		}
+		g.Add(p.callTransform(name, ids(outputs), append(args, extraArgs...)...))
+		g.Line()
+
+		g.Return()
+	})
+
+	return nil
+}
+
+// ids returns an identifier for each name.
+func ids(names []string) (codes []jen.Code) {
+	for _, name := range names {
+		codes = append(codes, jen.Id(name))
+	}
+	return codes
+}
@@ -0,0 +1,55 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestPad(t *testing.T) {
+	// Padding mustn't pass overlapping buffers to out-of-place transforms.
+	_, debug := aliasSupport("dft")
+	files := map[string]*jen.File{
+		"cmplx_8.go":     generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Pad: true, NoAlias: true}}),
+		"float_8.go":     generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Pad: true}}),
+		"alias_debug.go": debug,
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestPad(t *testing.T) {
+	xi := randCmplx(5)
+	want := append(append([]complex128(nil), xi...), 0, 0, 0)
+	naiveDFT(want, -1.0)
+
+	xo, err := DftCmplx8Pad(xi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := dftError(xo[:], want); err > 1e-13 {
+		t.Fatalf("cmplx: error %g", err)
+	}
+
+	ri, ii := make([]float64, 5), make([]float64, 5)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	ro, io, err := DftFloat8Pad(ri, ii)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for idx := range xo {
+		xo[idx] = complex(ro[idx], io[idx])
+	}
+	if err := dftError(xo[:], want); err > 1e-13 {
+		t.Fatalf("float: error %g", err)
+	}
+
+	if _, err := DftCmplx8Pad(randCmplx(9)); err == nil {
+		t.Fatal("input longer than the transform accepted")
+	}
+}
+`, "-tags", "debug")
+}
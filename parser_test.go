@@ -0,0 +1,117 @@
+package genfft
+
+import "testing"
+
+// The lexer splits operators, identifiers and parentheses by pattern, so
+// schedules parse the same regardless of spacing between tokens.
+func TestParseSpacing(t *testing.T) {
+	testCases := []struct {
+		alst string
+		want string
+	}{
+		{"(*A B)", "A * B"},
+		{"(* A B)", "A * B"},
+		{"(+T1(-T2))", "T1 - T2"},
+		{"(:=xo[0](+T1 T4))", "xo[0] = T1 + T4"},
+		{"( := T6 ( * I ( * KP866025403 ( + T3 ( - T2 ) ) ) ) )", "T6 := I * (KP866025403 * (T3 - T2))"},
+		{"(:= T5\t(+ T1 (-(*KP500000000 T4))))", "T5 := T1 - KP500000000*T4"},
+	}
+
+	for _, tc := range testCases {
+		e := parseExpr(t, tc.alst)
+		if got := e.Gen().GoString(); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.alst, got, tc.want)
+		}
+	}
+}
+
+// Expressions are delimited by parentheses rather than lines, so a schedule
+// expression wrapped across several lines parses as one statement.
+func TestParseContinuation(t *testing.T) {
+	alst := "(:= T1 xi[0])\n" +
+		"(:= T6 (* I\n" +
+		"\t(* KP866025403\r\n" +
+		"\t\t(+ T3 (- T2)))))\n" +
+		"(:= xo[0]\n(+ T1 T4))\n"
+
+	prog := &Program{}
+	if err := parser.ParseString("", alst, prog); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"T1 := xi[0]",
+		"T6 := I * (KP866025403 * (T3 - T2))",
+		"xo[0] = T1 + T4",
+	}
+	if len(prog.Statements) != len(want) {
+		t.Fatalf("got %d statements, want %d", len(prog.Statements), len(want))
+	}
+	for idx, e := range prog.Statements {
+		if got := e.Gen().GoString(); got != want[idx] {
+			t.Errorf("statement %d: got %q, want %q", idx, got, want[idx])
+		}
+	}
+}
+
+// Codelets generated with FFTW's -with-istride and -with-ostride index
+// their slices by symbolic stride factors, which are preserved and rendered
+// as multiples of stride parameters.
+func TestParseStride(t *testing.T) {
+	testCases := []struct {
+		alst string
+		want string
+	}{
+		{"(:= T1 xi[WS(is, 2)])", "T1 := xi[2*is]"},
+		{"(:= T1 ri[WS(is,0)])", "T1 := ri[0]"},
+		{"(:= xo[WS( os , 1 )] (+ T1 T2))", "xo[os] = T1 + T2"},
+		{"(:= T3 (* KP500000000 xi[WS(is, 3)]))", "T3 := KP500000000 * xi[3*is]"},
+	}
+
+	for _, tc := range testCases {
+		e := parseExpr(t, tc.alst)
+		if got := e.Gen().GoString(); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.alst, got, tc.want)
+		}
+	}
+
+	prog := &Program{}
+	err := parser.ParseString("", "(:= T1 xi[WS(is, 2)])\n(:= T2 xi[0])\n(:= xo[WS(os, 1)] (+ T1 T2))\n", prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prog.TransformLength(); got != 3 {
+		t.Errorf("TransformLength() = %d, want 3", got)
+	}
+	if got := prog.Strides(); len(got) != 2 || got[0] != "is" || got[1] != "os" {
+		t.Errorf("Strides() = %q, want [is os]", got)
+	}
+	if _, _, k, ok := stridedIndex(prog.Statements[0].Sub[1].Ident); !ok || k != 2 {
+		t.Errorf("stride factor of %q not preserved", prog.Statements[0].Sub[1].Ident)
+	}
+}
+
+// The length of a transform is one past the largest index of its input,
+// whatever the indices of its outputs.
+func TestTransformLength(t *testing.T) {
+	for _, n := range []int{2, 3, 8, 13, 16} {
+		for _, float := range []bool{false, true} {
+			alst, _ := naiveSchedule(n, float)
+			prog, err := parseSchedule("", []byte(alst))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := prog.TransformLength(); got != n {
+				t.Errorf("n=%d float=%t: TransformLength() = %d", n, float, got)
+			}
+		}
+	}
+
+	prog, err := parseSchedule("", []byte("(:= xo[7] (+ xi[0] xi[2]))\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prog.TransformLength(); got != 3 {
+		t.Errorf("TransformLength() = %d, want 3", got)
+	}
+}
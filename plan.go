@@ -0,0 +1,101 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genPlan renders a plan type for the transform, and a variant computing the
+// transform with a plan built by its first call and returned for reuse.
+// Codelets compute their twiddle factors as constants, so a plan holds only
+// scratch space for the input, which lets the input and output overlap.
+func (p Program) genPlan(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: plans require a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	planType := name + "Plan"
+	withPlan := name + "WithPlan"
+	cached := "cached" + planType
+	once := cached + "Once"
+
+	inputs, outputs, typ := []string{"xi"}, []string{"xo"}, jen.Complex128()
+	if p.Float() {
+		inputs, outputs, typ = []string{"ri", "ii"}, []string{"ro", "io"}, jen.Float64()
+	}
+
+	extraParams, extraArgs := p.passThrough()
+	params := append([]jen.Code{
+		jen.List(append(ids(inputs), ids(outputs)...)...).Index().Add(typ),
+	}, extraParams...)
+
+	var args []jen.Code
+	for _, in := range inputs {
+		args = append(args, jen.Id("plan").Dot(in).Index(jen.Empty(), jen.Empty()))
+	}
+	args = append(append(args, ids(outputs)...), extraArgs...)
+
+	// Plans are unit-stride, so the sign is the only parameter passed through.
+	callArgs := ids(append(inputs, outputs...))
+	if p.Options.RuntimeSign || p.Options.SignMultiplier {
+		callArgs = append(callArgs, jen.Id("sign"))
+	}
+
+	f.Line()
+	f.Comment(fmt.Sprintf("%s holds the state %s reuses across calls, scratch space\nfor the input which lets %s overlap %s.", planType, withPlan, joinNames(inputs), joinNames(outputs)))
+	f.Type().Id(planType).Struct(
+		jen.Id("mu").Qual("sync", "Mutex"),
+		jen.List(ids(inputs)...).Index(jen.Lit(n)).Add(typ),
+	)
+	f.Line()
+
+	f.Var().Defs(
+		jen.Id(once).Qual("sync", "Once"),
+		jen.Id(cached).Op("*").Id(planType),
+	)
+	f.Line()
+
+	f.Comment(fmt.Sprintf("Transform computes %s of %s into %s using the plan's scratch\nspace. It is safe for concurrent use.", name, joinNames(inputs), joinNames(outputs)))
+	f.Func().Params(jen.Id("plan").Op("*").Id(planType)).Id("Transform").Params(params...).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		g.Id("plan").Dot("mu").Dot("Lock").Call()
+		g.Defer().Id("plan").Dot("mu").Dot("Unlock").Call()
+		g.Line()
+
+		for _, in := range inputs {
+			g.Copy(jen.Id("plan").Dot(in).Index(jen.Empty(), jen.Empty()), jen.Id(in))
+		}
+		g.Add(p.callTransform(name, nil, args...))
+		g.Add(p.wrapperReturn())
+	})
+	f.Line()
+
+	f.Comment(fmt.Sprintf("%s computes %s of %s into %s. The plan is built by the\nfirst call and the same plan is returned by every call for reuse.", withPlan, name, joinNames(inputs), joinNames(outputs)))
+	f.Func().Id(withPlan).Params(params...).Params(jen.Op("*").Id(planType), jen.Error()).BlockFunc(func(g *jen.Group) {
+		for _, id := range append(inputs, outputs...) {
+			g.If(jen.Len(jen.Id(id)).Op("<").Lit(n)).Block(
+				jen.Return(jen.Nil(), jen.Qual("fmt", "Errorf").Call(
+					jen.Lit(fmt.Sprintf("dft: %s: %s has length %%d, need %d", withPlan, id, n)),
+					jen.Len(jen.Id(id)),
+				)),
+			)
+		}
+		g.Line()
+
+		g.Id(once).Dot("Do").Call(jen.Func().Params().Block(
+			jen.Id(cached).Op("=").New(jen.Id(planType)),
+		))
+		transform := jen.Id(cached).Dot("Transform").Call(callArgs...)
+		if p.Options.ReturnErrors {
+			g.If(jen.Err().Op(":=").Add(transform), jen.Err().Op("!=").Nil()).Block(
+				jen.Return(jen.Nil(), jen.Err()),
+			)
+		} else {
+			g.Add(transform)
+		}
+		g.Return(jen.Id(cached), jen.Nil())
+	})
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestPlan(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Plan: true}}),
+		"float_4.go": generateNaive(t, 4, Dft{Func: "DftFloat4", Options: Options{Plan: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestPlan(t *testing.T) {
+	var first *DftCmplx8Plan
+	for call := 0; call < 3; call++ {
+		xi := randCmplx(8)
+		xo := make([]complex128, 8)
+		plan, err := DftCmplx8WithPlan(xi, xo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first == nil {
+			first = plan
+		} else if plan != first {
+			t.Fatalf("call %d: plan was rebuilt", call)
+		}
+
+		naive := append([]complex128(nil), xi...)
+		naiveDFT(naive, -1.0)
+		if err := dftError(xo, naive); err > 1e-13 {
+			t.Fatalf("call %d: error %g", call, err)
+		}
+
+		// The plan's scratch space lets the transform run in place.
+		first.Transform(xi, xi)
+		if err := dftError(xi, naive); err > 1e-13 {
+			t.Fatalf("call %d: in-place error %g", call, err)
+		}
+	}
+
+	if _, err := DftCmplx8WithPlan(make([]complex128, 7), make([]complex128, 8)); err == nil {
+		t.Fatal("expected error for short input")
+	}
+}
+
+func TestPlanFloat(t *testing.T) {
+	xi := randCmplx(4)
+	ri, ii := make([]float64, 4), make([]float64, 4)
+	for idx := range xi {
+		ri[idx], ii[idx] = real(xi[idx]), imag(xi[idx])
+	}
+
+	plan, err := DftFloat4WithPlan(ri, ii, ri, ii)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := DftFloat4WithPlan(ri, ii, ri, ii); again != plan {
+		t.Fatal("plan was rebuilt")
+	}
+
+	naiveDFT(xi, -1.0)
+	naiveDFT(xi, -1.0)
+	xo := make([]complex128, 4)
+	for idx := range xo {
+		xo[idx] = complex(ri[idx], ii[idx])
+	}
+	if err := dftError(xo, xi); err > 1e-12 {
+		t.Fatalf("error %g", err)
+	}
+}
+`)
+}
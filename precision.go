@@ -0,0 +1,33 @@
+package genfft
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// checkPrecision returns an error if the precision is unknown, or is float32
+// and any option rendering code specific to float64 is set. Options are
+// rejected unless known to render the same code for either precision.
+func (o Options) checkPrecision(name string) error {
+	switch o.Precision {
+	case "", "float64":
+		return nil
+	case "float32":
+	default:
+		return fmt.Errorf("%s: unknown precision %q", name, o.Precision)
+	}
+
+	rest := o
+	rest.Precision = ""
+	rest.Stages, rest.TinyGo, rest.Lanes = 0, false, 0
+	rest.Canonical, rest.CSE, rest.Locality, rest.MinimizeLive, rest.SplitPasses = false, false, false, false, false
+	rest.InPlaceTag, rest.NoLenGuard, rest.AssertSize = false, false, 0
+	rest.Expvar, rest.DebugTiming, rest.GoGenerate, rest.Inverse = false, false, false, false
+	rest.Constants = nil
+
+	if !reflect.DeepEqual(rest, Options{}) {
+		return fmt.Errorf("%s: float32 transforms support only stages, tinyGo, lanes, canonical, cse, locality, minimizeLive, splitPasses, inPlaceTag, noLenGuard, assertSize, expvar, debugTiming, goGenerate, inverse and constants", name)
+	}
+
+	return nil
+}
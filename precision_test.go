@@ -0,0 +1,131 @@
+package genfft
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestFloat32(t *testing.T) {
+	single := Options{Precision: "float32"}
+	staged := Options{Precision: "float32", Stages: 2}
+
+	files := map[string]*jen.File{
+		"cmplx_8_f32.go":        generateNaive(t, 8, Dft{Func: "DftCmplx8F32", Options: single}),
+		"float_8_f32.go":        generateNaive(t, 8, Dft{Func: "DftFloat8F32", Options: single}),
+		"cmplx_16_f32_stage.go": generateNaive(t, 16, Dft{Func: "DftCmplx16F32Staged", Options: staged}),
+	}
+
+	if src := files["cmplx_8_f32.go"].GoString(); !regexp.MustCompile(`I\s+= complex64\(1i\)`).MatchString(src) {
+		t.Fatalf("expected the imaginary constant as complex64:\n%s", src)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+// tolerance is the mean error allowed for single precision transforms.
+const tolerance = 1e-5
+
+func TestFloat32(t *testing.T) {
+	for n, dft := range map[int]func(xi, xo []complex64){
+		8:  DftCmplx8F32,
+		16: DftCmplx16F32Staged,
+	} {
+		want := randCmplx(n)
+		xi := make([]complex64, n)
+		for idx, x := range want {
+			xi[idx] = complex64(x)
+		}
+
+		// In place.
+		dft(xi, xi)
+		naiveDFT(want, -1.0)
+
+		got := make([]complex128, n)
+		for idx, x := range xi {
+			got[idx] = complex128(x)
+		}
+		if err := dftError(got, want); err > tolerance {
+			t.Errorf("N=%d: error %g exceeds %g", n, err, tolerance)
+		}
+	}
+
+	want := randCmplx(8)
+	ri, ii := make([]float32, 8), make([]float32, 8)
+	for idx, x := range want {
+		ri[idx], ii[idx] = float32(real(x)), float32(imag(x))
+	}
+	ro, io := make([]float32, 8), make([]float32, 8)
+	DftFloat8F32(ri, ii, ro, io)
+	naiveDFT(want, -1.0)
+
+	got := make([]complex128, 8)
+	for idx := range got {
+		got[idx] = complex(float64(ro[idx]), float64(io[idx]))
+	}
+	if err := dftError(got, want); err > tolerance {
+		t.Errorf("float: error %g exceeds %g", err, tolerance)
+	}
+}
+`)
+}
+
+func TestFloat32SupportFiles(t *testing.T) {
+	dir := t.TempDir()
+	single := Options{Precision: "float32"}
+
+	var dfts []Dft
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx8"},
+		{Func: "DftCmplx8F32", Options: single},
+		{Func: "DftFloat8F32", Options: single},
+	} {
+		alst, cout := naiveSchedule(8, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+		dfts = append(dfts, dft)
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	for filename, f := range SupportFiles(dfts) {
+		files[filepath.Base(filename)] = f
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestSupport(t *testing.T) {
+	xi := make([]complex64, 8)
+	DftCmplx8F32(xi, xi)
+}
+`, "-bench", ".", "-benchtime", "1x")
+}
+
+func TestFloat32Errors(t *testing.T) {
+	alst, cout := naiveSchedule(4, false)
+	prefix := writeSchedule(t, t.TempDir(), "cmplx_4", alst, cout)
+
+	for _, tc := range []struct {
+		opts Options
+		want string
+	}{
+		{Options{Precision: "float16"}, `unknown precision "float16"`},
+		{Options{Precision: "float32", Into: true}, "float32 transforms support only"},
+		{Options{Precision: "float32", Contraction: "fma"}, "float32 transforms support only"},
+	} {
+		prog, err := Dft{Prefix: prefix, Func: "DftCmplx4", Options: tc.opts}.Parse()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = prog.Render("dft", "DftCmplx4")
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%+v: expected an error containing %q, got %v", tc.opts, tc.want, err)
+		}
+	}
+}
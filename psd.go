@@ -0,0 +1,217 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genPSD renders a wrapper computing the power spectral density of a real
+// signal: the squared magnitude of each bin of its transform. Real input has
+// a conjugate-symmetric spectrum, so only the first n/2+1 bins are written.
+// The input is transformed as a complex signal with zero imaginary part.
+func (p Program) genPSD(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: psd requires a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	bins := n/2 + 1
+	psd := name + "PSD"
+
+	k := jen.Id("k")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes the power spectral density of %d real samples in in, writing\nthe squared magnitude of the first %d bins of their transform to out.",
+		psd, n, bins,
+	))
+	f.Func().Id(psd).Params(jen.List(jen.Id("in"), jen.Id("out")).Index().Float64()).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		re, im := p.genRealTransform(g, name, "in", false)
+		g.Line()
+
+		g.For(k.Clone().Op(":=").Range().Id("out").Index(jen.Empty(), jen.Lit(bins))).Block(
+			jen.Id("out").Index(k.Clone()).Op("=").Add(re(k)).Op("*").Add(re(k)).Op("+").Add(im(k)).Op("*").Add(im(k)),
+		)
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
+
+// genMagnitude renders a wrapper computing the magnitude spectrum of a real
+// float32 signal. Like PSD only the first n/2+1 bins are written, the
+// transform itself is computed in float64 on the stack.
+func (p Program) genMagnitude(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: magnitude requires a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	bins := n/2 + 1
+	magnitude := name + "Magnitude"
+
+	k := jen.Id("k")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes the magnitude spectrum of %d real samples in in, writing\nthe magnitude of the first %d bins of their transform to out.",
+		magnitude, n, bins,
+	))
+	f.Func().Id(magnitude).Params(jen.List(jen.Id("in"), jen.Id("out")).Index().Float32()).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		re, im := p.genRealTransform(g, name, "in", true)
+		g.Line()
+
+		g.For(k.Clone().Op(":=").Range().Id("out").Index(jen.Empty(), jen.Lit(bins))).Block(
+			jen.Id("out").Index(k.Clone()).Op("=").Float32().Call(jen.Qual("math", "Hypot").Call(re(k), im(k))),
+		)
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
+
+// genHalfcomplex renders a wrapper computing the forward transform of a real
+// signal packed in FFTW's halfcomplex (r2hc) layout: the real parts of bins 0
+// through n/2 followed by the imaginary parts of bins (n-1)/2 down to 1. The
+// input is read in full before out is written, so in and out may be the same
+// slice as with FFTW's in-place real transforms.
+func (p Program) genHalfcomplex(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: halfcomplex requires a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	halfcomplex := name + "Halfcomplex"
+
+	k := jen.Id("k")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes the transform of %d real samples in in, writing it to out\nin FFTW's halfcomplex layout: r0, r1, ..., r%d, i%d, ..., i1.",
+		halfcomplex, n, n/2, (n+1)/2-1,
+	))
+	f.Func().Id(halfcomplex).Params(jen.List(jen.Id("in"), jen.Id("out")).Index().Float64()).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		re, im := p.genRealTransform(g, name, "in", false)
+		g.Id("out").Op("=").Id("out").Index(jen.Empty(), jen.Lit(n))
+		g.Line()
+
+		g.For(k.Clone().Op(":=").Lit(0), k.Clone().Op("<=").Lit(n/2), k.Clone().Op("++")).Block(
+			jen.Id("out").Index(k.Clone()).Op("=").Add(re(k)),
+		)
+		g.For(k.Clone().Op(":=").Lit(1), k.Clone().Op("<").Lit((n+1)/2), k.Clone().Op("++")).Block(
+			jen.Id("out").Index(jen.Lit(n).Op("-").Add(k.Clone())).Op("=").Add(im(k)),
+		)
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
+
+// genCrossSpectrum renders a wrapper computing the cross-spectrum of two real
+// signals: the product of each bin of the first's transform with the
+// conjugate of the same bin of the second's. Like PSD only the first n/2+1
+// bins are written. Each transform is computed in its own block so their
+// stack buffers don't collide.
+func (p Program) genCrossSpectrum(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: cross-spectrum requires a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	bins := n/2 + 1
+	cross := name + "CrossSpectrum"
+
+	k := jen.Id("k")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes the cross-spectrum of %d real samples in a and b, writing\nthe first %d bins of a's transform times the conjugate of b's to out.",
+		cross, n, bins,
+	))
+	f.Func().Id(cross).Params(
+		jen.List(jen.Id("a"), jen.Id("b")).Index().Float64(),
+		jen.Id("out").Index().Complex128(),
+	).Add(p.wrapperResults()).BlockFunc(func(g *jen.Group) {
+		g.Id("out").Op("=").Id("out").Index(jen.Empty(), jen.Lit(bins))
+		g.Line()
+
+		g.BlockFunc(func(g *jen.Group) {
+			re, im := p.genRealTransform(g, name, "a", false)
+			g.For(k.Clone().Op(":=").Range().Id("out")).Block(
+				jen.Id("out").Index(k.Clone()).Op("=").Complex(re(k), im(k)),
+			)
+		})
+		g.BlockFunc(func(g *jen.Group) {
+			re, im := p.genRealTransform(g, name, "b", false)
+			g.For(k.Clone().Op(":=").Range().Id("out")).Block(
+				jen.Id("out").Index(k.Clone()).Op("*=").Complex(re(k), jen.Op("-").Add(im(k))),
+			)
+		})
+		g.Add(p.wrapperReturn())
+	})
+
+	return nil
+}
+
+// genRealTransform renders statements transforming the real samples in the
+// slice named in as a complex signal with zero imaginary part, converting
+// them to float64 if single is set. It returns functions rendering the real
+// and imaginary parts of bin k of the result.
+func (p Program) genRealTransform(g *jen.Group, name, in string, single bool) (re, im func(k jen.Code) *jen.Statement) {
+	n := p.TransformLength()
+	k := jen.Id("k")
+
+	// Real signals are transformed forward, though the direction doesn't
+	// change the magnitude of any bin.
+	var extraArgs []jen.Code
+	if p.Options.OutputBase {
+		extraArgs = append(extraArgs, jen.Lit(0))
+	}
+	if p.Options.RuntimeSign {
+		extraArgs = append(extraArgs, jen.Lit(-1))
+	}
+	if p.Options.SignMultiplier {
+		extraArgs = append(extraArgs, jen.Lit(-1.0))
+	}
+
+	if p.Float() {
+		ri := jen.Id(in).Index(jen.Empty(), jen.Lit(n))
+		if single {
+			g.Var().List(jen.Id("re"), jen.Id("im"), jen.Id("ro"), jen.Id("io")).Index(jen.Lit(n)).Float64()
+			g.For(jen.List(k, jen.Id("x")).Op(":=").Range().Id(in).Index(jen.Empty(), jen.Lit(n))).Block(
+				jen.Id("re").Index(k).Op("=").Float64().Call(jen.Id("x")),
+			)
+			ri = jen.Id("re").Index(jen.Empty(), jen.Empty())
+		} else {
+			g.Var().List(jen.Id("im"), jen.Id("ro"), jen.Id("io")).Index(jen.Lit(n)).Float64()
+		}
+		g.Add(p.callTransform(name, nil, append([]jen.Code{
+			ri,
+			jen.Id("im").Index(jen.Empty(), jen.Empty()),
+			jen.Id("ro").Index(jen.Empty(), jen.Empty()),
+			jen.Id("io").Index(jen.Empty(), jen.Empty()),
+		}, extraArgs...)...))
+
+		re = func(k jen.Code) *jen.Statement { return jen.Id("ro").Index(k) }
+		im = func(k jen.Code) *jen.Statement { return jen.Id("io").Index(k) }
+		return re, im
+	}
+
+	x := jen.Id("x")
+	if single {
+		x = jen.Float64().Call(jen.Id("x"))
+	}
+	g.Var().List(jen.Id("xi"), jen.Id("xo")).Index(jen.Lit(n)).Complex128()
+	g.For(jen.List(k, jen.Id("x")).Op(":=").Range().Id(in).Index(jen.Empty(), jen.Lit(n))).Block(
+		jen.Id("xi").Index(k).Op("=").Complex(x, jen.Lit(0)),
+	)
+	g.Add(p.callTransform(name, nil, append([]jen.Code{
+		jen.Id("xi").Index(jen.Empty(), jen.Empty()),
+		jen.Id("xo").Index(jen.Empty(), jen.Empty()),
+	}, extraArgs...)...))
+
+	re = func(k jen.Code) *jen.Statement { return jen.Real(jen.Id("xo").Index(k)) }
+	im = func(k jen.Code) *jen.Statement { return jen.Imag(jen.Id("xo").Index(k)) }
+	return re, im
+}
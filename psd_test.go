@@ -0,0 +1,47 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestPSD(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{PSD: true}}),
+		"float_7.go": generateNaive(t, 7, Dft{Func: "DftFloat7", Options: Options{PSD: true, RuntimeSign: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func checkPSD(t *testing.T, name string, n int, psd func(in, out []float64)) {
+	x := randCmplx(n)
+	in := make([]float64, n)
+	for k := range x {
+		x[k] = complex(real(x[k]), 0)
+		in[k] = real(x[k])
+	}
+	naiveDFT(x, -1.0)
+
+	out := make([]float64, n/2+1)
+	psd(in, out)
+
+	for k := range out {
+		want := real(x[k])*real(x[k]) + imag(x[k])*imag(x[k])
+		if math.Abs(out[k]-want) > 1e-13*(1+want) {
+			t.Fatalf("%s: bin %d: %g != %g", name, k, out[k], want)
+		}
+	}
+}
+
+func TestPSD(t *testing.T) {
+	checkPSD(t, "DftCmplx8PSD", 8, DftCmplx8PSD)
+	checkPSD(t, "DftFloat7PSD", 7, DftFloat7PSD)
+}
+`)
+}
@@ -0,0 +1,93 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Operators converting a signaling NaN read from the input to a quiet NaN.
+const (
+	quietFloatOp = "QUIET64"
+	quietCmplxOp = "QUIET128"
+)
+
+func init() {
+	RegisterLowering(quietFloatOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Id("quietFloat").Call(operands...)
+	})
+	RegisterLowering(quietCmplxOp, func(operands []jen.Code) *jen.Statement {
+		return jen.Id("quietCmplx").Call(operands...)
+	})
+}
+
+// quietInputs returns the program's statements with every read of the input
+// quieting signaling NaNs, which trap or take slow paths on some hardware.
+func (p Program) quietInputs(name string) ([]Expr, error) {
+	if p.Options.Generic || p.Options.Compensated {
+		return nil, fmt.Errorf("%s: quieting NaNs requires a non-generic, uncompensated function", name)
+	}
+
+	op := quietCmplxOp
+	if p.Float() {
+		op = quietFloatOp
+	}
+
+	var quiet func(e Expr) Expr
+	quiet = func(e Expr) Expr {
+		if e.Ident != "" {
+			if slice, _, ok := elementIndex(e.Ident); ok && !isOutput(slice) {
+				return Expr{Pos: e.Pos, Op: op, Sub: []Expr{e}}
+			}
+			return e
+		}
+
+		subs := make([]Expr, len(e.Sub))
+		for idx, sub := range e.Sub {
+			subs[idx] = quiet(sub)
+		}
+		return Expr{Pos: e.Pos, Op: e.Op, Sub: subs}
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			expr = Expr{Pos: expr.Pos, Op: expr.Op, Sub: []Expr{expr.Sub[0], quiet(expr.Sub[1])}}
+		} else {
+			expr = quiet(expr)
+		}
+		statements[idx] = expr
+	}
+
+	return statements, nil
+}
+
+// quietSupport renders the helpers converting signaling NaNs to quiet NaNs.
+func quietSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("quietBit is the most significant bit of a float64's mantissa, set in quiet\nNaNs and clear in signaling NaNs.")
+	f.Const().Id("quietBit").Op("=").Lit(1).Op("<<").Lit(51)
+	f.Line()
+
+	f.Comment("quietFloat returns x, with the quiet bit set if it's a NaN.")
+	f.Func().Id("quietFloat").Params(jen.Id("x").Float64()).Float64().Block(
+		jen.If(jen.Id("x").Op("!=").Id("x")).Block(
+			jen.Return(jen.Qual("math", "Float64frombits").Call(
+				jen.Qual("math", "Float64bits").Call(jen.Id("x")).Op("|").Id("quietBit"),
+			)),
+		),
+		jen.Return(jen.Id("x")),
+	)
+	f.Line()
+
+	f.Comment("quietCmplx quiets the real and imaginary parts of x independently.")
+	f.Func().Id("quietCmplx").Params(jen.Id("x").Complex128()).Complex128().Block(
+		jen.Return(jen.Complex(
+			jen.Id("quietFloat").Call(jen.Real(jen.Id("x"))),
+			jen.Id("quietFloat").Call(jen.Imag(jen.Id("x"))),
+		)),
+	)
+
+	return f
+}
@@ -0,0 +1,57 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestQuietNaN(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_4.go": generateNaive(t, 4, Dft{Func: "DftFloat4", Options: Options{QuietNaN: true}}),
+		"cmplx_4.go": generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: Options{QuietNaN: true}}),
+		"quiet.go":   quietSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+// signaling is a signaling NaN: all ones exponent, quiet bit clear.
+var signaling = math.Float64frombits(0x7ff0000000000001)
+
+func isQuiet(x float64) bool {
+	return x != x && math.Float64bits(x)&quietBit != 0
+}
+
+func TestQuietNaN(t *testing.T) {
+	if q := quietFloat(signaling); !isQuiet(q) {
+		t.Fatalf("quietFloat: %#x isn't a quiet NaN", math.Float64bits(q))
+	}
+	if x := quietFloat(1.5); x != 1.5 {
+		t.Fatalf("quietFloat changed a number: %g", x)
+	}
+
+	// The first input contributes to every output unscaled.
+	ri, ii := []float64{signaling, 2, 3, 4}, make([]float64, 4)
+	ro, io := make([]float64, 4), make([]float64, 4)
+	DftFloat4(ri, ii, ro, io)
+	for k := range ro {
+		if !isQuiet(ro[k]) {
+			t.Fatalf("float: output %d is %#x, want a quiet NaN", k, math.Float64bits(ro[k]))
+		}
+	}
+
+	xo := make([]complex128, 4)
+	DftCmplx4([]complex128{complex(1, signaling), 2, 3, 4}, xo)
+	for k := range xo {
+		if !isQuiet(imag(xo[k])) {
+			t.Fatalf("cmplx: output %d is %v, want a quiet NaN", k, xo[k])
+		}
+	}
+}
+`)
+}
@@ -0,0 +1,154 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// zero is the literal substituted for operands known to be zero.
+var zero = Expr{Ident: "0"}
+
+// withZeroInput simplifies an expression given that the input slice named
+// slice and the temporaries in zeros are all zero. It reports whether the
+// whole expression is zero.
+func (e Expr) withZeroInput(slice string, zeros map[string]bool) (Expr, bool) {
+	if e.Ident != "" {
+		name, _, ok := elementIndex(e.Ident)
+		if e.Ident == "0" || zeros[e.Ident] || (ok && name == slice) {
+			return zero, true
+		}
+		return e, false
+	}
+
+	subs := make([]Expr, len(e.Sub))
+	isZero := make([]bool, len(e.Sub))
+	for idx, sub := range e.Sub {
+		subs[idx], isZero[idx] = sub.withZeroInput(slice, zeros)
+	}
+
+	switch {
+	// Negating zero is zero.
+	case len(subs) == 1 && e.Op == "-":
+		return Expr{Op: e.Op, Sub: subs}, isZero[0]
+
+	// A product with a zero factor is zero.
+	case e.Op == "*":
+		for _, z := range isZero {
+			if z {
+				return zero, true
+			}
+		}
+
+	// Sums drop zero terms, keeping the first of a difference as its sign.
+	case e.Op == "+" || e.Op == "-":
+		var terms []Expr
+		for idx, sub := range subs {
+			if !isZero[idx] {
+				terms = append(terms, sub)
+			}
+		}
+
+		switch {
+		case len(terms) == 0:
+			return zero, true
+		case e.Op == "-" && isZero[0]:
+			rest := Expr{Op: "+", Sub: terms}
+			if len(terms) == 1 {
+				rest = terms[0]
+			}
+			return Expr{Op: "-", Sub: []Expr{rest}}, false
+		case len(terms) == 1:
+			return terms[0], false
+		}
+		return Expr{Op: e.Op, Sub: terms}, false
+	}
+
+	return Expr{Op: e.Op, Sub: subs}, false
+}
+
+// zeroInputStatements returns the program's statements simplified for an
+// input slice that is all zero. Temporaries that are zero or no longer read
+// are dropped, and outputs that are zero are assigned it.
+func (p Program) zeroInputStatements(slice string) []Expr {
+	zeros := map[string]bool{}
+
+	var statements []Expr
+	for _, expr := range p.Statements {
+		rhs, isZero := expr.Sub[1].withZeroInput(slice, zeros)
+		if temp, ok := expr.Temporary(); ok && isZero {
+			zeros[temp] = true
+			continue
+		}
+		statements = append(statements, Expr{Op: expr.Op, Sub: []Expr{expr.Sub[0], rhs}})
+	}
+
+	// Drop assignments to temporaries no longer read, until none remain.
+	for dropped := true; dropped; {
+		dropped = false
+
+		read := map[string]bool{}
+		for _, expr := range statements {
+			for _, id := range expr.Sub[1].Idents() {
+				read[id] = true
+			}
+		}
+
+		live := statements[:0]
+		for _, expr := range statements {
+			if temp, ok := expr.Temporary(); ok && !read[temp] {
+				dropped = true
+				continue
+			}
+			live = append(live, expr)
+		}
+		statements = live
+	}
+
+	return statements
+}
+
+// genRealInput renders a check for input with an all zero imaginary part,
+// computing the transform with the simplified statements and returning early
+// if it is.
+func (p Program) genRealInput(g *jen.Group, name string) error {
+	return p.genZeroInput(g, name, "ii", "real", "realInput")
+}
+
+// genImagInput renders the same check for input with an all zero real part.
+func (p Program) genImagInput(g *jen.Group, name string) error {
+	return p.genZeroInput(g, name, "ri", "imaginary", "imagInput")
+}
+
+// genZeroInput renders a check, recorded in the variable named flag, for
+// input whose slice named slice is all zero, computing the transform with
+// the simplified statements and returning early if it is. The path is named
+// for the input it's taken for.
+func (p Program) genZeroInput(g *jen.Group, name, slice, path, flagName string) error {
+	if !p.Float() {
+		return fmt.Errorf("%s: the %s input path requires a float schedule", name, path)
+	}
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: the %s input path requires a unit-stride schedule", name, path)
+	}
+
+	n := p.TransformLength()
+	v := jen.Id("v")
+	flag := jen.Id(flagName)
+	g.Add(flag).Op(":=").True()
+	g.For(jen.List(jen.Id("_"), v).Op(":=").Range().Id(slice).Index(jen.Empty(), jen.Lit(n))).Block(
+		jen.If(v.Clone().Op("!=").Lit(0)).Block(
+			flag.Clone().Op("=").False(),
+			jen.Break(),
+		),
+	)
+	g.If(flag.Clone()).BlockFunc(func(g *jen.Group) {
+		for _, expr := range p.zeroInputStatements(slice) {
+			g.Add(p.genStatement(expr))
+		}
+		g.Return()
+	})
+	g.Line()
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRealInput(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_16.go":      generateNaive(t, 16, Dft{Func: "DftFloat16"}),
+		"float_16_real.go": generateNaive(t, 16, Dft{Func: "DftFloat16Real", Options: Options{RealInput: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func compareReal(t *testing.T, path string, ri, ii []float64) {
+	wantR, wantI := make([]float64, 16), make([]float64, 16)
+	DftFloat16(ri, ii, wantR, wantI)
+
+	gotR, gotI := make([]float64, 16), make([]float64, 16)
+	DftFloat16Real(ri, ii, gotR, gotI)
+
+	for idx := range wantR {
+		if gotR[idx] != wantR[idx] || gotI[idx] != wantI[idx] {
+			t.Fatalf("%s path: output differs at %d: (%g, %g) != (%g, %g)",
+				path, idx, gotR[idx], gotI[idx], wantR[idx], wantI[idx])
+		}
+	}
+}
+
+func TestRealInput(t *testing.T) {
+	xi := randCmplx(16)
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	compareReal(t, "general", ri, ii)
+
+	compareReal(t, "real", ri, make([]float64, 16))
+
+	// A single nonzero imaginary part takes the general path.
+	ii = make([]float64, 16)
+	ii[15] = 1
+	compareReal(t, "general", ri, ii)
+}
+`)
+}
+
+func TestImagInput(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_12.go":      generateNaive(t, 12, Dft{Func: "DftFloat12"}),
+		"float_12_fast.go": generateNaive(t, 12, Dft{Func: "DftFloat12Fast", Options: Options{RealInput: true, ImagInput: true}}),
+	}
+	if src := files["float_12_fast.go"].GoString(); !strings.Contains(src, "range ri[:12]") {
+		t.Fatalf("missing imaginary input check:\n%s", src)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func compareFast(t *testing.T, path string, ri, ii []float64) {
+	wantR, wantI := make([]float64, 12), make([]float64, 12)
+	DftFloat12(ri, ii, wantR, wantI)
+
+	gotR, gotI := make([]float64, 12), make([]float64, 12)
+	DftFloat12Fast(ri, ii, gotR, gotI)
+
+	for idx := range wantR {
+		if gotR[idx] != wantR[idx] || gotI[idx] != wantI[idx] {
+			t.Fatalf("%s path: output differs at %d: (%g, %g) != (%g, %g)",
+				path, idx, gotR[idx], gotI[idx], wantR[idx], wantI[idx])
+		}
+	}
+}
+
+func TestImagInput(t *testing.T) {
+	xi := randCmplx(12)
+	ri, ii := make([]float64, 12), make([]float64, 12)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	compareFast(t, "general", ri, ii)
+	compareFast(t, "real", ri, make([]float64, 12))
+	compareFast(t, "imaginary", make([]float64, 12), ii)
+}
+`)
+}
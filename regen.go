@@ -0,0 +1,134 @@
+package genfft
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// RegenCheck regenerates each dft and compares it against the committed
+// output, returning the filenames of any codelets that differ, or an error
+// if a dft can't be regenerated.
+func RegenCheck(dfts []Dft) (drifted []string, err error) {
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dft.Prefix, err)
+		}
+
+		files := map[string]*jen.File{}
+		if files[dft.Prefix+".go"], err = prog.Render("dft", dft.Func); err != nil {
+			return nil, fmt.Errorf("%s: %w", dft.Prefix, err)
+		}
+
+		// Transforms gated by the inplace tag also have an in-place variant.
+		if dft.InPlaceTag {
+			if files[dft.Prefix+"_inplace.go"], err = prog.RenderInPlace("dft", dft.Func); err != nil {
+				return nil, fmt.Errorf("%s: %w", dft.Prefix, err)
+			}
+		}
+
+		for _, goFilename := range sortedKeys(files) {
+			matches, err := matchesCommitted(goFilename, files[goFilename])
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				drifted = append(drifted, goFilename)
+			}
+		}
+	}
+
+	return drifted, nil
+}
+
+// matchesCommitted renders f to memory and reports whether it matches the
+// committed file, logging any differences.
+func matchesCommitted(goFilename string, f *jen.File) (bool, error) {
+	buf := &bytes.Buffer{}
+	err := f.Render(buf)
+	if err != nil {
+		return false, fmt.Errorf("%s: f.Render: %w", goFilename, err)
+	}
+
+	committed, err := os.ReadFile(goFilename)
+	if err != nil {
+		log.Errorf("%+v\n", fmt.Errorf("os.ReadFile: %w", err))
+		return false, nil
+	}
+
+	// Committed files may have been checked out with CRLF line endings.
+	committed = bytes.ReplaceAll(committed, []byte("\r\n"), []byte("\n"))
+
+	if bytes.Equal(committed, buf.Bytes()) {
+		return true, nil
+	}
+
+	log.Errorf("%s differs from its schedule:\n%s", goFilename,
+		strings.Join(diffLines(string(committed), buf.String()), "\n"),
+	)
+	return false, nil
+}
+
+// sortedKeys returns the filenames of files in order.
+func sortedKeys(files map[string]*jen.File) []string {
+	keys := make([]string, 0, len(files))
+	for key := range files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffLines returns a minimal line diff between a and b. Lines only in a are
+// prefixed with "-", lines only in b with "+".
+func diffLines(a, b string) (diff []string) {
+	al := strings.Split(a, "\n")
+	bl := strings.Split(b, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of al[i:] and bl[j:].
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	// Walk the table, emitting only lines that aren't common to both.
+	i, j := 0, 0
+	for i < len(al) && j < len(bl) {
+		switch {
+		case al[i] == bl[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "-"+al[i])
+			i++
+		default:
+			diff = append(diff, "+"+bl[j])
+			j++
+		}
+	}
+	for ; i < len(al); i++ {
+		diff = append(diff, "-"+al[i])
+	}
+	for ; j < len(bl); j++ {
+		diff = append(diff, "+"+bl[j])
+	}
+
+	return
+}
@@ -0,0 +1,68 @@
+package genfft
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// copyTestdata copies a schedule and its constants into dir, returning the new prefix.
+func copyTestdata(t *testing.T, dir, name string) string {
+	t.Helper()
+	for _, ext := range []string{".alst", ".cout"} {
+		b, err := os.ReadFile(filepath.Join("testdata", name+ext))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(dir, name+ext), b, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return filepath.Join(dir, name)
+}
+
+func TestRegenCheck(t *testing.T) {
+	dir := t.TempDir()
+	dft := Dft{Prefix: copyTestdata(t, dir, "cmplx_3"), Func: "DftCmplx3"}
+	goFilename := dft.Prefix + ".go"
+
+	err := mustGenerate(t, dft).Save(goFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if drifted, err := RegenCheck([]Dft{dft}); err != nil || len(drifted) != 0 {
+		t.Fatalf("freshly generated codelet reported as drifted: %v", drifted)
+	}
+
+	// Simulate a hand edit to the committed codelet.
+	b, err := os.ReadFile(goFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = []byte(strings.Replace(string(b), "T5 + T6", "T5 - T6", 1))
+	err = os.WriteFile(goFilename, b, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	drifted, err := RegenCheck([]Dft{dft})
+	if err != nil || len(drifted) != 1 || drifted[0] != goFilename {
+		t.Fatalf("expected %s to be drifted, got %v, %v", goFilename, drifted, err)
+	}
+
+	// Schedules that can't be read are reported rather than exiting.
+	missing := Dft{Prefix: filepath.Join(dir, "missing"), Func: "DftCmplx4"}
+	if _, err := RegenCheck([]Dft{dft, missing}); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("expected an error for a missing schedule, got %v", err)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	diff := diffLines("a\nb\nc", "a\nx\nc")
+	if strings.Join(diff, ",") != "-b,+x" {
+		t.Fatalf("unexpected diff: %q", diff)
+	}
+}
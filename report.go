@@ -0,0 +1,54 @@
+package genfft
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// OpCount is the arithmetic cost of one evaluation of a transform, in real
+// operations, comparable to the operation counts FFTW publishes for its
+// codelets.
+type OpCount struct {
+	Func  string
+	Kind  string
+	Size  int
+	Adds  int
+	Mults int
+}
+
+// OpCounts parses the schedule of each transform and counts the operations
+// it performs, in the order of dfts.
+func OpCounts(dfts []Dft) (counts []OpCount, err error) {
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", dft.Func, err)
+		}
+
+		kind := "complex"
+		if prog.Float() {
+			kind = "float"
+		}
+
+		adds, mults := prog.OpCount()
+		counts = append(counts, OpCount{dft.Func, kind, prog.TransformLength(), adds, mults})
+	}
+
+	return counts, nil
+}
+
+// FprintOpCounts writes a table of operation counts to w, one transform per
+// row.
+func FprintOpCounts(w io.Writer, counts []OpCount) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "func\tkind\tsize\tadds\tmults\tflops\t")
+	for _, c := range counts {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t\n", c.Func, c.Kind, c.Size, c.Adds, c.Mults, c.Adds+c.Mults)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("tw.Flush: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package genfft
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOpCounts(t *testing.T) {
+	dir := t.TempDir()
+
+	alst, cout := naiveSchedule(4, true)
+	dfts := []Dft{
+		{Prefix: copyTestdata(t, dir, "cmplx_3"), Func: "DftCmplx3"},
+		{Prefix: writeSchedule(t, dir, "DftFloat4", alst, cout), Func: "DftFloat4"},
+	}
+
+	counts, err := OpCounts(dfts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Six complex additions and two multiplications by real constants.
+	if got, want := counts[0], (OpCount{"DftCmplx3", "complex", 3, 12, 4}); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	prog := mustParse(t, dfts[1])
+	if c := counts[1]; c.Kind != "float" || c.Size != 4 || c.Adds+c.Mults != prog.Flops() {
+		t.Errorf("expected a float transform of size 4 performing %d flops, got %+v", prog.Flops(), c)
+	}
+
+	var buf bytes.Buffer
+	if err := FprintOpCounts(&buf, counts); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || strings.Join(strings.Fields(lines[1]), " ") != "DftCmplx3 complex 3 12 4 16" {
+		t.Fatalf("unexpected report:\n%s", buf.String())
+	}
+}
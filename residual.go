@@ -0,0 +1,64 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genResidual renders a variant of the transform that also returns how far
+// the result is from conserving energy. By Parseval's theorem the input's
+// energy equals the output's energy divided by the transform length, so a
+// large residual indicates a gross error.
+func (p Program) genResidual(f *jen.File, name string) {
+	n := p.TransformLength()
+	residual := name + "Residual"
+	extraParams, extraArgs := p.passThrough()
+
+	args, argType := p.Args()
+	params := append([]jen.Code{jen.List(args...).Index().Add(argType)}, extraParams...)
+	call := append(args, extraArgs...)
+
+	// energy renders a sum of squared magnitudes over the first n elements
+	// of the given slices.
+	energy := func(g *jen.Group, sum string, slices ...string) {
+		g.Var().Id(sum).Float64()
+		g.For(jen.Id("k").Op(":=").Lit(0), jen.Id("k").Op("<").Lit(n), jen.Id("k").Op("++")).BlockFunc(func(g *jen.Group) {
+			if p.Float() {
+				for _, s := range slices {
+					g.Id(sum).Op("+=").Id(s).Index(jen.Id("k")).Op("*").Id(s).Index(jen.Id("k"))
+				}
+				return
+			}
+			x := jen.Id(slices[0]).Index(jen.Id("k"))
+			g.Id(sum).Op("+=").Real(x).Op("*").Real(x.Clone()).Op("+").Imag(x.Clone()).Op("*").Imag(x.Clone())
+		})
+	}
+
+	inputs, outputs := []string{"xi"}, []string{"xo"}
+	if p.Float() {
+		inputs, outputs = []string{"ri", "ii"}, []string{"ro", "io"}
+	}
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes %s and returns the relative difference between the\ninput's energy and the output's energy over %d, zero for an exact transform.",
+		residual, name, n,
+	))
+	f.Func().Id(residual).Params(params...).Add(p.wrapperResults(jen.Float64())).BlockFunc(func(g *jen.Group) {
+		// Measure the input before an in-place transform overwrites it.
+		energy(g, "in", inputs...)
+		g.Line()
+
+		g.Add(p.callTransform(name, []jen.Code{jen.Lit(0)}, call...))
+		g.Line()
+
+		energy(g, "out", outputs...)
+		g.Line()
+
+		g.If(jen.Id("in").Op("==").Lit(0)).Block(
+			p.wrapperReturn(jen.Id("out")),
+		)
+		g.Add(p.wrapperReturn(jen.Qual("math", "Abs").Call(jen.Id("in").Op("-").Id("out").Op("/").Lit(float64(n))).Op("/").Id("in")))
+	})
+}
@@ -0,0 +1,40 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestResidual(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Residual: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Residual: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestResidual(t *testing.T) {
+	for trial := 0; trial < 100; trial++ {
+		xi := randCmplx(8)
+		if r := DftCmplx8Residual(xi, make([]complex128, 8)); r > 1e-14 {
+			t.Fatalf("DftCmplx8Residual: %g", r)
+		}
+
+		ri, ii := make([]float64, 8), make([]float64, 8)
+		for k, x := range xi {
+			ri[k], ii[k] = real(x), imag(x)
+		}
+		if r := DftFloat8Residual(ri, ii, ri, ii); r > 1e-14 {
+			t.Fatalf("DftFloat8Residual in-place: %g", r)
+		}
+	}
+
+	if r := DftCmplx8Residual(make([]complex128, 8), make([]complex128, 8)); r != 0 {
+		t.Fatalf("zero input residual %g", r)
+	}
+}
+`)
+}
@@ -0,0 +1,68 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genRing renders a variant of the transform reading its input from a ring
+// buffer of the transform's length, starting at position start and wrapping
+// around its end. Power-of-two lengths wrap by masking instead of modulo.
+func (p Program) genRing(f *jen.File, name string) error {
+	if len(p.Strides()) > 0 {
+		return fmt.Errorf("%s: ring buffers require a unit-stride schedule", name)
+	}
+
+	n := p.TransformLength()
+	ring := name + "Ring"
+
+	wrap := func(k int) string {
+		switch {
+		case k == 0 && n&(n-1) == 0:
+			return fmt.Sprintf("start&%d", n-1)
+		case k == 0:
+			return fmt.Sprintf("start%%%d", n)
+		case n&(n-1) == 0:
+			return fmt.Sprintf("(start+%d)&%d", k, n-1)
+		default:
+			return fmt.Sprintf("(start+%d)%%%d", k, n)
+		}
+	}
+
+	mapIndex := func(id string) string {
+		slice, k, ok := splitIndex(id)
+		if !ok || isOutput(slice) {
+			return id
+		}
+		return fmt.Sprintf("%s[%s]", slice, wrap(k))
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(mapIndex)
+	}
+	p.Statements = statements
+
+	// The ring buffer is never the output.
+	p.inPlace = false
+
+	args, argType := p.Args()
+	extraParams, _ := p.passThrough()
+	params := append([]jen.Code{
+		jen.List(args...).Index().Add(argType),
+		jen.Id("start").Int(),
+	}, extraParams...)
+
+	inputs := "xi"
+	if p.Float() {
+		inputs = "ri and ii"
+	}
+
+	f.Line()
+	doc := fmt.Sprintf(
+		"%s computes %s of the %d-sample ring buffer %s, whose oldest sample is at\nposition start, without copying its samples into order.",
+		ring, name, n, inputs,
+	)
+	return p.genFunc(f, ring, []string{doc}, params)
+}
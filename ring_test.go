@@ -0,0 +1,60 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRing(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Ring: true}}),
+		"cmplx_6.go": generateNaive(t, 6, Dft{Func: "DftCmplx6", Options: Options{Ring: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Ring: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func checkRing(t *testing.T, name string, n int, dft, ring func(xi, xo []complex128, start int)) {
+	buf := randCmplx(n)
+	for start := 0; start < n; start++ {
+		linear := append(append([]complex128(nil), buf[start:]...), buf[:start]...)
+		want := make([]complex128, n)
+		dft(linear, want, 0)
+
+		got := make([]complex128, n)
+		ring(buf, got, start)
+		for idx := range want {
+			if got[idx] != want[idx] {
+				t.Fatalf("%s start=%d: output differs at %d", name, start, idx)
+			}
+		}
+	}
+}
+
+func TestRing(t *testing.T) {
+	checkRing(t, "DftCmplx8Ring", 8, func(xi, xo []complex128, _ int) { DftCmplx8(xi, xo) }, DftCmplx8Ring)
+	checkRing(t, "DftCmplx6Ring", 6, func(xi, xo []complex128, _ int) { DftCmplx6(xi, xo) }, DftCmplx6Ring)
+
+	float := func(dft func(ri, ii, ro, io []float64, start int)) func(xi, xo []complex128, start int) {
+		return func(xi, xo []complex128, start int) {
+			ri, ii := make([]float64, 8), make([]float64, 8)
+			for idx, x := range xi {
+				ri[idx], ii[idx] = real(x), imag(x)
+			}
+			ro, io := make([]float64, 8), make([]float64, 8)
+			dft(ri, ii, ro, io, start)
+			for idx := range xo {
+				xo[idx] = complex(ro[idx], io[idx])
+			}
+		}
+	}
+	checkRing(t, "DftFloat8Ring", 8,
+		float(func(ri, ii, ro, io []float64, _ int) { DftFloat8(ri, ii, ro, io) }),
+		float(DftFloat8Ring),
+	)
+}
+`)
+}
@@ -0,0 +1,146 @@
+package genfft
+
+import (
+	"path/filepath"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// roundTripTolerance is the largest error relative to the input's magnitude
+// allowed after a forward and inverse transform.
+const roundTripTolerance = 1e-12
+
+// roundTripSupport renders, for each package directory, a test applying
+// every transform whose direction is chosen at runtime forward and then
+// inverse, asserting that scaling by 1/n recovers the input. Strided
+// transforms are skipped.
+func roundTripSupport(dfts []Dft) map[string]*jen.File {
+	tests := map[string][]jen.Code{}
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err != nil {
+			log.Warnf("skipping round trip test of %s: %+v\n", dft.Func, err)
+			continue
+		}
+		if !(dft.RuntimeSign || dft.SignMultiplier) || dft.Generic || dft.Strided || len(prog.Strides()) > 0 {
+			continue
+		}
+
+		params := []jen.Code{jen.List(jen.Id("xi"), jen.Id("xo")).Index().Complex128()}
+		args := []jen.Code{jen.Id("xi"), jen.Id("xo")}
+		helper := "roundTripCmplx"
+		if prog.Float() {
+			params = []jen.Code{jen.List(jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")).Index().Float64()}
+			args = []jen.Code{jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")}
+			helper = "roundTripFloat"
+		}
+		if dft.OutputBase {
+			args = append(args, jen.Lit(0))
+		}
+		if dft.SignMultiplier {
+			args = append(args, jen.Float64().Call(jen.Id("sign")))
+		} else {
+			args = append(args, jen.Id("sign"))
+		}
+
+		dir := filepath.Dir(dft.Prefix)
+		tests[dir] = append(tests[dir], jen.Id("t").Dot("Run").Call(
+			jen.Lit(dft.Func),
+			jen.Func().Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(
+				jen.Id(helper).Call(
+					jen.Id("t"),
+					jen.Lit(prog.TransformLength()),
+					jen.Func().Params(append(params, jen.Id("sign").Int())...).Block(
+						jen.Id(dft.Func).Call(args...),
+					),
+				),
+			),
+		))
+	}
+
+	files := map[string]*jen.File{}
+	for dir, runs := range tests {
+		f := jen.NewFilePathName("dft", "dft")
+		genRoundTripHelpers(f)
+		f.Func().Id("TestRoundTrip").Params(jen.Id("t").Op("*").Qual("testing", "T")).Block(runs...)
+		files[filepath.Join(dir, "roundtrip_test.go")] = f
+	}
+
+	return files
+}
+
+// genRoundTripHelpers renders the helpers checking a complex or float
+// transform's round trip, given a function computing it in the direction of
+// sign.
+func genRoundTripHelpers(f *jen.File) {
+	n := jen.Id("n")
+	idx := jen.Id("idx")
+	fn := jen.Id("fn")
+	scale := jen.Float64().Call(n.Clone())
+
+	// The input is deterministic so failures are reproducible.
+	sample := func(part int) *jen.Statement {
+		if part == 0 {
+			return jen.Float64().Call(idx.Clone().Op("+").Lit(1))
+		}
+		return jen.Float64().Call(n.Clone().Op("-").Add(idx.Clone()))
+	}
+
+	failure := func(got, want jen.Code) *jen.Statement {
+		return jen.If(jen.Qual("math/cmplx", "Abs").Call(jen.Add(got).Op("-").Add(want)).Op(">").Lit(roundTripTolerance).Op("*").Qual("math/cmplx", "Abs").Call(want)).Block(
+			jen.Id("t").Dot("Fatalf").Call(jen.Lit("element %d: got %v, want %v"), idx.Clone(), got, want),
+		)
+	}
+
+	f.Comment("roundTripCmplx asserts that transforming an input forward and then inverse,\nand scaling by 1/n, recovers the input.")
+	f.Func().Id("roundTripCmplx").Params(
+		jen.Id("t").Op("*").Qual("testing", "T"),
+		n.Clone().Int(),
+		fn.Clone().Func().Params(jen.List(jen.Id("xi"), jen.Id("xo")).Index().Complex128(), jen.Id("sign").Int()),
+	).Block(
+		jen.List(jen.Id("xi"), jen.Id("spectrum"), jen.Id("xo")).Op(":=").List(makes(jen.Complex128, 3)...),
+		jen.For(idx.Clone().Op(":=").Range().Id("xi")).Block(
+			jen.Id("xi").Index(idx.Clone()).Op("=").Complex(sample(0), sample(1)),
+		),
+		jen.Line(),
+		fn.Clone().Call(jen.Id("xi"), jen.Id("spectrum"), jen.Lit(-1)),
+		fn.Clone().Call(jen.Id("spectrum"), jen.Id("xo"), jen.Lit(1)),
+		jen.Line(),
+		jen.For(idx.Clone().Op(":=").Range().Id("xo")).Block(
+			failure(jen.Id("xo").Index(idx.Clone()).Op("/").Complex(scale.Clone(), jen.Lit(0)), jen.Id("xi").Index(idx.Clone())),
+		),
+	)
+	f.Line()
+
+	f.Comment("roundTripFloat asserts the same of a float transform.")
+	f.Func().Id("roundTripFloat").Params(
+		jen.Id("t").Op("*").Qual("testing", "T"),
+		n.Clone().Int(),
+		fn.Clone().Func().Params(jen.List(jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")).Index().Float64(), jen.Id("sign").Int()),
+	).Block(
+		jen.List(jen.Id("ri"), jen.Id("ii"), jen.Id("sr"), jen.Id("si"), jen.Id("ro"), jen.Id("io")).Op(":=").List(makes(jen.Float64, 6)...),
+		jen.For(idx.Clone().Op(":=").Range().Id("ri")).Block(
+			jen.List(jen.Id("ri").Index(idx.Clone()), jen.Id("ii").Index(idx.Clone())).Op("=").List(sample(0), sample(1)),
+		),
+		jen.Line(),
+		fn.Clone().Call(jen.Id("ri"), jen.Id("ii"), jen.Id("sr"), jen.Id("si"), jen.Lit(-1)),
+		fn.Clone().Call(jen.Id("sr"), jen.Id("si"), jen.Id("ro"), jen.Id("io"), jen.Lit(1)),
+		jen.Line(),
+		jen.For(idx.Clone().Op(":=").Range().Id("ro")).Block(
+			failure(
+				jen.Complex(jen.Id("ro").Index(idx.Clone()), jen.Id("io").Index(idx.Clone())).Op("/").Complex(scale.Clone(), jen.Lit(0)),
+				jen.Complex(jen.Id("ri").Index(idx.Clone()), jen.Id("ii").Index(idx.Clone())),
+			),
+		),
+	)
+	f.Line()
+}
+
+// makes returns count calls making slices of n elements of type elem.
+func makes(elem func() *jen.Statement, count int) (codes []jen.Code) {
+	for idx := 0; idx < count; idx++ {
+		codes = append(codes, jen.Make(jen.Index().Add(elem()), jen.Id("n")))
+	}
+	return codes
+}
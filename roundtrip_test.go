@@ -0,0 +1,45 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	files := map[string]*jen.File{}
+	for _, tc := range []struct {
+		n   int
+		dft Dft
+	}{
+		{3, Dft{Func: "DftCmplx3", Options: Options{RuntimeSign: true}}},
+		{8, Dft{Func: "DftCmplx8", Options: Options{RuntimeSign: true, OutputBase: true}}},
+		{6, Dft{Func: "DftCmplx6", Options: Options{SignMultiplier: true}}},
+		{5, Dft{Func: "DftFloat5", Options: Options{RuntimeSign: true}}},
+		{4, Dft{Func: "DftFloat4", Options: Options{SignMultiplier: true}}},
+		// Transforms without an inverse are skipped.
+		{4, Dft{Func: "DftCmplx4"}},
+	} {
+		alst, cout := naiveSchedule(tc.n, tc.dft.Func[3] == 'F')
+		tc.dft.Prefix = writeSchedule(t, dir, tc.dft.Func, alst, cout)
+		dfts = append(dfts, tc.dft)
+		files[tc.dft.Func+".go"] = mustGenerate(t, tc.dft)
+	}
+
+	support := roundTripSupport(dfts)
+	if len(support) != 1 {
+		t.Fatalf("expected one test file, got %d", len(support))
+	}
+	for _, f := range support {
+		if src := f.GoString(); strings.Contains(src, "DftCmplx4") {
+			t.Fatalf("round trip test includes a transform without an inverse:\n%s", src)
+		}
+		files["roundtrip_test.go"] = f
+	}
+
+	goTest(t, files, "package dft\n", "-run", "RoundTrip", "-v")
+}
@@ -0,0 +1,43 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genScaled renders a variant of the transform multiplying every output by a
+// scale factor as it's written, fusing the scaling of an inverse transform
+// or an overlap-add window into the transform itself.
+func (p Program) genScaled(f *jen.File, name string) error {
+	scaled := name + "Scaled"
+
+	factor := Expr{Ident: "scale"}
+	if !p.Float() {
+		factor = Expr{Ident: "complex(scale, 0)"}
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		if expr.Op == ":=" && len(expr.Sub) == 2 && strings.HasSuffix(expr.Sub[0].Ident, "]") {
+			if slice, _, ok := elementIndex(expr.Sub[0].Ident); ok && isOutput(slice) {
+				expr = Expr{Pos: expr.Pos, Op: ":=", Sub: []Expr{
+					expr.Sub[0],
+					{Op: "*", Sub: []Expr{factor, expr.Sub[1]}},
+				}}
+			}
+		}
+		statements[idx] = expr
+	}
+	p.Statements = statements
+
+	args, argType := p.Args()
+	extraParams, _ := p.passThrough()
+	params := append([]jen.Code{jen.List(args...).Index().Add(argType)}, extraParams...)
+	params = append(params, jen.Id("scale").Float64())
+
+	f.Line()
+	doc := fmt.Sprintf("%s computes %s with every output multiplied by scale.", scaled, name)
+	return p.genFunc(f, scaled, []string{doc}, params)
+}
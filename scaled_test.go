@@ -0,0 +1,48 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestScaled(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Scaled: true, RuntimeSign: true}}),
+		"float_5.go": generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{Scaled: true, RuntimeSign: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestScaled(t *testing.T) {
+	const scale = 0.125
+
+	xi := randCmplx(8)
+	want, got := make([]complex128, 8), make([]complex128, 8)
+	DftCmplx8(xi, want, 1)
+	for k := range want {
+		want[k] *= scale
+	}
+	DftCmplx8Scaled(xi, got, 1, scale)
+	if err := dftError(got, want); err > 1e-15 {
+		t.Fatalf("cmplx: error %g", err)
+	}
+
+	ri, ii := make([]float64, 5), make([]float64, 5)
+	for k, x := range randCmplx(5) {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 5), make([]float64, 5)
+	sro, sio := make([]float64, 5), make([]float64, 5)
+	DftFloat5(ri, ii, ro, io, 1)
+	DftFloat5Scaled(ri, ii, sro, sio, 1, scale)
+	for k := range ro {
+		if d := complex(sro[k]-scale*ro[k], sio[k]-scale*io[k]); real(d)*real(d)+imag(d)*imag(d) > 1e-30 {
+			t.Fatalf("float: output %d differs by %v", k, d)
+		}
+	}
+}
+`)
+}
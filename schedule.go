@@ -0,0 +1,208 @@
+package genfft
+
+import "sort"
+
+// memorySlot identifies an element of the transform's arguments. Input and
+// output slices may alias for in-place transforms, so xi[k] and xo[k] share a
+// slot, as do ri[k] and ro[k], and ii[k] and io[k].
+type memorySlot struct {
+	slice byte
+	index int
+}
+
+// slot returns the memory slot an indexed identifier refers to.
+func slot(id string) (memorySlot, bool) {
+	name, k, ok := elementIndex(id)
+	if !ok || name == "" {
+		return memorySlot{}, false
+	}
+	return memorySlot{name[0], k}, true
+}
+
+// Dependencies returns, for each statement, the earlier statements that must
+// be evaluated before it: those assigning a temporary it uses, and those
+// reading or writing a memory slot it writes or reads.
+func Dependencies(statements []Expr) [][]int {
+	deps := make([][]int, len(statements))
+
+	assigned := map[string]int{}
+	lastWrite := map[memorySlot]int{}
+	reads := map[memorySlot][]int{}
+
+	for idx, expr := range statements {
+		seen := map[int]bool{}
+		depend := func(d int) {
+			if !seen[d] {
+				seen[d] = true
+				deps[idx] = append(deps[idx], d)
+			}
+		}
+
+		// The left side of the assignment, if any, is written not read.
+		operands := expr.Sub
+		var written string
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			written, operands = expr.Sub[0].Ident, expr.Sub[1:]
+		}
+
+		for _, sub := range operands {
+			for _, id := range sub.Idents() {
+				if d, ok := assigned[id]; ok {
+					depend(d)
+				}
+				if s, ok := slot(id); ok {
+					if d, ok := lastWrite[s]; ok {
+						depend(d)
+					}
+					reads[s] = append(reads[s], idx)
+				}
+			}
+		}
+
+		if s, ok := slot(written); ok {
+			if d, ok := lastWrite[s]; ok {
+				depend(d)
+			}
+			for _, d := range reads[s] {
+				if d != idx {
+					depend(d)
+				}
+			}
+			lastWrite[s] = idx
+			reads[s] = nil
+		} else if written != "" {
+			assigned[written] = idx
+		}
+
+		sort.Ints(deps[idx])
+	}
+
+	return deps
+}
+
+// InPlaceSafe reports whether a schedule computes its transform correctly
+// when called with the same slices as input and output, that is whether no
+// statement reads an input element after an output sharing its memory slot
+// has been written. If not, it also returns the index of the first statement
+// reading an overwritten input.
+func InPlaceSafe(statements []Expr) (safe bool, clobbered int) {
+	written := map[memorySlot]bool{}
+	for idx, expr := range statements {
+		operands := expr.Sub
+		var lhs string
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			lhs, operands = expr.Sub[0].Ident, expr.Sub[1:]
+		}
+
+		for _, sub := range operands {
+			for _, id := range sub.Idents() {
+				slice, _, _ := splitIndex(id)
+				if s, ok := slot(id); ok && !isOutput(slice) && written[s] {
+					return false, idx
+				}
+			}
+		}
+
+		if s, ok := slot(lhs); ok {
+			written[s] = true
+		}
+	}
+
+	return true, -1
+}
+
+// Dependencies returns, for each output element the program writes, the
+// input elements it transitively depends on, ordered by slice and index.
+// Outputs not depending on an input element, such as those of pruned
+// schedules, are absent from the map.
+func (p Program) Dependencies() map[string][]string {
+	// The input elements each assigned identifier depends on.
+	sets := map[string]map[string]bool{}
+
+	deps := map[string][]string{}
+	for _, expr := range p.Statements {
+		if expr.Op != ":=" || len(expr.Sub) != 2 {
+			continue
+		}
+
+		set := map[string]bool{}
+		for _, id := range expr.Sub[1].Idents() {
+			if slice, _, ok := elementIndex(id); ok && !isOutput(slice) {
+				set[id] = true
+			}
+			for in := range sets[id] {
+				set[in] = true
+			}
+		}
+
+		lhs := expr.Sub[0].Ident
+		sets[lhs] = set
+
+		if slice, _, ok := elementIndex(lhs); !ok || !isOutput(slice) {
+			continue
+		}
+		if len(set) == 0 {
+			delete(deps, lhs)
+			continue
+		}
+
+		inputs := make([]string, 0, len(set))
+		for in := range set {
+			inputs = append(inputs, in)
+		}
+		sort.Slice(inputs, func(i, j int) bool {
+			si, ki, _ := elementIndex(inputs[i])
+			sj, kj, _ := elementIndex(inputs[j])
+			if si != sj {
+				return si < sj
+			}
+			return ki < kj
+		})
+		deps[lhs] = inputs
+	}
+
+	return deps
+}
+
+// Reorder list-schedules statements respecting their dependencies. At each
+// step pick chooses the next statement, it is given the indices of ready
+// statements in their original order and returns a position in that list.
+func Reorder(statements []Expr, pick func(ready []int) int) []Expr {
+	deps := Dependencies(statements)
+
+	// Count unscheduled dependencies and find each statement's dependents.
+	waiting := make([]int, len(statements))
+	dependents := make([][]int, len(statements))
+	for idx, d := range deps {
+		waiting[idx] = len(d)
+		for _, dep := range d {
+			dependents[dep] = append(dependents[dep], idx)
+		}
+	}
+
+	var ready []int
+	for idx := range statements {
+		if waiting[idx] == 0 {
+			ready = append(ready, idx)
+		}
+	}
+
+	ordered := make([]Expr, 0, len(statements))
+	for len(ready) > 0 {
+		pos := pick(ready)
+		next := ready[pos]
+		ready = append(ready[:pos], ready[pos+1:]...)
+		ordered = append(ordered, statements[next])
+
+		// Release statements waiting only on this one.
+		for _, d := range dependents[next] {
+			waiting[d]--
+			if waiting[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+		sort.Ints(ready)
+	}
+
+	return ordered
+}
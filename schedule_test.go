@@ -0,0 +1,174 @@
+package genfft
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDependencies(t *testing.T) {
+	prog := &Program{}
+	err := parser.ParseString("", `
+(:= T1 xi[0])
+(:= T2 xi[1])
+(:= xo[0] (+ T1 T2))
+(:= T3 xi[2])
+(:= xo[1] (+ T1 (- T2)))
+(:= xo[2] T3)
+`, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]int{
+		nil,
+		nil,
+		// Writing xo[0] must follow the read of xi[0] for in-place transforms.
+		{0, 1},
+		nil,
+		{0, 1},
+		// Writing xo[2] must follow the read of xi[2].
+		{3},
+	}
+	if got := Dependencies(prog.Statements); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestProgramDependencies(t *testing.T) {
+	prog := &Program{}
+	err := parser.ParseString("", `
+(:= T1 (+ ri[0] ri[2]))
+(:= T2 (+ ri[0] (- ri[2])))
+(:= T3 (+ ii[0] ii[2]))
+(:= T4 (* KP500000000 T1))
+(:= ro[0] (+ T4 ri[1]))
+(:= io[0] T3)
+(:= ro[1] T2)
+(:= io[1] (* KP866025403 (+ T3 T2)))
+`, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"ro[0]": {"ri[0]", "ri[1]", "ri[2]"},
+		"io[0]": {"ii[0]", "ii[2]"},
+		"ro[1]": {"ri[0]", "ri[2]"},
+		// Sets are ordered by slice, then by index.
+		"io[1]": {"ii[0]", "ii[2]", "ri[0]", "ri[2]"},
+	}
+	if got := prog.Dependencies(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestLocalityOrder(t *testing.T) {
+	prog := &Program{}
+	err := parser.ParseString("", `
+(:= T1 xi[0])
+(:= T3 xi[2])
+(:= T2 xi[1])
+(:= T4 xi[3])
+(:= xo[2] (+ T1 (- T3)))
+(:= xo[0] (+ T1 T3))
+(:= xo[3] (+ T2 (- T4)))
+(:= xo[1] (+ T2 T4))
+`, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, expr := range prog.localityOrder() {
+		got = append(got, expr.Gen().GoString())
+	}
+
+	want := []string{
+		"T1 := xi[0]",
+		"T2 := xi[1]",
+		"T3 := xi[2]",
+		"xo[2] = T1 - T3",
+		"T4 := xi[3]",
+		"xo[3] = T2 - T4",
+		"xo[1] = T2 + T4",
+		"xo[0] = T1 + T3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestLiveOrder(t *testing.T) {
+	prog := &Program{}
+	err := parser.ParseString("", `
+(:= T1 xi[0])
+(:= T2 xi[1])
+(:= T3 xi[2])
+(:= T4 xi[3])
+(:= xo[0] (* KP500000000 (+ T1 T2)))
+(:= xo[1] (+ T3 T4))
+`, prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if live := MaxLive(prog.Statements); live != 4 {
+		t.Fatalf("schedule order has %d live temporaries, want 4", live)
+	}
+
+	ordered := prog.liveOrder()
+	var got []string
+	for _, expr := range ordered {
+		got = append(got, expr.Gen().GoString())
+	}
+
+	want := []string{
+		"T1 := xi[0]",
+		"T2 := xi[1]",
+		"xo[0] = KP500000000 * (T1 + T2)",
+		"T3 := xi[2]",
+		"T4 := xi[3]",
+		"xo[1] = T3 + T4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if live := MaxLive(ordered); live != 2 {
+		t.Fatalf("reordered schedule has %d live temporaries, want 2", live)
+	}
+}
+
+func TestInPlaceSafe(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		schedule  string
+		safe      bool
+		clobbered int
+	}{
+		{"LoadsFirst", `
+(:= T1 xi[0])
+(:= T2 xi[1])
+(:= xo[0] (+ T1 T2))
+(:= xo[1] (+ T1 (- T2)))
+`, true, -1},
+		// xi[1] is read after xo[1] has been written.
+		{"ReadAfterWrite", `
+(:= T1 xi[0])
+(:= xo[1] (+ T1 (- xi[1])))
+(:= xo[0] (+ T1 xi[1]))
+`, false, 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			prog := &Program{}
+			if err := parser.ParseString("", tc.schedule, prog); err != nil {
+				t.Fatal(err)
+			}
+
+			safe, clobbered := InPlaceSafe(prog.Statements)
+			if safe != tc.safe || clobbered != tc.clobbered {
+				t.Fatalf("got (%v, %d), want (%v, %d)", safe, clobbered, tc.safe, tc.clobbered)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genScratch renders a variant of the transform keeping its temporaries in
+// a caller-provided scratch slice rather than on the stack, bounding the
+// stack usage of very large transforms. Temporaries are numbered in order of
+// assignment, so scratch needs one element per temporary.
+func (p Program) genScratch(f *jen.File, name string) error {
+	scratch := name + "Scratch"
+
+	slots := map[string]string{}
+	for _, expr := range p.Statements {
+		if temp, ok := expr.Temporary(); ok {
+			if _, seen := slots[temp]; !seen {
+				slots[temp] = fmt.Sprintf("scratch[%d]", len(slots))
+			}
+		}
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(func(id string) string {
+			if slot, ok := slots[id]; ok {
+				return slot
+			}
+			return id
+		})
+	}
+	p.Statements = statements
+
+	// Lanes would declare several temporaries at once.
+	p.Options.Lanes = 0
+
+	args, argType := p.Args()
+	extraParams, _ := p.passThrough()
+	params := append([]jen.Code{jen.List(args...).Index().Add(argType)}, extraParams...)
+	params = append(params, jen.Id("scratch").Index().Add(argType))
+
+	f.Line()
+	doc := fmt.Sprintf(
+		"%s computes %s keeping its temporaries in scratch, which must hold at\nleast %d elements and not overlap the input or output.",
+		scratch, name, len(slots),
+	)
+	return p.genFunc(f, scratch, []string{doc}, params)
+}
@@ -0,0 +1,55 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestScratch(t *testing.T) {
+	cmplx := mustGenerate(t, Dft{
+		Prefix:  copyTestdata(t, t.TempDir(), "cmplx_3"),
+		Func:    "DftCmplx3",
+		Options: Options{Scratch: true},
+	})
+	if src := cmplx.GoString(); !strings.Contains(src, "scratch[5] = scratch[0] - KP500000000*scratch[3]") {
+		t.Fatalf("temporaries aren't kept in scratch:\n%s", src)
+	}
+
+	files := map[string]*jen.File{
+		"cmplx_3.go": cmplx,
+		"float_5.go": generateNaive(t, 5, Dft{Func: "DftFloat5", Options: Options{Scratch: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestScratch(t *testing.T) {
+	xi := randCmplx(3)
+	want, got := make([]complex128, 3), make([]complex128, 3)
+	DftCmplx3(xi, want)
+	DftCmplx3Scratch(xi, got, make([]complex128, 6))
+	for k := range want {
+		if got[k] != want[k] {
+			t.Fatalf("cmplx: output %d: %v != %v", k, got[k], want[k])
+		}
+	}
+
+	ri, ii := make([]float64, 5), make([]float64, 5)
+	for k, x := range randCmplx(5) {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 5), make([]float64, 5)
+	sro, sio := make([]float64, 5), make([]float64, 5)
+	DftFloat5(ri, ii, ro, io)
+	DftFloat5Scratch(ri, ii, sro, sio, make([]float64, 64))
+	for k := range ro {
+		if sro[k] != ro[k] || sio[k] != io[k] {
+			t.Fatalf("float: output %d differs", k)
+		}
+	}
+}
+`)
+}
@@ -0,0 +1,73 @@
+package genfft
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genSignI renders the imaginary constant for a complex transform whose
+// direction is chosen at runtime. Schedules compute the forward transform with
+// I = 1i, so the inverse (sign = +1) uses I = -1i.
+func genSignI(g *jen.Group) {
+	g.Id("I").Op(":=").Complex(jen.Lit(0), jen.Op("-").Float64().Call(jen.Id("sign")))
+	g.Line()
+}
+
+// genSignSwap renders the argument swap selecting an inverse float transform
+// at runtime. Exchanging real and imaginary parts of both input and output
+// turns the forward transform into the inverse.
+func genSignSwap(g *jen.Group) {
+	g.If(jen.Id("sign").Op(">").Lit(0)).Block(
+		jen.List(jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")).Op("=").
+			List(jen.Id("ii"), jen.Id("ri"), jen.Id("io"), jen.Id("ro")),
+	)
+	g.Line()
+}
+
+// genSignMultiplier renders the multiplier selecting the direction of a
+// transform without branches. Complex schedules compute the forward transform
+// with I = 1i, so the multiplier is the imaginary constant itself, I = -sign*i.
+// Float schedules conjugate their input and output by scaling imaginary parts
+// by m = -sign, turning the forward transform into the inverse.
+func genSignMultiplier(g *jen.Group, complexArgs bool) {
+	if complexArgs {
+		g.Id("I").Op(":=").Complex(jen.Lit(0), jen.Op("-").Id("sign"))
+	} else {
+		g.Id("m").Op(":=").Op("-").Id("sign")
+	}
+	g.Line()
+}
+
+// signMultiplied returns a float statement with every imaginary input scaled
+// by the sign multiplier, and its right side too if it writes an imaginary
+// output.
+func signMultiplied(expr Expr) Expr {
+	m := Expr{Ident: "m"}
+
+	var scale func(e Expr) Expr
+	scale = func(e Expr) Expr {
+		if e.Ident != "" {
+			if name, _, ok := elementIndex(e.Ident); ok && name == "ii" {
+				return Expr{Op: "*", Sub: []Expr{m, e}}
+			}
+			return e
+		}
+
+		subs := make([]Expr, len(e.Sub))
+		for idx, sub := range e.Sub {
+			subs[idx] = scale(sub)
+		}
+		return Expr{Pos: e.Pos, Op: e.Op, Sub: subs}
+	}
+
+	if expr.Op != ":=" || len(expr.Sub) != 2 {
+		return scale(expr)
+	}
+
+	rhs := scale(expr.Sub[1])
+	if lhs := expr.Sub[0].Ident; strings.HasPrefix(lhs, "io[") {
+		rhs = Expr{Op: "*", Sub: []Expr{m, rhs}}
+	}
+	return Expr{Pos: expr.Pos, Op: ":=", Sub: []Expr{expr.Sub[0], rhs}}
+}
@@ -0,0 +1,131 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRuntimeSign(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx2"},
+		{Func: "DftCmplx8"},
+		{Func: "DftCmplx8Staged", Options: Options{Stages: 3}},
+		{Func: "DftFloat8"},
+	} {
+		n := 8
+		if dft.Func == "DftCmplx2" {
+			n = 2
+		}
+		dft.RuntimeSign = true
+
+		alst, cout := naiveSchedule(n, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func floatDft8(xi []complex128, sign int) []complex128 {
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for idx := range xi {
+		ri[idx], ii[idx] = real(xi[idx]), imag(xi[idx])
+	}
+	DftFloat8(ri, ii, ri, ii, sign)
+
+	xo := make([]complex128, 8)
+	for idx := range xo {
+		xo[idx] = complex(ri[idx], ii[idx])
+	}
+	return xo
+}
+
+func TestRuntimeSign(t *testing.T) {
+	for _, dft := range []struct {
+		name string
+		fn   func(xi []complex128, sign int) []complex128
+	}{
+		{"Cmplx", func(xi []complex128, sign int) []complex128 {
+			xo := make([]complex128, 8)
+			DftCmplx8(xi, xo, sign)
+			return xo
+		}},
+		{"Staged", func(xi []complex128, sign int) []complex128 {
+			xo := make([]complex128, 8)
+			DftCmplx8Staged(xi, xo, sign)
+			return xo
+		}},
+		{"Float", floatDft8},
+	} {
+		xi := randCmplx(8)
+
+		for _, sign := range []int{-1, 1} {
+			naive := append([]complex128(nil), xi...)
+			naiveDFT(naive, float64(sign))
+			if err := dftError(dft.fn(xi, sign), naive); err > 1e-14 {
+				t.Errorf("%s sign=%d: error %g", dft.name, sign, err)
+			}
+		}
+
+		// Forward then inverse recovers the input scaled by N.
+		roundTrip := dft.fn(dft.fn(xi, -1), 1)
+		for idx := range roundTrip {
+			roundTrip[idx] /= 8
+		}
+		if err := dftError(roundTrip, xi); err > 1e-14 {
+			t.Errorf("%s round trip: error %g", dft.name, err)
+		}
+	}
+
+	xi, xo := []complex128{1, 2}, make([]complex128, 2)
+	DftCmplx2(xi, xo, 1)
+	if xo[0] != 3 || xo[1] != -1 {
+		t.Errorf("Cmplx2: unexpected output %v", xo)
+	}
+}
+`)
+}
+
+func TestSignMultiplier(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{SignMultiplier: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{SignMultiplier: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestSignMultiplier(t *testing.T) {
+	xi := randCmplx(8)
+
+	for _, sign := range []float64{-1, 1} {
+		naive := append([]complex128(nil), xi...)
+		naiveDFT(naive, sign)
+
+		xo := make([]complex128, 8)
+		DftCmplx8(xi, xo, sign)
+		if err := dftError(xo, naive); err > 1e-13 {
+			t.Errorf("Cmplx sign=%g: error %g", sign, err)
+		}
+
+		ri, ii := make([]float64, 8), make([]float64, 8)
+		for idx, x := range xi {
+			ri[idx], ii[idx] = real(x), imag(x)
+		}
+		DftFloat8(ri, ii, ri, ii, sign)
+		for idx := range xo {
+			xo[idx] = complex(ri[idx], ii[idx])
+		}
+		if err := dftError(xo, naive); err > 1e-13 {
+			t.Errorf("Float sign=%g: error %g", sign, err)
+		}
+	}
+}
+`)
+}
@@ -0,0 +1,122 @@
+package genfft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// WithinSize returns the transforms whose length doesn't exceed maxSize,
+// warning about each one skipped. Transforms whose schedules can't be parsed
+// are kept, so generating them reports why.
+func WithinSize(dfts []Dft, maxSize int) (kept []Dft) {
+	for _, dft := range dfts {
+		prog, err := dft.Parse()
+		if err == nil && prog.TransformLength() > maxSize {
+			log.Warnf("skipping %s: length %d exceeds max size %d\n", dft.Func, prog.TransformLength(), maxSize)
+			continue
+		}
+		kept = append(kept, dft)
+	}
+
+	return kept
+}
+
+// sizeRe matches the size argument in the command line FFTW's generator
+// records in the header of its C output.
+var sizeRe = regexp.MustCompile(`Generated by: .*\s-n (\d+)\b`)
+
+// ParseSize returns the transform size C output was generated for, if its
+// header records one.
+func ParseSize(r io.Reader) (n int, ok bool) {
+	coutScanner := bufio.NewScanner(r)
+	for coutScanner.Scan() {
+		m := sizeRe.FindStringSubmatch(coutScanner.Text())
+		if m == nil {
+			continue
+		}
+
+		size, err := strconv.Atoi(m[1])
+		return size, err == nil
+	}
+
+	return 0, false
+}
+
+// CheckSize returns an error if the C output records a size other than the
+// length of the program's schedule.
+func (p Program) CheckSize(cout io.Reader) error {
+	n, ok := ParseSize(cout)
+	if !ok {
+		return nil
+	}
+
+	if length := p.TransformLength(); n != length {
+		return fmt.Errorf("constants are for size %d, but the schedule has length %d", n, length)
+	}
+
+	return nil
+}
+
+// genAssertSize renders a constant holding the transform's length, and
+// assertions that fail to compile unless it equals the configured size: an
+// array type's length can't be negative, so one of the two differences is
+// an error unless they're both zero.
+func (p Program) genAssertSize(f *jen.File, name string) {
+	size := jen.Id(name + "Size")
+	expected := jen.Lit(p.Options.AssertSize)
+
+	f.Line()
+	f.Comment(fmt.Sprintf("%sSize is the length of %s.", name, name))
+	f.Const().Add(size).Op("=").Lit(p.TransformLength())
+	f.Line()
+
+	f.Comment(fmt.Sprintf("Fail to compile unless %s has length %d.", name, p.Options.AssertSize))
+	f.Var().Defs(
+		jen.Id("_").Index(size.Clone().Op("-").Add(expected)).Int(),
+		jen.Id("_").Index(expected.Clone().Op("-").Add(size.Clone())).Int(),
+	)
+}
+
+// genLenGuard renders statements failing if a slice is too short for the
+// highest element the schedule accesses in it, naming the function and the
+// length needed rather than failing with an index out of range mid-transform.
+// Checking upfront also lets the compiler drop the bounds check of every
+// access. Strided and offset indices aren't known until runtime, so slices
+// indexed by them are left unchecked.
+func (p Program) genLenGuard(g *jen.Group, name string) {
+	need := map[string]int{}
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			if slice, k, ok := splitIndex(id); ok && k+1 > need[slice] {
+				need[slice] = k + 1
+			}
+		}
+	}
+
+	slices := []string{"xi", "xo"}
+	if p.Float() {
+		slices = []string{"ri", "ii", "ro", "io"}
+	}
+	slices = append(slices, "scratch")
+
+	guarded := false
+	for _, slice := range slices {
+		n, ok := need[slice]
+		if !ok {
+			continue
+		}
+		g.If(jen.Len(jen.Id(slice)).Op("<").Lit(n)).Block(
+			p.genFailure(fmt.Sprintf("dft: %s: %s has length %%d, need %d", name, slice, n), jen.Len(jen.Id(slice))),
+		)
+		guarded = true
+	}
+	if guarded {
+		g.Line()
+	}
+}
@@ -0,0 +1,150 @@
+package genfft
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestWithinSize(t *testing.T) {
+	dir := t.TempDir()
+
+	var dfts []Dft
+	for _, tc := range []struct {
+		n   int
+		dft Dft
+	}{
+		{4, Dft{Func: "DftCmplx4"}},
+		{64, Dft{Func: "DftCmplx64"}},
+		{8, Dft{Func: "DftFloat8"}},
+	} {
+		alst, cout := naiveSchedule(tc.n, tc.dft.Func[3] == 'F')
+		tc.dft.Prefix = writeSchedule(t, dir, tc.dft.Func, alst, cout)
+		dfts = append(dfts, tc.dft)
+	}
+
+	kept := WithinSize(dfts, 16)
+	if len(kept) != 2 || kept[0].Func != "DftCmplx4" || kept[1].Func != "DftFloat8" {
+		t.Fatalf("expected only DftCmplx4 and DftFloat8 kept, got %+v", kept)
+	}
+
+	if kept := WithinSize(dfts, 64); len(kept) != 3 {
+		t.Fatalf("transform at the limit was skipped, kept %+v", kept)
+	}
+}
+
+func TestCheckSize(t *testing.T) {
+	cout, err := os.ReadFile("testdata/cmplx_3.cout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, ok := ParseSize(strings.NewReader(string(cout))); !ok || n != 3 {
+		t.Fatalf("parsed size %d, %v from header, want 3", n, ok)
+	}
+
+	matched := mustParse(t, Dft{Prefix: copyTestdata(t, t.TempDir(), "cmplx_3")})
+	if err := matched.CheckSize(strings.NewReader(string(cout))); err != nil {
+		t.Fatalf("matching pair reported: %v", err)
+	}
+
+	// Pair a size 4 schedule with the size 3 constants.
+	alst, _ := naiveSchedule(4, false)
+	mismatched := &Program{}
+	if err := parser.ParseString("", alst, mismatched); err != nil {
+		t.Fatal(err)
+	}
+
+	err = mismatched.CheckSize(strings.NewReader(string(cout)))
+	if err == nil || !strings.Contains(err.Error(), "constants are for size 3, but the schedule has length 4") {
+		t.Fatalf("mismatched pair returned %v", err)
+	}
+
+	// Naive schedules' constants have no header to check against.
+	if err := mismatched.CheckSize(strings.NewReader("DVK(KP500000000, +0.5);\n")); err != nil {
+		t.Fatalf("constants without a size reported: %v", err)
+	}
+}
+
+func TestAssertSize(t *testing.T) {
+	t.Run("Match", func(t *testing.T) {
+		files := map[string]*jen.File{
+			"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{AssertSize: 8}}),
+		}
+		goTest(t, files, `package dft
+
+import "testing"
+
+func TestAssertSize(t *testing.T) {
+	if DftCmplx8Size != 8 {
+		t.Fatalf("DftCmplx8Size is %d", DftCmplx8Size)
+	}
+}
+`)
+	})
+
+	for _, expected := range []int{7, 9} {
+		dir := t.TempDir()
+		f := generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{AssertSize: expected}})
+		if err := f.Save(filepath.Join(dir, "cmplx_8.go")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "go.mod"), scratchMod(t), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cmd := exec.Command("go", "build", ".")
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err == nil {
+			t.Fatalf("size 8 asserted as %d compiled", expected)
+		} else if !strings.Contains(string(out), "invalid array length") {
+			t.Fatalf("size 8 asserted as %d: unexpected error:\n%s", expected, out)
+		}
+	}
+}
+
+func TestLenGuard(t *testing.T) {
+	if src := generateNaive(t, 4, Dft{Func: "DftCmplx4", Options: Options{NoLenGuard: true}}).GoString(); strings.Contains(src, "panic(") {
+		t.Fatalf("noLenGuard transform still guards its slices:\n%s", src)
+	}
+
+	files := map[string]*jen.File{
+		"cmplx_4.go": generateNaive(t, 4, Dft{Func: "DftCmplx4"}),
+		"float_4.go": generateNaive(t, 4, Dft{Func: "DftFloat4"}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func lenPanic(fn func()) (msg interface{}) {
+	defer func() {
+		msg = recover()
+	}()
+	fn()
+	return nil
+}
+
+func TestLenGuard(t *testing.T) {
+	want := "dft: DftCmplx4: xi has length 3, need 4"
+	if msg := lenPanic(func() { DftCmplx4(make([]complex128, 3), make([]complex128, 4)) }); msg != want {
+		t.Fatalf("short input: got %v, want %q", msg, want)
+	}
+
+	want = "dft: DftFloat4: io has length 2, need 4"
+	f := make([]float64, 4)
+	if msg := lenPanic(func() { DftFloat4(f, f, make([]float64, 4), f[:2]) }); msg != want {
+		t.Fatalf("short output: got %v, want %q", msg, want)
+	}
+
+	// Longer slices are fine, only the first elements are used.
+	if msg := lenPanic(func() { DftCmplx4(make([]complex128, 8), make([]complex128, 8)) }); msg != nil {
+		t.Fatalf("long slices panicked: %v", msg)
+	}
+}
+`)
+}
@@ -0,0 +1,43 @@
+package genfft
+
+import (
+	"fmt"
+)
+
+// splitOrder reorders a float program's statements into two passes: first
+// every statement the real outputs depend on, then the rest, which compute
+// the imaginary outputs. Keeping each pass's dependency chains together
+// gives the processor more independent work between them than interleaving
+// the parts does.
+func (p Program) splitOrder(name string) ([]Expr, error) {
+	if !p.Float() {
+		return nil, fmt.Errorf("%s: split passes require a float schedule", name)
+	}
+
+	// Mark statements writing real outputs, then everything they depend on.
+	deps := Dependencies(p.Statements)
+	realPass := make([]bool, len(p.Statements))
+	for idx := len(p.Statements) - 1; idx >= 0; idx-- {
+		expr := p.Statements[idx]
+		if expr.Op == ":=" && len(expr.Sub) == 2 {
+			if out, _, ok := elementIndex(expr.Sub[0].Ident); ok && out == "ro" {
+				realPass[idx] = true
+			}
+		}
+
+		if realPass[idx] {
+			for _, d := range deps[idx] {
+				realPass[d] = true
+			}
+		}
+	}
+
+	return Reorder(p.Statements, func(ready []int) int {
+		for pos, idx := range ready {
+			if realPass[idx] {
+				return pos
+			}
+		}
+		return 0
+	}), nil
+}
@@ -0,0 +1,86 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestSplitOrder(t *testing.T) {
+	alst, cout := naiveSchedule(8, true)
+	prog := mustParse(t, Dft{Prefix: writeSchedule(t, t.TempDir(), "float_8", alst, cout)})
+
+	statements, err := prog.splitOrder("DftFloat8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every real output must be written before any imaginary output.
+	seenImag := false
+	for _, expr := range statements {
+		name, _, ok := elementIndex(expr.Sub[0].Ident)
+		switch {
+		case !ok:
+		case name == "io":
+			seenImag = true
+		case name == "ro" && seenImag:
+			t.Fatalf("real output %s written after an imaginary output", expr.Sub[0].Ident)
+		}
+	}
+}
+
+func TestSplitPasses(t *testing.T) {
+	files := map[string]*jen.File{
+		"float_16.go":       generateNaive(t, 16, Dft{Func: "DftFloat16"}),
+		"float_16_split.go": generateNaive(t, 16, Dft{Func: "DftFloat16Split", Options: Options{SplitPasses: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestSplitPasses(t *testing.T) {
+	xi := randCmplx(16)
+	want := append([]complex128(nil), xi...)
+	naiveDFT(want, -1.0)
+
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	for idx, x := range xi {
+		ri[idx], ii[idx] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 16), make([]float64, 16)
+	DftFloat16Split(ri, ii, ro, io)
+
+	got := make([]complex128, 16)
+	for idx := range got {
+		got[idx] = complex(ro[idx], io[idx])
+	}
+	if err := dftError(got, want); err > 1e-13 {
+		t.Fatalf("error %g", err)
+	}
+
+	// Reordering must keep in-place transforms correct.
+	DftFloat16Split(ri, ii, ri, ii)
+	for idx := range ro {
+		if ri[idx] != ro[idx] || ii[idx] != io[idx] {
+			t.Fatalf("in-place output differs at %d", idx)
+		}
+	}
+}
+
+func BenchmarkSplitPasses(b *testing.B) {
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	ro, io := make([]float64, 16), make([]float64, 16)
+	b.Run("Interleaved", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftFloat16(ri, ii, ro, io)
+		}
+	})
+	b.Run("Split", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			DftFloat16Split(ri, ii, ro, io)
+		}
+	})
+}
+`, "-bench", ".", "-benchtime", "10x")
+}
@@ -0,0 +1,202 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Idents walks an expression tree and returns every identifier in order of appearance.
+func (e Expr) Idents() (i []string) {
+	if e.Ident != "" {
+		return []string{e.Ident}
+	}
+
+	for _, sub := range e.Sub {
+		i = append(i, sub.Idents()...)
+	}
+
+	return
+}
+
+// Temporary returns the name of the temporary an expression assigns to, if any.
+func (e Expr) Temporary() (string, bool) {
+	if e.Op != ":=" || len(e.Sub) == 0 {
+		return "", false
+	}
+
+	lhs := e.Sub[0].Ident
+	if lhs == "" || strings.HasSuffix(lhs, "]") {
+		return "", false
+	}
+
+	return lhs, true
+}
+
+// uses reports whether any of the statements reference an identifier.
+func uses(statements []Expr, ident string) bool {
+	for _, expr := range statements {
+		for _, id := range expr.Idents() {
+			if id == ident {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// unexport lower-cases the first letter of an identifier.
+func unexport(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// genStages renders the program as a function calling a series of helpers,
+// each evaluating a contiguous run of statements. Temporaries used by a later
+// stage than the one that assigns them are passed through a struct.
+func (p Program) genStages(f *jen.File, name string, args []jen.Code, argType jen.Code, params []jen.Code) {
+	tempsType := unexport(name) + "Temps"
+	stageName := func(s int) string {
+		return fmt.Sprintf("%sStage%d", unexport(name), s)
+	}
+
+	// Partition statements into roughly equal stages.
+	size := (len(p.Statements) + p.Options.Stages - 1) / p.Options.Stages
+	var stages [][]Expr
+	for start := 0; start < len(p.Statements); start += size {
+		end := start + size
+		if end > len(p.Statements) {
+			end = len(p.Statements)
+		}
+		stages = append(stages, p.Statements[start:end])
+	}
+
+	// Find the stage each temporary is assigned in.
+	assigned := map[string]int{}
+	for s, stage := range stages {
+		for _, expr := range stage {
+			if temp, ok := expr.Temporary(); ok {
+				assigned[temp] = s
+			}
+		}
+	}
+
+	// Find temporaries each stage loads from and stores to the shared struct.
+	var shared []string
+	isShared := map[string]bool{}
+	loads := make([][]string, len(stages))
+	stores := make([][]string, len(stages))
+	for s, stage := range stages {
+		loaded := map[string]bool{}
+		for _, expr := range stage {
+			for _, id := range expr.Idents() {
+				a, ok := assigned[id]
+				if !ok || a >= s || loaded[id] {
+					continue
+				}
+				loaded[id] = true
+				loads[s] = append(loads[s], id)
+
+				if !isShared[id] {
+					isShared[id] = true
+					shared = append(shared, id)
+					stores[a] = append(stores[a], id)
+				}
+			}
+		}
+	}
+
+	// Complex stages each select the imaginary constant's sign, float
+	// transforms select it once by swapping arguments before any stage.
+	stageArgs := args
+	stageParams := []jen.Code{params[0]}
+	if strides := p.Strides(); len(strides) > 0 {
+		for _, stride := range strides {
+			stageArgs = append(stageArgs, jen.Id(stride))
+		}
+		stageParams = append(stageParams, strideParams(strides))
+	}
+	if p.Options.OutputBase {
+		stageArgs = append(stageArgs, jen.Id("obase"))
+		stageParams = append(stageParams, jen.Id("obase").Int())
+	}
+	if p.Options.RuntimeSign && !p.Float() {
+		stageArgs = append(stageArgs, jen.Id("sign"))
+		stageParams = append(stageParams, jen.Id("sign").Int())
+	}
+
+	if p.Options.Expvar {
+		genMetricsVar(f, name)
+	}
+
+	// Define the struct of shared temporaries.
+	f.Type().Id(tempsType).StructFunc(func(g *jen.Group) {
+		if len(shared) > 0 {
+			g.Id(strings.Join(shared, ", ")).Add(argType)
+		}
+	})
+	f.Line()
+
+	for _, line := range p.funcDoc(name) {
+		f.Comment(line)
+	}
+
+	// Define the named function, which calls each stage in order.
+	f.Func().Id(name).Params(params...).BlockFunc(func(g *jen.Group) {
+		if p.Options.Expvar {
+			genMetrics(g, name)
+		}
+
+		if p.Options.DebugTiming {
+			genTiming(g, name)
+		}
+
+		if p.Options.NoAlias {
+			genAssertDisjoint(g, !p.Float())
+		}
+
+		if !p.Options.NoLenGuard {
+			p.genLenGuard(g, name)
+		}
+
+		if p.Options.RuntimeSign && p.Float() {
+			genSignSwap(g)
+		}
+
+		g.Var().Id("tmp").Id(tempsType)
+		for s := range stages {
+			g.Id(stageName(s)).Call(append(stageArgs, jen.Op("&").Id("tmp"))...)
+		}
+	})
+	f.Line()
+
+	// Define each stage.
+	for s, stage := range stages {
+		f.Func().Id(stageName(s)).Params(
+			append(stageParams, jen.Id("tmp").Op("*").Id(tempsType))...,
+		).BlockFunc(func(g *jen.Group) {
+			p.genConstants(g)
+
+			if p.Options.RuntimeSign && uses(stage, "I") {
+				genSignI(g)
+			}
+
+			for _, id := range loads[s] {
+				g.Id(id).Op(":=").Id("tmp").Dot(id)
+			}
+
+			for _, expr := range stage {
+				g.Add(p.genStatement(expr))
+			}
+
+			for _, id := range stores[s] {
+				g.Id("tmp").Dot(id).Op("=").Id(id)
+			}
+		})
+		f.Line()
+	}
+}
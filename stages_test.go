@@ -0,0 +1,82 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestStages(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]*jen.File{}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx16"},
+		{Func: "DftCmplx16Staged", Options: Options{Stages: 4}},
+		{Func: "DftFloat16"},
+		{Func: "DftFloat16Staged", Options: Options{Stages: 3}},
+	} {
+		alst, cout := naiveSchedule(16, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+		files[dft.Func+".go"] = mustGenerate(t, dft)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestStages(t *testing.T) {
+	xi := randCmplx(16)
+	mono := make([]complex128, 16)
+	staged := make([]complex128, 16)
+	DftCmplx16(xi, mono)
+	DftCmplx16Staged(xi, staged)
+
+	for idx := range mono {
+		if mono[idx] != staged[idx] {
+			t.Fatalf("cmplx: staged output differs at %d: %v != %v", idx, staged[idx], mono[idx])
+		}
+	}
+
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+	if err := dftError(mono, naive); err > 1e-13 {
+		t.Fatalf("cmplx: error %g", err)
+	}
+
+	ri, ii := make([]float64, 16), make([]float64, 16)
+	for idx := range xi {
+		ri[idx], ii[idx] = real(xi[idx]), imag(xi[idx])
+	}
+	monoR, monoI := make([]float64, 16), make([]float64, 16)
+	stagedR, stagedI := make([]float64, 16), make([]float64, 16)
+	DftFloat16(ri, ii, monoR, monoI)
+	DftFloat16Staged(ri, ii, stagedR, stagedI)
+
+	for idx := range monoR {
+		if monoR[idx] != stagedR[idx] || monoI[idx] != stagedI[idx] {
+			t.Fatalf("float: staged output differs at %d", idx)
+		}
+	}
+
+	floatOut := make([]complex128, 16)
+	for idx := range floatOut {
+		floatOut[idx] = complex(monoR[idx], monoI[idx])
+	}
+	if err := dftError(floatOut, naive); err > 1e-13 {
+		t.Fatalf("float: error %g", err)
+	}
+
+	// The entry function checks its slices' lengths before any stage runs.
+	want := "dft: DftCmplx16Staged: xo has length 15, need 16"
+	func() {
+		defer func() {
+			if msg := recover(); msg != want {
+				t.Fatalf("short output: got panic %v, want %q", msg, want)
+			}
+		}()
+		DftCmplx16Staged(xi, staged[:15])
+	}()
+}
+`)
+}
@@ -0,0 +1,80 @@
+package genfft
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Stats describes the shape of a schedule's expression trees, which explains
+// differences in compile time and accuracy between transforms.
+type Stats struct {
+	// Statements is the number of statements.
+	Statements int
+	// Nodes is the total number of operators and identifiers.
+	Nodes int
+	// Depth is the largest number of nodes on a path from a statement's root
+	// to an identifier.
+	Depth int
+	// Arity is the largest number of operands of any operator.
+	Arity int
+}
+
+// ScheduleStats measures the expression trees of a schedule's statements.
+func ScheduleStats(statements []Expr) (s Stats) {
+	var walk func(e Expr, depth int)
+	walk = func(e Expr, depth int) {
+		s.Nodes++
+		if depth > s.Depth {
+			s.Depth = depth
+		}
+		if len(e.Sub) > s.Arity {
+			s.Arity = len(e.Sub)
+		}
+
+		for _, sub := range e.Sub {
+			walk(sub, depth+1)
+		}
+	}
+
+	for _, expr := range statements {
+		s.Statements++
+		walk(expr, 1)
+	}
+
+	return s
+}
+
+// Fprint writes the statistics to w, one per line.
+func (s Stats) Fprint(w io.Writer) {
+	fmt.Fprintf(w, "statements: %d\n", s.Statements)
+	fmt.Fprintf(w, "nodes: %d\n", s.Nodes)
+	fmt.Fprintf(w, "depth: %d\n", s.Depth)
+	fmt.Fprintf(w, "arity: %d\n", s.Arity)
+}
+
+// scheduleStats parses a schedule file and measures its statements.
+func scheduleStats(alstFilename string) (Stats, error) {
+	statements, err := ParseStatements(alstFilename)
+	if err != nil {
+		return Stats{}, err
+	}
+	return ScheduleStats(statements), nil
+}
+
+// ParseStatements parses the statements of a schedule file.
+func ParseStatements(alstFilename string) ([]Expr, error) {
+	alst, err := os.ReadFile(alstFilename)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	prog := &Program{}
+	err = parser.Parse(alstFilename, bytes.NewReader(stripConstants(alst)), prog)
+	if err != nil {
+		return nil, fmt.Errorf("parser.Parse: %w", err)
+	}
+
+	return prog.Statements, nil
+}
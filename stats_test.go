@@ -0,0 +1,33 @@
+package genfft
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScheduleStats(t *testing.T) {
+	stats, err := scheduleStats("testdata/cmplx_3.alst")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The deepest statement is (:= T6 (* I (* KP866025403 (+ T3 (- T2))))).
+	want := Stats{Statements: 9, Nodes: 48, Depth: 6, Arity: 2}
+	if stats != want {
+		t.Fatalf("got %+v, want %+v", stats, want)
+	}
+
+	var b strings.Builder
+	stats.Fprint(&b)
+	if got, want := b.String(), "statements: 9\nnodes: 48\ndepth: 6\narity: 2\n"; got != want {
+		t.Fatalf("printed %q, want %q", got, want)
+	}
+
+	prog := &Program{}
+	if err := parser.ParseString("", "(:= xo[0] (+ xi[0] xi[1] xi[2]))", prog); err != nil {
+		t.Fatal(err)
+	}
+	if arity := ScheduleStats(prog.Statements).Arity; arity != 3 {
+		t.Fatalf("arity %d, want 3", arity)
+	}
+}
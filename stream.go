@@ -0,0 +1,64 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genStream renders a wrapper reading a transform's input from an io.Reader
+// and writing its output to an io.Writer. Samples are encoded as pairs of
+// little-endian float64 real and imaginary parts.
+func (p Program) genStream(f *jen.File, name string) {
+	n := p.TransformLength()
+	stream := name + "Stream"
+	extraParams, extraArgs := p.passThrough()
+
+	params := append([]jen.Code{
+		jen.Id("r").Qual("io", "Reader"),
+		jen.Id("w").Qual("io", "Writer"),
+	}, extraParams...)
+
+	le := jen.Qual("encoding/binary", "LittleEndian")
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s reads %d samples from r, computes %s and writes the result to w.\nSamples are pairs of little-endian float64 real and imaginary parts.",
+		stream, n, name,
+	))
+	f.Func().Id(stream).Params(params...).Error().BlockFunc(func(g *jen.Group) {
+		g.Var().Id("x").Index(jen.Lit(n)).Complex128()
+		g.If(
+			jen.Err().Op(":=").Qual("encoding/binary", "Read").Call(jen.Id("r"), le, jen.Op("&").Id("x")),
+			jen.Err().Op("!=").Nil(),
+		).Block(
+			jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit(fmt.Sprintf("dft: %s: %%w", stream)), jen.Err())),
+		)
+		g.Line()
+
+		if p.Float() {
+			// Split samples into real and imaginary parts and back again.
+			g.Var().List(jen.Id("re"), jen.Id("im")).Index(jen.Lit(n)).Float64()
+			g.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Id("x")).Block(
+				jen.List(jen.Id("re").Index(jen.Id("k")), jen.Id("im").Index(jen.Id("k"))).Op("=").List(jen.Real(jen.Id("v")), jen.Imag(jen.Id("v"))),
+			)
+			re, im := jen.Id("re").Index(jen.Empty(), jen.Empty()), jen.Id("im").Index(jen.Empty(), jen.Empty())
+			g.Add(p.callTransform(name, nil, append([]jen.Code{re, im, re.Clone(), im.Clone()}, extraArgs...)...))
+			g.For(jen.Id("k").Op(":=").Range().Id("x")).Block(
+				jen.Id("x").Index(jen.Id("k")).Op("=").Complex(jen.Id("re").Index(jen.Id("k")), jen.Id("im").Index(jen.Id("k"))),
+			)
+		} else {
+			x := jen.Id("x").Index(jen.Empty(), jen.Empty())
+			g.Add(p.callTransform(name, nil, append([]jen.Code{x, x.Clone()}, extraArgs...)...))
+		}
+		g.Line()
+
+		g.If(
+			jen.Err().Op(":=").Qual("encoding/binary", "Write").Call(jen.Id("w"), le, jen.Op("&").Id("x")),
+			jen.Err().Op("!=").Nil(),
+		).Block(
+			jen.Return(jen.Qual("fmt", "Errorf").Call(jen.Lit(fmt.Sprintf("dft: %s: %%w", stream)), jen.Err())),
+		)
+		g.Return(jen.Nil())
+	})
+}
@@ -0,0 +1,62 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestStream(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Stream: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Stream: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	xi := randCmplx(8)
+	xo := make([]complex128, 8)
+	DftCmplx8(xi, xo)
+
+	var in bytes.Buffer
+	for _, x := range xi {
+		binary.Write(&in, binary.LittleEndian, []float64{real(x), imag(x)})
+	}
+	encoded := in.Bytes()
+
+	for name, stream := range map[string]func(io.Reader, io.Writer) error{
+		"DftCmplx8Stream": DftCmplx8Stream,
+		"DftFloat8Stream": DftFloat8Stream,
+	} {
+		var out bytes.Buffer
+		if err := stream(bytes.NewReader(encoded), &out); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		decoded := make([]float64, 16)
+		if err := binary.Read(&out, binary.LittleEndian, decoded); err != nil {
+			t.Fatalf("%s: decoding output: %v", name, err)
+		}
+		for k := range xo {
+			if got := complex(decoded[2*k], decoded[2*k+1]); got != xo[k] {
+				t.Fatalf("%s: output %d is %v, want %v", name, k, got, xo[k])
+			}
+		}
+
+		err := stream(bytes.NewReader(encoded[:100]), &out)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("%s: short input returned %v", name, err)
+		}
+	}
+}
+`)
+}
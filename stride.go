@@ -0,0 +1,158 @@
+package genfft
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// strideRe matches identifiers indexed by an FFTW stride factor, such as
+// "xi[WS(is, 2)]" from codelets generated with -with-istride or
+// -with-ostride.
+var strideRe = regexp.MustCompile(`^(\w+)\[WS\(\s*(\w+)\s*,\s*(\d+)\s*\)\]$`)
+
+// stridedIndex splits an identifier indexed by a stride factor into its name,
+// stride and multiple of the stride.
+func stridedIndex(id string) (name, stride string, k int, ok bool) {
+	m := strideRe.FindStringSubmatch(id)
+	if m == nil {
+		return "", "", 0, false
+	}
+
+	k, err := strconv.Atoi(m[3])
+	if err != nil {
+		return "", "", 0, false
+	}
+
+	return m[1], m[2], k, true
+}
+
+// elementIndex splits an identifier indexed either literally or by a stride
+// factor into its name and logical element index.
+func elementIndex(id string) (name string, k int, ok bool) {
+	if name, k, ok = splitIndex(id); ok {
+		return name, k, true
+	}
+
+	name, _, k, ok = stridedIndex(id)
+	return name, k, ok
+}
+
+// genIdent renders an identifier, multiplying out stride factors in its
+// index.
+func genIdent(id string) *jen.Statement {
+	name, stride, k, ok := stridedIndex(id)
+	if !ok {
+		return jen.Id(id)
+	}
+
+	switch k {
+	case 0:
+		return jen.Id(name).Index(jen.Lit(0))
+	case 1:
+		return jen.Id(name).Index(jen.Id(stride))
+	}
+	return jen.Id(name).Index(jen.Lit(k).Op("*").Id(stride))
+}
+
+// stridedStatements returns the statements of a unit-stride schedule with
+// inputs indexed by multiples of is and outputs by multiples of os, the
+// stride factors FFTW's stride codelets use.
+func (p Program) stridedStatements(name string) ([]Expr, error) {
+	if len(p.Strides()) > 0 {
+		return nil, fmt.Errorf("%s: schedule is already strided", name)
+	}
+	if p.Options.OutputBase || p.Options.BitReverse {
+		return nil, fmt.Errorf("%s: strided transforms can't offset or bit-reverse their outputs", name)
+	}
+
+	stride := func(id string) string {
+		slice, k, ok := splitIndex(id)
+		if !ok {
+			return id
+		}
+		if isOutput(slice) {
+			return fmt.Sprintf("%s[WS(os, %d)]", slice, k)
+		}
+		return fmt.Sprintf("%s[WS(is, %d)]", slice, k)
+	}
+
+	statements := make([]Expr, len(p.Statements))
+	for idx, expr := range p.Statements {
+		statements[idx] = expr.MapIdents(stride)
+	}
+
+	return statements, nil
+}
+
+// Strides returns the stride factors a schedule indexes its slices by, in
+// order of first appearance.
+func (p Program) Strides() (strides []string) {
+	seen := map[string]bool{}
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			_, stride, _, ok := stridedIndex(id)
+			if ok && !seen[stride] {
+				seen[stride] = true
+				strides = append(strides, stride)
+			}
+		}
+	}
+
+	return strides
+}
+
+// genStrideCheck renders statements failing if any strided slice is too
+// short for the highest element the schedule accesses in it, so undersized
+// slices fail with a clear message rather than an index out of range.
+func (p Program) genStrideCheck(g *jen.Group, name string) error {
+	type access struct {
+		slice, stride string
+	}
+
+	// Find the highest multiple of each stride used with each slice.
+	var accesses []access
+	highest := map[access]int{}
+	for _, expr := range p.Statements {
+		for _, id := range expr.Idents() {
+			slice, stride, k, ok := stridedIndex(id)
+			if !ok {
+				continue
+			}
+			a := access{slice, stride}
+			if last, seen := highest[a]; !seen {
+				accesses = append(accesses, a)
+			} else if last >= k {
+				continue
+			}
+			highest[a] = k
+		}
+	}
+	if len(accesses) == 0 {
+		return fmt.Errorf("%s: stride checks require a strided schedule", name)
+	}
+
+	for _, a := range accesses {
+		need := jen.Lit(highest[a]).Op("*").Id(a.stride).Op("+").Lit(1)
+		g.If(jen.Len(jen.Id(a.slice)).Op("<").Add(need.Clone())).Block(
+			p.genFailure(
+				fmt.Sprintf("dft: %s: %s has length %%d, need %%d for stride %%d", name, a.slice),
+				jen.Len(jen.Id(a.slice)), need, jen.Id(a.stride),
+			),
+		)
+	}
+	g.Line()
+
+	return nil
+}
+
+// strideParams returns the parameter declaring a schedule's strides.
+func strideParams(strides []string) jen.Code {
+	ids := make([]jen.Code, len(strides))
+	for idx, stride := range strides {
+		ids[idx] = jen.Id(stride)
+	}
+	return jen.List(ids...).Int()
+}
@@ -0,0 +1,149 @@
+package genfft
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestStrided(t *testing.T) {
+	// Index a naive schedule by strides as FFTW's stride codelets do.
+	alst, cout := naiveSchedule(8, false)
+	alst = regexp.MustCompile(`xi\[(\d+)\]`).ReplaceAllString(alst, "xi[WS(is, $1)]")
+	alst = regexp.MustCompile(`xo\[(\d+)\]`).ReplaceAllString(alst, "xo[WS(os, $1)]")
+
+	dft := Dft{Prefix: writeSchedule(t, t.TempDir(), "DftCmplx8", alst, cout), Func: "DftCmplx8", Options: Options{Into: true}}
+	files := map[string]*jen.File{"cmplx_8.go": mustGenerate(t, dft)}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestStrided(t *testing.T) {
+	xi := randCmplx(8)
+	naive := append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+
+	// Read every second element and write every third.
+	in := make([]complex128, 16)
+	for k, x := range xi {
+		in[2*k] = x
+	}
+	out := make([]complex128, 24)
+	DftCmplx8(in, out, 2, 3)
+
+	for k := range naive {
+		if err := dftError(out[3*k:3*k+1], naive[k:k+1]); err > 1e-13 {
+			t.Fatalf("output %d error %g", k, err)
+		}
+	}
+
+	contiguous := DftCmplx8Into(xi, make([]complex128, 8), 1, 1)
+	if err := dftError(contiguous, naive); err > 1e-13 {
+		t.Fatalf("unit stride error %g", err)
+	}
+}
+`)
+}
+
+func TestStridedOption(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Strided: true, StrideCheck: true}}),
+		"float_6.go": generateNaive(t, 6, Dft{Func: "DftFloat6", Options: Options{Strided: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestStridedOption(t *testing.T) {
+	// Transform the second column of an 8x3 row-major matrix into the first
+	// row of a 2x8 column-major one.
+	matrix := randCmplx(24)
+	column := make([]complex128, 8)
+	for k := range column {
+		column[k] = matrix[3*k+1]
+	}
+	naive := append([]complex128(nil), column...)
+	naiveDFT(naive, -1.0)
+
+	out := make([]complex128, 16)
+	DftCmplx8(matrix[1:], out, 3, 2)
+	for k := range naive {
+		if err := dftError(out[2*k:2*k+1], naive[k:k+1]); err > 1e-13 {
+			t.Fatalf("Cmplx output %d error %g", k, err)
+		}
+	}
+
+	// Strides of one are the contiguous transform.
+	xi := randCmplx(6)
+	naive = append([]complex128(nil), xi...)
+	naiveDFT(naive, -1.0)
+
+	ri, ii := make([]float64, 6), make([]float64, 6)
+	for k, x := range xi {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+	ro, io := make([]float64, 6), make([]float64, 6)
+	DftFloat6(ri, ii, ro, io, 1, 1)
+	for k := range naive {
+		if err := dftError([]complex128{complex(ro[k], io[k])}, naive[k:k+1]); err > 1e-13 {
+			t.Fatalf("Float output %d error %g", k, err)
+		}
+	}
+}
+`)
+
+	// Schedules already indexed by strides can't be strided again.
+	alst, cout := naiveSchedule(4, false)
+	alst = regexp.MustCompile(`xi\[(\d+)\]`).ReplaceAllString(alst, "xi[WS(is, $1)]")
+	dft := Dft{Prefix: writeSchedule(t, t.TempDir(), "DftCmplx4", alst, cout), Func: "DftCmplx4", Options: Options{Strided: true}}
+	prog, err := dft.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prog.Render("dft", dft.Func); err == nil || !strings.Contains(err.Error(), "already strided") {
+		t.Fatalf("expected an error for an already strided schedule, got %v", err)
+	}
+}
+
+func TestStrideCheck(t *testing.T) {
+	alst, cout := naiveSchedule(8, false)
+	alst = regexp.MustCompile(`xi\[(\d+)\]`).ReplaceAllString(alst, "xi[WS(is, $1)]")
+	alst = regexp.MustCompile(`xo\[(\d+)\]`).ReplaceAllString(alst, "xo[WS(os, $1)]")
+
+	dft := Dft{Prefix: writeSchedule(t, t.TempDir(), "DftCmplx8", alst, cout), Func: "DftCmplx8", Options: Options{StrideCheck: true}}
+	files := map[string]*jen.File{"cmplx_8.go": mustGenerate(t, dft)}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func stridePanic(xi, xo []complex128, is, os int) (msg interface{}) {
+	defer func() {
+		msg = recover()
+	}()
+	DftCmplx8(xi, xo, is, os)
+	return nil
+}
+
+func TestStrideCheck(t *testing.T) {
+	// Seven strides of two past the first element need 15 elements.
+	if msg := stridePanic(make([]complex128, 15), make([]complex128, 8), 2, 1); msg != nil {
+		t.Fatalf("exactly sized slices panicked: %v", msg)
+	}
+
+	want := "dft: DftCmplx8: xi has length 14, need 15 for stride 2"
+	if msg := stridePanic(make([]complex128, 14), make([]complex128, 8), 2, 1); msg != want {
+		t.Fatalf("undersized input: got %v, want %q", msg, want)
+	}
+
+	want = "dft: DftCmplx8: xo has length 8, need 22 for stride 3"
+	if msg := stridePanic(make([]complex128, 8), make([]complex128, 8), 1, 3); msg != want {
+		t.Fatalf("undersized output: got %v, want %q", msg, want)
+	}
+}
+`)
+}
@@ -0,0 +1,134 @@
+package genfft
+
+import (
+	"path/filepath"
+
+	"github.com/dave/jennifer/jen"
+	log "github.com/sirupsen/logrus"
+)
+
+// SupportFiles renders every file written alongside the transforms, keyed by
+// filename: definitions shared by transforms in the same package, the
+// operation counts their benchmarks report throughput with, the alignment
+// benchmarks, the round trip tests and the dispatch functions.
+func SupportFiles(dfts []Dft) map[string]*jen.File {
+	files := supportFiles(dfts)
+	for filename, f := range flopsSupport(dfts) {
+		files[filename] = f
+	}
+	for filename, f := range alignSupport(dfts) {
+		files[filename] = f
+	}
+	for filename, f := range roundTripSupport(dfts) {
+		files[filename] = f
+	}
+	for filename, f := range dispatchSupport(dfts) {
+		files[filename] = f
+	}
+
+	return files
+}
+
+// supportFiles renders definitions shared by the transforms written to each
+// package directory, keyed by filename.
+func supportFiles(dfts []Dft) map[string]*jen.File {
+	files := map[string]*jen.File{}
+	windowSizes := map[string][]int{}
+
+	for _, dft := range dfts {
+		dir := filepath.Dir(dft.Prefix)
+
+		// Every package has the reference transform.
+		files[filepath.Join(dir, "naive.go")] = naiveSupport("dft")
+
+		// Out-of-place transforms assert their arguments don't overlap.
+		if dft.NoAlias {
+			release, debug := aliasSupport("dft")
+			files[filepath.Join(dir, "alias.go")] = release
+			files[filepath.Join(dir, "alias_debug.go")] = debug
+		}
+
+		// Transforms with an alignment requirement share the assertions.
+		if dft.Align != 0 {
+			files[filepath.Join(dir, "aligned.go")] = alignedSupport("dft")
+		}
+
+		// Transforms returning errors share the overlap checks.
+		if dft.ReturnErrors {
+			files[filepath.Join(dir, "errors.go")] = errorsSupport("dft")
+		}
+
+		// Transforms flushing to zero share the flush helpers.
+		if dft.FlushToZero != 0 {
+			files[filepath.Join(dir, "flush.go")] = flushSupport("dft")
+		}
+
+		// Timed transforms share the logging, compiled in by the debug tag.
+		if dft.DebugTiming {
+			release, debug := timingSupport("dft")
+			files[filepath.Join(dir, "timing.go")] = release
+			files[filepath.Join(dir, "timing_debug.go")] = debug
+		}
+
+		// Transforms quieting NaNs share the quieting helpers.
+		if dft.QuietNaN {
+			files[filepath.Join(dir, "quiet.go")] = quietSupport("dft")
+		}
+
+		// Bit-reversed transforms share the un-permute helpers.
+		if dft.BitReverse {
+			files[filepath.Join(dir, "bitrev.go")] = bitReverseSupport("dft")
+		}
+
+		// Frame methods share their receiver type.
+		if dft.Frame {
+			files[filepath.Join(dir, "frame.go")] = frameSupport("dft")
+		}
+
+		// Compensated transforms share the summation helpers.
+		if dft.Compensated {
+			files[filepath.Join(dir, "compensated.go")] = compensatedSupport("dft")
+		}
+
+		// Generic transforms share a constraint.
+		if dft.Generic {
+			files[filepath.Join(dir, "generic.go")] = genericSupport("dft")
+		}
+
+		// Transformer types share their interface.
+		if dft.Transformer {
+			files[filepath.Join(dir, "transformer.go")] = transformerSupport("dft")
+		}
+
+		// Direction wrappers share the type selecting the direction.
+		if dft.Direction {
+			files[filepath.Join(dir, "direction.go")] = directionSupport("dft")
+		}
+
+		// Accessor variants share their interface.
+		if dft.Accessor {
+			files[filepath.Join(dir, "accessor.go")] = accessorSupport("dft")
+		}
+
+		// Views are independent of any transform's size.
+		if dft.Views {
+			files[filepath.Join(dir, "view.go")] = viewSupport("dft")
+		}
+
+		// Windows are shared by transforms of the same size.
+		if dft.Windows {
+			prog, err := dft.Parse()
+			if err != nil {
+				log.Warnf("skipping windows for %s: %+v\n", dft.Func, err)
+				continue
+			}
+			windowSizes[dir] = append(windowSizes[dir], prog.TransformLength())
+		}
+	}
+
+	for dir, sizes := range windowSizes {
+		files[filepath.Join(dir, "window.go")] = windowSupport("dft", sizes)
+	}
+
+	return files
+}
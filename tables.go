@@ -0,0 +1,194 @@
+package genfft
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// tableRe matches a table of transforms in the dft package's tests, capturing
+// its name and element type.
+var tableRe = regexp.MustCompile(`(?s)var (floatDfts|cmplxDfts) = \[\](floatDft|cmplxDft)\{.*?\n\}`)
+
+// tableSignatures are the signatures of transforms listed by each table,
+// without the function's name.
+var tableSignatures = map[string]string{
+	"floatDfts": "(ri, ii, ro, io []float64)",
+	"cmplxDfts": "(xi, xo []complex128)",
+}
+
+// tableEntries returns the functions belonging in a table, forward transforms
+// with the table's signature, ordered by size. The tables are checked against
+// the forward reference transform, so inverse transforms are left out.
+func tableEntries(table string, funcs []FuncAPI) (entries []FuncAPI) {
+	for _, fn := range funcs {
+		if !fn.Inverse && fn.Signature == "func "+fn.Name+tableSignatures[table] {
+			entries = append(entries, fn)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Size < entries[j].Size
+	})
+	return entries
+}
+
+// RewriteTables returns the source of the dft package's tests with the
+// tables of transforms they check listing the given functions.
+func RewriteTables(src []byte, funcs []FuncAPI) ([]byte, error) {
+	crlf := bytes.Contains(src, []byte("\r\n"))
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+
+	found := 0
+	src = tableRe.ReplaceAllFunc(src, func(table []byte) []byte {
+		found++
+		m := tableRe.FindSubmatch(table)
+		name, typ := string(m[1]), string(m[2])
+
+		buf := &bytes.Buffer{}
+		fmt.Fprintf(buf, "var %s = []%s{\n", name, typ)
+		for _, fn := range tableEntries(name, funcs) {
+			fmt.Fprintf(buf, "\t{%d, %s},\n", fn.Size, fn.Name)
+		}
+		buf.WriteString("}")
+		return buf.Bytes()
+	})
+	if found != len(tableSignatures) {
+		return nil, fmt.Errorf("found %d of %d tables", found, len(tableSignatures))
+	}
+
+	src, err := format.Source(src)
+	if err != nil {
+		return nil, fmt.Errorf("format.Source: %w", err)
+	}
+
+	if crlf {
+		src = bytes.ReplaceAll(src, []byte("\n"), []byte("\r\n"))
+	}
+	return src, nil
+}
+
+// generatedTableRe matches a table of transforms in the generated tests,
+// capturing its name and entries.
+var generatedTableRe = regexp.MustCompile(`(?s)var generated(Float|Cmplx)Dfts = \[\]\w+\{(.*?)\n\}`)
+
+// generatedEntryRe matches an entry of a generated table.
+var generatedEntryRe = regexp.MustCompile(`\{(\d+), (\w+)\}`)
+
+// generatedTestsTemplate is the generated tests, given the float and complex
+// tables' entries. They share the step inputs, tolerance and table types of
+// the dft package's own tests.
+const generatedTestsTemplate = `// Code generated by genfft. DO NOT EDIT.
+
+package dft
+
+import (
+	"strconv"
+	"testing"
+)
+
+var generatedFloatDfts = []floatDft{
+%s}
+
+func TestGeneratedFloatDFT(t *testing.T) {
+	for _, dft := range generatedFloatDfts {
+		t.Run(strconv.FormatInt(int64(dft.Size), 10), func(t *testing.T) {
+			re := stepFloat(dft.Size)
+			im := make([]float64, dft.Size)
+			dft.Fn(re, im, re, im)
+
+			genOut := make([]complex128, dft.Size)
+			for idx := range genOut {
+				genOut[idx] = complex(re[idx], im[idx])
+			}
+
+			naiveOut := stepCmplx(dft.Size)
+			NaiveDFT(naiveOut, false)
+
+			err := dftError(genOut, naiveOut)
+			t.Logf("DFT%%d Error: %%0.12g", dft.Size, err)
+			if err > tolerance {
+				t.Fail()
+			}
+		})
+	}
+}
+
+var generatedCmplxDfts = []cmplxDft{
+%s}
+
+func TestGeneratedCmplxDFT(t *testing.T) {
+	for _, dft := range generatedCmplxDfts {
+		t.Run(strconv.FormatInt(int64(dft.Size), 10), func(t *testing.T) {
+			xi := stepCmplx(dft.Size)
+			dft.Fn(xi, xi)
+
+			naiveOut := stepCmplx(dft.Size)
+			NaiveDFT(naiveOut, false)
+
+			err := dftError(xi, naiveOut)
+			t.Logf("DFT%%d Error: %%0.3g", dft.Size, err)
+			if err > tolerance {
+				t.Fail()
+			}
+		})
+	}
+}
+`
+
+// GeneratedTests returns the source of tests checking the given functions
+// against the reference transform, keeping the functions already listed by
+// src, the previous source if any. Functions are listed once however many
+// times the tests are regenerated.
+func GeneratedTests(src []byte, funcs []FuncAPI) ([]byte, error) {
+	crlf := bytes.Contains(src, []byte("\r\n"))
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+
+	// Entries from previous runs keep their place unless regenerated.
+	var listed []FuncAPI
+	for _, m := range generatedTableRe.FindAllSubmatch(src, -1) {
+		table := "floatDfts"
+		if string(m[1]) == "Cmplx" {
+			table = "cmplxDfts"
+		}
+		for _, e := range generatedEntryRe.FindAllSubmatch(m[2], -1) {
+			size, _ := strconv.Atoi(string(e[1]))
+			name := string(e[2])
+			listed = append(listed, FuncAPI{Name: name, Size: size, Signature: "func " + name + tableSignatures[table]})
+		}
+	}
+
+	seen := map[string]bool{}
+	var merged []FuncAPI
+	for _, fn := range append(funcs, listed...) {
+		if !seen[fn.Name] {
+			seen[fn.Name] = true
+			merged = append(merged, fn)
+		}
+	}
+
+	tables := map[string]*bytes.Buffer{}
+	for table := range tableSignatures {
+		tables[table] = &bytes.Buffer{}
+		entries := tableEntries(table, merged)
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].Size < entries[j].Size || entries[i].Size == entries[j].Size && entries[i].Name < entries[j].Name
+		})
+		for _, fn := range entries {
+			fmt.Fprintf(tables[table], "\t{%d, %s},\n", fn.Size, fn.Name)
+		}
+	}
+
+	src, err := format.Source([]byte(fmt.Sprintf(generatedTestsTemplate, tables["floatDfts"], tables["cmplxDfts"])))
+	if err != nil {
+		return nil, fmt.Errorf("format.Source: %w", err)
+	}
+
+	if crlf {
+		src = bytes.ReplaceAll(src, []byte("\n"), []byte("\r\n"))
+	}
+	return src, nil
+}
@@ -0,0 +1,160 @@
+package genfft
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestRewriteTables(t *testing.T) {
+	dir := t.TempDir()
+
+	var funcs []FuncAPI
+	for _, dft := range []Dft{
+		{Func: "DftCmplx4"},
+		{Func: "DftFloat3"},
+		{Func: "DftCmplx2"},
+		{Func: "DftFloat2"},
+		// Transforms with other signatures can't be listed.
+		{Func: "DftCmplx5", Options: Options{RuntimeSign: true}},
+		// Nor can inverse transforms, which the forward reference would fail.
+		{Func: "DftCmplxInv4", Options: Options{Inverse: true}},
+	} {
+		n, _ := strconv.Atoi(dft.Func[len(dft.Func)-1:])
+		alst, cout := naiveSchedule(n, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+
+		prog := mustParse(t, dft)
+		fnFuncs, err := prog.API(mustRender(t, prog, dft.Func))
+		if err != nil {
+			t.Fatal(err)
+		}
+		funcs = append(funcs, fnFuncs...)
+	}
+
+	src, err := os.ReadFile("dft/dft_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := RewriteTables(src, funcs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"var floatDfts = []floatDft{\r\n\t{2, DftFloat2},\r\n\t{3, DftFloat3},\r\n}\r\n",
+		"var cmplxDfts = []cmplxDft{\r\n\t{2, DftCmplx2},\r\n\t{4, DftCmplx4},\r\n}\r\n",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("missing table %q", want)
+		}
+	}
+	if strings.Contains(string(got), "DftCmplx5") || strings.Contains(string(got), "DftCmplxInv4") || strings.Contains(string(got), "DftFloat16") {
+		t.Error("table lists a transform it shouldn't")
+	}
+
+	// Rewriting the committed tables with the transforms they list must
+	// leave them unchanged.
+	var committed []FuncAPI
+	for n := 2; n <= 16; n++ {
+		committed = append(committed,
+			FuncAPI{Name: "DftFloat" + strconv.Itoa(n), Size: n, Signature: "func DftFloat" + strconv.Itoa(n) + "(ri, ii, ro, io []float64)"},
+			FuncAPI{Name: "DftCmplx" + strconv.Itoa(n), Size: n, Signature: "func DftCmplx" + strconv.Itoa(n) + "(xi, xo []complex128)"},
+		)
+	}
+	if got, err := RewriteTables(src, committed); err != nil || string(got) != string(src) {
+		t.Fatalf("rewriting the committed tables changed them (err %v)", err)
+	}
+}
+
+func TestGeneratedTests(t *testing.T) {
+	dir := t.TempDir()
+
+	var funcs []FuncAPI
+	files := map[string]*jen.File{"naive.go": naiveSupport("dft")}
+	for _, dft := range []Dft{
+		{Func: "DftCmplx4"},
+		{Func: "DftFloat3"},
+		{Func: "DftCmplx2"},
+		// Transforms with other signatures can't be listed.
+		{Func: "DftCmplx5", Options: Options{RuntimeSign: true}},
+		// Nor can inverse transforms, which the forward reference would fail.
+		{Func: "DftCmplxInv4", Options: Options{Inverse: true}},
+	} {
+		n, _ := strconv.Atoi(dft.Func[len(dft.Func)-1:])
+		alst, cout := naiveSchedule(n, dft.Func[3] == 'F')
+		dft.Prefix = writeSchedule(t, dir, dft.Func, alst, cout)
+
+		prog := mustParse(t, dft)
+		f := mustRender(t, prog, dft.Func)
+		fnFuncs, err := prog.API(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		funcs = append(funcs, fnFuncs...)
+		files[dft.Func+".go"] = f
+	}
+
+	// Functions are added to the tables left by earlier runs.
+	src, err := GeneratedTests(nil, funcs[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err = GeneratedTests(src, funcs[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"var generatedFloatDfts = []floatDft{\n\t{3, DftFloat3},\n}\n",
+		"var generatedCmplxDfts = []cmplxDft{\n\t{2, DftCmplx2},\n\t{4, DftCmplx4},\n}\n",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("missing table %q in:\n%s", want, src)
+		}
+	}
+	if strings.Contains(string(src), "DftCmplx5") || strings.Contains(string(src), "DftCmplxInv4") {
+		t.Error("table lists a transform it shouldn't")
+	}
+
+	// Regenerating the same functions must leave the tests unchanged.
+	crlf := []byte(strings.ReplaceAll(string(src), "\n", "\r\n"))
+	if again, err := GeneratedTests(crlf, funcs); err != nil || string(again) != string(crlf) {
+		t.Fatalf("regenerating the tests changed them (err %v):\n%s", err, again)
+	}
+
+	// The tests share the definitions of the dft package's own tests.
+	goTest(t, files, string(src)+`
+const tolerance = 2.5e-15
+
+func stepFloat(n int) (out []float64) {
+	out = make([]float64, n)
+	for idx := 0; idx < n>>1; idx++ {
+		out[idx] = 1
+	}
+	return
+}
+
+func stepCmplx(n int) (out []complex128) {
+	out = make([]complex128, n)
+	for idx := 0; idx < n>>1; idx++ {
+		out[idx] = 1
+	}
+	return
+}
+
+type floatDft struct {
+	Size int
+	Fn   func(ri, ii, ro, io []float64)
+}
+
+type cmplxDft struct {
+	Size int
+	Fn   func(xi, xo []complex128)
+}
+`, "-run", "Generated", "-v")
+}
@@ -0,0 +1,44 @@
+package genfft
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// genTiming renders a deferred call logging how long a transform took. The
+// call is guarded by a constant which is false unless the package is built
+// with the debug tag, so release builds compile it away.
+func genTiming(g *jen.Group, name string) {
+	g.If(jen.Id("debugTiming")).Block(
+		jen.Defer().Id("logTiming").Call(jen.Lit(name), jen.Qual("time", "Now").Call()),
+	)
+	g.Line()
+}
+
+// timingSupport renders the release and debug implementations of the timing
+// logged by transforms.
+func timingSupport(path string) (release, debug *jen.File) {
+	release = jen.NewFilePathName(path, path)
+	release.HeaderComment("//go:build !debug\n// +build !debug")
+
+	debug = jen.NewFilePathName(path, path)
+	debug.HeaderComment("//go:build debug\n// +build debug")
+
+	params := []jen.Code{jen.Id("name").String(), jen.Id("start").Qual("time", "Time")}
+
+	// Release builds never log timing.
+	release.Comment("debugTiming enables logging the time taken by transforms.")
+	release.Const().Id("debugTiming").Op("=").False()
+	release.Line()
+	release.Func().Id("logTiming").Params(params...).Block()
+
+	// Debug builds log to the standard logger.
+	debug.Comment("debugTiming enables logging the time taken by transforms.")
+	debug.Const().Id("debugTiming").Op("=").True()
+	debug.Line()
+	debug.Comment("logTiming logs the time taken by a call to the named transform which\nbegan at start.")
+	debug.Func().Id("logTiming").Params(params...).Block(
+		jen.Qual("log", "Printf").Call(jen.Lit("dft: %s took %v"), jen.Id("name"), jen.Qual("time", "Since").Call(jen.Id("start"))),
+	)
+
+	return
+}
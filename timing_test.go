@@ -0,0 +1,43 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestDebugTiming(t *testing.T) {
+	release, debug := timingSupport("dft")
+	files := map[string]*jen.File{
+		"cmplx_8.go":      generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{DebugTiming: true}}),
+		"timing.go":       release,
+		"timing_debug.go": debug,
+	}
+
+	test := `package dft
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+)
+
+func TestDebugTiming(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	DftCmplx8(randCmplx(8), make([]complex128, 8))
+
+	if logged := strings.Contains(buf.String(), "dft: DftCmplx8 took "); logged != debug {
+		t.Fatalf("expected timing logged=%v, got %q", debug, buf.String())
+	}
+}
+`
+
+	t.Run("Release", func(t *testing.T) {
+		goTest(t, files, test+"\nconst debug = false\n")
+	})
+	t.Run("Debug", func(t *testing.T) {
+		goTest(t, files, test+"\nconst debug = true\n", "-tags", "debug")
+	})
+}
@@ -0,0 +1,29 @@
+package genfft
+
+import (
+	"fmt"
+)
+
+// maxTinyGoStatements bounds the schedule statements evaluated by each
+// function of a TinyGo transform, large functions compile slowly and spill
+// heavily on microcontrollers.
+const maxTinyGoStatements = 128
+
+// tinyGo returns the options adjusted for transforms compiled with TinyGo,
+// which lacks a fast math.FMA and may not support unsafe. Options relying on
+// either are an error, and schedules with more than maxTinyGoStatements
+// statements are split into stages unless their stages are already set.
+func (o Options) tinyGo(name string, statements int) (Options, error) {
+	if o.Contraction == "fma" {
+		return Options{}, fmt.Errorf("%s: tinyGo transforms can't contract to math.FMA", name)
+	}
+	if o.NoAlias || o.Align != 0 || o.Views || o.ReturnErrors {
+		return Options{}, fmt.Errorf("%s: tinyGo transforms can't use unsafe for noAlias, align, views or returnErrors", name)
+	}
+
+	if o.Stages == 0 && statements > maxTinyGoStatements {
+		o.Stages = (statements + maxTinyGoStatements - 1) / maxTinyGoStatements
+	}
+
+	return o, nil
+}
@@ -0,0 +1,70 @@
+package genfft
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestTinyGo(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_80.go": generateNaive(t, 80, Dft{Func: "DftCmplx80", Options: Options{TinyGo: true}}),
+		"float_8.go":  generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{TinyGo: true, PSD: true, Into: true}}),
+	}
+
+	// The naive schedule of 80 elements has a load and a store per element,
+	// so it's split in two stages, the smaller one isn't split at all.
+	wantStages := map[string]int{"cmplx_80.go": 2, "float_8.go": 0}
+
+	for filename, f := range files {
+		file, err := goparser.ParseFile(token.NewFileSet(), filename, f.GoString(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, spec := range file.Imports {
+			if path, _ := strconv.Unquote(spec.Path.Value); path == "unsafe" {
+				t.Errorf("%s: imports unsafe", filename)
+			}
+		}
+
+		stages := 0
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.SelectorExpr:
+				if x, ok := n.X.(*ast.Ident); ok && x.Name == "math" && n.Sel.Name == "FMA" {
+					t.Errorf("%s: calls math.FMA", filename)
+				}
+			case *ast.FuncDecl:
+				if strings.Contains(n.Name.Name, "Stage") {
+					stages++
+				}
+			}
+			return true
+		})
+		if stages != wantStages[filename] {
+			t.Errorf("%s: split into %d stages, want %d", filename, stages, wantStages[filename])
+		}
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestTinyGo(t *testing.T) {
+	xi := randCmplx(80)
+	xo := make([]complex128, 80)
+	DftCmplx80(xi, xo)
+
+	naiveDFT(xi, -1.0)
+	if err := dftError(xo, xi); err > 1e-12 {
+		t.Fatalf("error %g", err)
+	}
+}
+`)
+}
@@ -0,0 +1,76 @@
+package genfft
+
+import (
+	"fmt"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genTransformer renders a type whose Transform method computes a complex
+// transform, satisfying the Transformer interface.
+func (p Program) genTransformer(f *jen.File, name string) error {
+	if p.Float() {
+		return fmt.Errorf("%s: transformer types require a complex schedule", name)
+	}
+
+	typeName := name + "Transformer"
+
+	// Transform's signature is fixed, so the direction of transforms chosen
+	// at runtime is a field.
+	args := []jen.Code{jen.Id("xi"), jen.Id("xo")}
+	if p.Options.OutputBase {
+		args = append(args, jen.Lit(0))
+	}
+
+	f.Line()
+	if p.Options.RuntimeSign || p.Options.SignMultiplier {
+		signType := jen.Int()
+		if p.Options.SignMultiplier {
+			signType = jen.Float64()
+		}
+
+		f.Comment(fmt.Sprintf("%s is a Transformer computing %s in the direction\ngiven by Sign.", typeName, name))
+		f.Type().Id(typeName).Struct(
+			jen.Id("Sign").Add(signType),
+		)
+		args = append(args, jen.Id("t").Dot("Sign"))
+	} else {
+		f.Comment(fmt.Sprintf("%s is a Transformer computing %s.", typeName, name))
+		f.Type().Id(typeName).Struct()
+	}
+	f.Line()
+
+	f.Var().Id("_").Id("Transformer").Op("=").Id(typeName).Values()
+	f.Line()
+
+	// Transform can't return an error either, so it panics with any the
+	// transform returns.
+	call := jen.Id(name).Call(args...)
+	doc := fmt.Sprintf("Transform computes %s of xi into xo.", name)
+	if p.Options.ReturnErrors {
+		call = jen.If(jen.Err().Op(":=").Add(call), jen.Err().Op("!=").Nil()).Block(
+			jen.Panic(jen.Err()),
+		)
+		doc += fmt.Sprintf(" It panics with any error %s\nreturns, since Transformer's signature is fixed.", name)
+	}
+
+	f.Comment(doc)
+	f.Func().Params(jen.Id("t").Id(typeName)).Id("Transform").Params(
+		jen.List(jen.Id("xi"), jen.Id("xo")).Index().Complex128(),
+	).Block(call)
+
+	return nil
+}
+
+// transformerSupport renders the interface implemented by transformer types,
+// which downstream code can depend on in place of a particular transform.
+func transformerSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	f.Comment("Transformer computes a complex transform of xi into xo.")
+	f.Type().Id("Transformer").Interface(
+		jen.Id("Transform").Params(jen.List(jen.Id("xi"), jen.Id("xo")).Index().Complex128()),
+	)
+
+	return f
+}
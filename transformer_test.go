@@ -0,0 +1,43 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestTransformer(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go":      generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Transformer: true}}),
+		"cmplx_8_sign.go": generateNaive(t, 8, Dft{Func: "DftCmplx8Sign", Options: Options{Transformer: true, RuntimeSign: true}}),
+		"transformer.go":  transformerSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestTransformer(t *testing.T) {
+	var tr Transformer = DftCmplx8Transformer{}
+
+	xi := randCmplx(8)
+	want, got := make([]complex128, 8), make([]complex128, 8)
+	DftCmplx8(xi, want)
+	tr.Transform(xi, got)
+	for k := range want {
+		if got[k] != want[k] {
+			t.Fatalf("output %d is %v, want %v", k, got[k], want[k])
+		}
+	}
+
+	inverse := Transformer(DftCmplx8SignTransformer{Sign: 1})
+	inverse.Transform(want, got)
+	for k := range got {
+		got[k] /= 8
+	}
+	if err := dftError(got, xi); err > 1e-13 {
+		t.Fatalf("inverse error %g", err)
+	}
+}
+`)
+}
@@ -0,0 +1,166 @@
+package genfft
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Twiddle reports whether the program is a twiddle codelet, one reading
+// precomputed twiddle factors from a W slice, such as FFTW's t1 codelets
+// generated by gen_twiddle.
+func (p Program) Twiddle() bool {
+	for _, s := range p.Statements {
+		for _, input := range s.Inputs() {
+			if input == "W" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// twiddleCount returns the number of twiddle factors each iteration of a
+// twiddle codelet reads, one more than the largest index of W.
+func (p Program) twiddleCount() (n int) {
+	for _, s := range p.Statements {
+		for _, id := range s.Idents() {
+			if name, k, ok := splitIndex(id); ok && name == "W" && k >= n {
+				n = k + 1
+			}
+		}
+	}
+
+	return n
+}
+
+// checkTwiddle returns an error if a twiddle codelet doesn't compute in place
+// in separate real and imaginary slices, sets any option not known to render
+// the same code inside its loop, or inlines twiddle factors it doesn't read
+// as a cosine and sine for each of its inputs after the first.
+func (p Program) checkTwiddle(name string) error {
+	if !p.Float() {
+		return fmt.Errorf("%s: twiddle codelets require separate real and imaginary slices", name)
+	}
+	for _, s := range p.Statements {
+		for _, slice := range s.Inputs() {
+			if slice == "ro" || slice == "io" {
+				return fmt.Errorf("%s: twiddle codelets compute in place, but the schedule writes %s", name, slice)
+			}
+		}
+	}
+
+	rest := p.Options
+	rest.Canonical, rest.CSE, rest.GoGenerate, rest.InlineTwiddles = false, false, false, false
+	rest.Constants = nil
+
+	if !reflect.DeepEqual(rest, Options{}) {
+		return fmt.Errorf("%s: twiddle codelets support only canonical, cse, goGenerate, inlineTwiddles and constants", name)
+	}
+
+	if n, w := p.TransformLength(), p.twiddleCount(); p.Options.InlineTwiddles && w != 2*(n-1) {
+		return fmt.Errorf("%s: inline twiddles require a cosine and sine for each of the %d inputs after the first, but the schedule reads %d twiddle factors", name, n-1, w)
+	}
+
+	return nil
+}
+
+// genTwiddle renders a twiddle codelet as a function taking the loop
+// parameters FFTW's twiddle codelets take. Each iteration m from mb up to me
+// computes one transform in place, with its slices advanced by m*ms elements
+// and its twiddle factors by m times the number each iteration reads.
+func (p Program) genTwiddle(f *jen.File, name string) {
+	if p.Options.InlineTwiddles {
+		p.genInlineTwiddle(f, name)
+		return
+	}
+
+	n, w := p.TransformLength(), p.twiddleCount()
+
+	ids := []jen.Code{jen.Id("ri"), jen.Id("ii"), jen.Id("W")}
+	advanced := []jen.Code{
+		jen.Id("ri").Index(jen.Id("m").Op("*").Id("ms").Op(":")),
+		jen.Id("ii").Index(jen.Id("m").Op("*").Id("ms").Op(":")),
+		jen.Id("W").Index(jen.Id("m").Op("*").Lit(w).Op(":")),
+	}
+
+	var loop []jen.Code
+	for _, id := range append(p.Strides(), "mb", "me", "ms") {
+		loop = append(loop, jen.Id(id))
+	}
+	params := []jen.Code{jen.List(ids...).Index().Float64(), jen.List(loop...).Int()}
+
+	f.Comment(fmt.Sprintf("%s computes %d-point float DFTs of ri and ii in place, one for each m", name, n))
+	f.Comment(fmt.Sprintf("from mb up to me, with the slices advanced by m*ms and the %d twiddle", w))
+	f.Comment(fmt.Sprintf("factors read from W[m*%d:].", w))
+	f.Func().Id(name).Params(params...).BlockFunc(func(g *jen.Group) {
+		p.genConstants(g)
+
+		g.For(
+			jen.Id("m").Op(":=").Id("mb"),
+			jen.Id("m").Op("<").Id("me"),
+			jen.Id("m").Op("++"),
+		).BlockFunc(func(g *jen.Group) {
+			g.List(ids...).Op(":=").List(advanced...)
+			g.Line()
+
+			for _, expr := range p.Statements {
+				g.Add(p.genStatement(expr))
+			}
+		})
+	})
+}
+
+// genInlineTwiddle renders a twiddle codelet computing its twiddle factors in
+// each iteration m rather than reading them from W. Input k is multiplied by
+// the conjugate of the factor whose cosine and sine are W[2k-2] and W[2k-1],
+// which FFTW computes as the angle 2*pi*m*k/size, for size the length of the
+// transform the codelet is a pass of. Each angle is computed with math.Sincos
+// into an array standing in for W, so the statements are unchanged.
+func (p Program) genInlineTwiddle(f *jen.File, name string) {
+	n, w := p.TransformLength(), p.twiddleCount()
+
+	var loop []jen.Code
+	for _, id := range append(p.Strides(), "mb", "me", "ms", "size") {
+		loop = append(loop, jen.Id(id))
+	}
+	params := []jen.Code{jen.List(jen.Id("ri"), jen.Id("ii")).Index().Float64(), jen.List(loop...).Int()}
+
+	f.Comment(fmt.Sprintf("%s computes %d-point float DFTs of ri and ii in place, one for each m", name, n))
+	f.Comment("from mb up to me, with the slices advanced by m*ms and the twiddle factors")
+	f.Comment("of a pass of a transform of length size computed inline.")
+	f.Func().Id(name).Params(params...).BlockFunc(func(g *jen.Group) {
+		p.genConstants(g)
+
+		g.Var().Id("W").Index(jen.Lit(w)).Float64()
+		g.Id("step").Op(":=").Lit(2).Op("*").Qual("math", "Pi").Op("/").Float64().Call(jen.Id("size"))
+		g.Line()
+
+		g.For(
+			jen.Id("m").Op(":=").Id("mb"),
+			jen.Id("m").Op("<").Id("me"),
+			jen.Id("m").Op("++"),
+		).BlockFunc(func(g *jen.Group) {
+			g.List(jen.Id("ri"), jen.Id("ii")).Op(":=").List(
+				jen.Id("ri").Index(jen.Id("m").Op("*").Id("ms").Op(":")),
+				jen.Id("ii").Index(jen.Id("m").Op("*").Id("ms").Op(":")),
+			)
+			for k := 1; k < n; k++ {
+				mk := jen.Id("m")
+				if k > 1 {
+					mk.Op("*").Lit(k)
+				}
+				g.List(jen.Id("W").Index(jen.Lit(2*k-1)), jen.Id("W").Index(jen.Lit(2*k-2))).Op("=").Qual("math", "Sincos").Call(
+					jen.Id("step").Op("*").Float64().Call(mk),
+				)
+			}
+			g.Line()
+
+			for _, expr := range p.Statements {
+				g.Add(p.genStatement(expr))
+			}
+		})
+	})
+}
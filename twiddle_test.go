@@ -0,0 +1,199 @@
+package genfft
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// twiddleSchedule returns the schedule of an n-point float twiddle codelet
+// computing in place, at stride rs, the transform of inputs multiplied by the
+// conjugates of their twiddle factors, and its constants.
+func twiddleSchedule(n int) (alst, cout string) {
+	alst, cout = naiveSchedule(n, true)
+
+	load := regexp.MustCompile(`\(:= (T\d+) (ri|ii)\[(\d+)\]\)`)
+	alst = load.ReplaceAllStringFunc(alst, func(s string) string {
+		m := load.FindStringSubmatch(s)
+		k, _ := strconv.Atoi(m[3])
+		if k == 0 {
+			return s
+		}
+
+		c, d := fmt.Sprintf("W[%d]", 2*k-2), fmt.Sprintf("W[%d]", 2*k-1)
+		re, im := fmt.Sprintf("ri[%d]", k), fmt.Sprintf("ii[%d]", k)
+		if m[2] == "ri" {
+			return fmt.Sprintf("(:= %s (+ (* %s %s) (* %s %s)))", m[1], c, re, d, im)
+		}
+		return fmt.Sprintf("(:= %s (+ (* %s %s) (- (* %s %s))))", m[1], c, im, d, re)
+	})
+
+	alst = strings.NewReplacer("ro[", "ri[", "io[", "ii[").Replace(alst)
+	alst = regexp.MustCompile(`(ri|ii)\[(\d+)\]`).ReplaceAllString(alst, "$1[WS(rs, $2)]")
+	return alst, cout
+}
+
+func TestTwiddle(t *testing.T) {
+	alst, cout := twiddleSchedule(4)
+	dft := Dft{Prefix: writeSchedule(t, t.TempDir(), "t1_4", alst, cout), Func: "TwFloat4"}
+
+	prog := mustParse(t, dft)
+	if !prog.Twiddle() || prog.twiddleCount() != 6 {
+		t.Fatalf("expected a twiddle codelet reading 6 twiddle factors")
+	}
+
+	files := map[string]*jen.File{"t1_4.go": mustGenerate(t, dft)}
+	if src := files["t1_4.go"].GoString(); !strings.Contains(src, "func TwFloat4(ri, ii, W []float64, rs, mb, me, ms int)") {
+		t.Fatalf("expected FFTW's twiddle codelet signature:\n%s", src)
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestTwiddle(t *testing.T) {
+	// Three interleaved 4-point transforms, each of a column of a 4x3
+	// row-major matrix, with six twiddle factors per column.
+	const n, howMany = 4, 3
+	x := randCmplx(n * howMany)
+	w := randCmplx(howMany * (n - 1))
+
+	ri, ii := make([]float64, len(x)), make([]float64, len(x))
+	for idx, v := range x {
+		ri[idx], ii[idx] = real(v), imag(v)
+	}
+	W := make([]float64, 2*len(w))
+	for idx, v := range w {
+		W[2*idx], W[2*idx+1] = real(v), imag(v)
+	}
+
+	// Skip the first column to check the loop bounds.
+	TwFloat4(ri, ii, W, howMany, 1, howMany, 1)
+
+	for m := 0; m < howMany; m++ {
+		want, got := make([]complex128, n), make([]complex128, n)
+		for k := range want {
+			want[k] = x[k*howMany+m]
+			got[k] = complex(ri[k*howMany+m], ii[k*howMany+m])
+		}
+
+		// Columns from mb on are multiplied by the conjugates of their
+		// twiddle factors and transformed.
+		if m > 0 {
+			for k := 1; k < n; k++ {
+				tw := w[m*(n-1)+k-1]
+				want[k] *= complex(real(tw), -imag(tw))
+			}
+			naiveDFT(want, -1)
+		}
+
+		if err := dftError(got, want); err > 1e-13 {
+			t.Errorf("column %d: error %g", m, err)
+		}
+	}
+}
+`)
+}
+
+func TestInlineTwiddles(t *testing.T) {
+	alst, cout := twiddleSchedule(4)
+	dir := t.TempDir()
+	table := Dft{Prefix: writeSchedule(t, dir, "t1_4", alst, cout), Func: "TwFloat4"}
+	inline := Dft{Prefix: table.Prefix, Func: "TwFloat4Inline", Options: Options{InlineTwiddles: true}}
+
+	files := map[string]*jen.File{
+		"t1_4.go":        mustGenerate(t, table),
+		"t1_4_inline.go": mustGenerate(t, inline),
+	}
+	if src := files["t1_4_inline.go"].GoString(); !strings.Contains(src, "func TwFloat4Inline(ri, ii []float64, rs, mb, me, ms, size int)") {
+		t.Fatalf("expected the twiddle signature without W and with size:\n%s", src)
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInlineTwiddles(t *testing.T) {
+	// The first pass of a 12-point transform, three 4-point transforms of
+	// the columns of a 4x3 row-major matrix.
+	const n, howMany = 4, 3
+	x := randCmplx(n * howMany)
+
+	// FFTW's table of twiddle factors for the pass.
+	W := make([]float64, 0, 2*howMany*(n-1))
+	for m := 0; m < howMany; m++ {
+		for k := 1; k < n; k++ {
+			sin, cos := math.Sincos(2 * math.Pi * float64(m*k) / (n * howMany))
+			W = append(W, cos, sin)
+		}
+	}
+
+	split := func() (ri, ii []float64) {
+		ri, ii = make([]float64, len(x)), make([]float64, len(x))
+		for idx, v := range x {
+			ri[idx], ii[idx] = real(v), imag(v)
+		}
+		return ri, ii
+	}
+
+	ri, ii := split()
+	TwFloat4(ri, ii, W, howMany, 0, howMany, 1)
+	inlineRi, inlineIi := split()
+	TwFloat4Inline(inlineRi, inlineIi, howMany, 0, howMany, 1, n*howMany)
+
+	for idx := range ri {
+		if math.Abs(ri[idx]-inlineRi[idx]) > 1e-13 || math.Abs(ii[idx]-inlineIi[idx]) > 1e-13 {
+			t.Errorf("output %d: inline %v, table %v", idx, complex(inlineRi[idx], inlineIi[idx]), complex(ri[idx], ii[idx]))
+		}
+	}
+}
+`)
+}
+
+func TestTwiddleErrors(t *testing.T) {
+	alst, cout := twiddleSchedule(4)
+	prefix := writeSchedule(t, t.TempDir(), "t1_4", alst, cout)
+
+	prog, err := Dft{Prefix: prefix, Func: "TwFloat4", Options: Options{Into: true}}.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prog.Render("dft", "TwFloat4"); err == nil || !strings.Contains(err.Error(), "twiddle codelets support only") {
+		t.Fatalf("expected an error for an unsupported option, got %v", err)
+	}
+
+	// Twiddle codelets compute in place.
+	prog = &Program{Statements: []Expr{parseExpr(t, "(:= ro[0] (* W[0] ri[0]))")}}
+	if _, err := prog.Render("dft", "TwFloat1"); err == nil || !strings.Contains(err.Error(), "compute in place") {
+		t.Fatalf("expected an error for an out-of-place twiddle codelet, got %v", err)
+	}
+
+	// Inline twiddles compute a cosine and sine for each input after the first.
+	prog = &Program{Statements: []Expr{parseExpr(t, "(:= ri[WS(rs, 0)] (* W[0] ri[WS(rs, 1)]))")}, Options: Options{InlineTwiddles: true}}
+	if _, err := prog.Render("dft", "TwFloat2"); err == nil || !strings.Contains(err.Error(), "cosine and sine") {
+		t.Fatalf("expected an error for a twiddle factor per input, got %v", err)
+	}
+
+	// Inline twiddles require a twiddle codelet.
+	alst, cout = naiveSchedule(4, true)
+	prog, err = Dft{Prefix: writeSchedule(t, t.TempDir(), "float_4", alst, cout), Func: "DftFloat4", Options: Options{InlineTwiddles: true}}.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prog.Render("dft", "DftFloat4"); err == nil || !strings.Contains(err.Error(), "require a twiddle codelet") {
+		t.Fatalf("expected an error for inline twiddles without a twiddle codelet, got %v", err)
+	}
+
+	// Complex schedules reading twiddle factors aren't FFTW codelets.
+	prog = &Program{Statements: []Expr{parseExpr(t, "(:= xo[0] (* W[0] xi[0]))")}}
+	if _, err := prog.Render("dft", "TwCmplx1"); err == nil || !strings.Contains(err.Error(), "separate real and imaginary") {
+		t.Fatalf("expected an error for a complex twiddle codelet, got %v", err)
+	}
+}
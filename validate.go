@@ -0,0 +1,59 @@
+package genfft
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+)
+
+// LoadConfig reads the list of transforms to generate from a config file.
+func LoadConfig(filename string) (dfts []Dft, err error) {
+	configBytes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	err = json.Unmarshal(configBytes, &dfts)
+	if err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	dfts, err = expandGlobs(dfts)
+	if err != nil {
+		return nil, err
+	}
+
+	return expandCorpora(dfts)
+}
+
+// Validate checks that a config file loads, that every transform's schedule
+// and constants exist and parse, and that function names are valid go
+// identifiers unique within their package. It returns every problem found.
+func Validate(filename string) (problems []error) {
+	dfts, err := LoadConfig(filename)
+	if err != nil {
+		return []error{err}
+	}
+
+	// Functions in the same directory share a package.
+	defined := map[string]string{}
+	for idx, dft := range dfts {
+		if !token.IsIdentifier(dft.Func) {
+			problems = append(problems, fmt.Errorf("entry %d: func %q isn't a valid go identifier", idx, dft.Func))
+		}
+
+		key := filepath.Join(filepath.Dir(dft.Prefix), dft.Func)
+		if prefix, ok := defined[key]; ok {
+			problems = append(problems, fmt.Errorf("entry %d: func %s is already generated from %s", idx, dft.Func, prefix))
+		}
+		defined[key] = dft.Prefix
+
+		if _, err := dft.Parse(); err != nil {
+			problems = append(problems, fmt.Errorf("entry %d: %s: %w", idx, dft.Func, err))
+		}
+	}
+
+	return problems
+}
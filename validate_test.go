@@ -0,0 +1,57 @@
+package genfft
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	prefix := copyTestdata(t, dir, "cmplx_3")
+
+	writeConfig := func(dfts []Dft) string {
+		b, err := json.Marshal(dfts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		filename := filepath.Join(dir, "config.json")
+		if err := os.WriteFile(filename, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+
+	valid := writeConfig([]Dft{{Prefix: prefix, Func: "DftCmplx3"}})
+	if problems := Validate(valid); len(problems) != 0 {
+		t.Fatalf("valid config reported problems: %v", problems)
+	}
+
+	invalid := writeConfig([]Dft{
+		{Prefix: prefix, Func: "DftCmplx3"},
+		{Prefix: prefix, Func: "DftCmplx3"},
+		{Prefix: filepath.Join(dir, "cmplx_5"), Func: "Dft-Cmplx5"},
+	})
+
+	problems := Validate(invalid)
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 problems, got %d: %v", len(problems), problems)
+	}
+	if !strings.Contains(problems[0].Error(), "entry 1: func DftCmplx3 is already generated") {
+		t.Errorf("duplicate func not reported: %v", problems[0])
+	}
+	if !strings.Contains(problems[1].Error(), `entry 2: func "Dft-Cmplx5" isn't a valid go identifier`) {
+		t.Errorf("invalid func not reported: %v", problems[1])
+	}
+	if !errors.Is(problems[2], fs.ErrNotExist) || !strings.Contains(problems[2].Error(), "cmplx_5.alst") {
+		t.Errorf("missing schedule not reported: %v", problems[2])
+	}
+
+	if problems := Validate(filepath.Join(dir, "missing.json")); len(problems) != 1 {
+		t.Fatalf("missing config reported %v", problems)
+	}
+}
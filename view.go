@@ -0,0 +1,48 @@
+package genfft
+
+import (
+	"github.com/dave/jennifer/jen"
+)
+
+// viewSupport renders helpers reinterpreting interleaved real and imaginary
+// parts as complex values and back, sharing the underlying array. The views
+// use unsafe.Slice, so the file is constrained to go1.17, which also lets it
+// build in modules declaring an older version.
+func viewSupport(path string) *jen.File {
+	f := jen.NewFilePathName(path, path)
+	f.HeaderComment("//go:build go1.17\n// +build go1.17")
+
+	ptr := func(x string) *jen.Statement {
+		return jen.Qual("unsafe", "Pointer").Call(jen.Op("&").Id(x).Index(jen.Lit(0)))
+	}
+
+	// float64 and complex128 share their alignment, so any float64 can
+	// start a complex128.
+	f.Comment("ComplexView returns x, interleaved real and imaginary parts, as complex values\nsharing its underlying array. It panics if x has odd length.")
+	f.Func().Id("ComplexView").Params(jen.Id("x").Index().Float64()).Index().Complex128().Block(
+		jen.If(jen.Len(jen.Id("x")).Op("%").Lit(2).Op("!=").Lit(0)).Block(
+			jen.Panic(jen.Qual("fmt", "Sprintf").Call(jen.Lit("dft: ComplexView: odd length %d"), jen.Len(jen.Id("x")))),
+		),
+		jen.If(jen.Len(jen.Id("x")).Op("==").Lit(0)).Block(
+			jen.Return(jen.Nil()),
+		),
+		jen.Return(jen.Qual("unsafe", "Slice").Call(
+			jen.Parens(jen.Op("*").Complex128()).Call(ptr("x")),
+			jen.Len(jen.Id("x")).Op("/").Lit(2),
+		)),
+	)
+	f.Line()
+
+	f.Comment("FloatView returns x as interleaved real and imaginary parts sharing its\nunderlying array.")
+	f.Func().Id("FloatView").Params(jen.Id("x").Index().Complex128()).Index().Float64().Block(
+		jen.If(jen.Len(jen.Id("x")).Op("==").Lit(0)).Block(
+			jen.Return(jen.Nil()),
+		),
+		jen.Return(jen.Qual("unsafe", "Slice").Call(
+			jen.Parens(jen.Op("*").Float64()).Call(ptr("x")),
+			jen.Len(jen.Id("x")).Op("*").Lit(2),
+		)),
+	)
+
+	return f
+}
@@ -0,0 +1,78 @@
+package genfft
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestViews(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Views: true}}),
+		"view.go":    viewSupport("dft"),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestViews(t *testing.T) {
+	xi := randCmplx(8)
+	want := make([]complex128, 8)
+	DftCmplx8(xi, want)
+
+	interleaved := make([]float64, 16)
+	for k, x := range xi {
+		interleaved[2*k], interleaved[2*k+1] = real(x), imag(x)
+	}
+
+	// Transform the view in-place, results land in the float slice.
+	view := ComplexView(interleaved)
+	if len(view) != 8 {
+		t.Fatalf("view has length %d, want 8", len(view))
+	}
+	DftCmplx8(view, view)
+	for k := range want {
+		if got := complex(interleaved[2*k], interleaved[2*k+1]); got != want[k] {
+			t.Fatalf("output %d is %v, want %v", k, got, want[k])
+		}
+	}
+
+	floats := FloatView(want)
+	if len(floats) != 16 || &floats[0] != &FloatView(want[:1])[0] || floats[3] != imag(want[1]) {
+		t.Fatalf("FloatView doesn't share its argument's array")
+	}
+
+	if ComplexView(nil) != nil || FloatView(nil) != nil {
+		t.Fatalf("views of empty slices aren't nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("ComplexView didn't panic on odd length")
+		}
+	}()
+	ComplexView(make([]float64, 3))
+}
+`)
+}
+
+func TestViewsOlderModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := viewSupport("dft").Save(filepath.Join(dir, "view.go")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module dft\n\ngo 1.16\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// unsafe.Slice needs go1.17, which the file's constraint provides.
+	cmd := exec.Command("go", "build", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, out)
+	}
+}
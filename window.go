@@ -0,0 +1,73 @@
+package genfft
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// window is a periodic window function, w(k) for a transform of size n.
+type window struct {
+	name string
+	w    func(k, n int) float64
+}
+
+var windows = []window{
+	{"Hann", func(k, n int) float64 {
+		return 0.5 - 0.5*math.Cos(2*math.Pi*float64(k)/float64(n))
+	}},
+	{"Hamming", func(k, n int) float64 {
+		return 0.54 - 0.46*math.Cos(2*math.Pi*float64(k)/float64(n))
+	}},
+	{"Blackman", func(k, n int) float64 {
+		theta := 2 * math.Pi * float64(k) / float64(n)
+		return 0.42 - 0.5*math.Cos(theta) + 0.08*math.Cos(2*theta)
+	}},
+}
+
+// windowSupport renders precomputed coefficients of each window for every
+// size given, and helpers multiplying complex and float input by them.
+func windowSupport(path string, sizes []int) *jen.File {
+	f := jen.NewFilePathName(path, path)
+
+	// Render each size once, in order.
+	sort.Ints(sizes)
+	for idx, n := range sizes {
+		if idx > 0 && sizes[idx-1] == n {
+			continue
+		}
+
+		for _, win := range windows {
+			coeffs := unexport(fmt.Sprintf("%s%d", win.name, n))
+			f.Var().Id(coeffs).Op("=").Index(jen.Lit(n)).Float64().ValuesFunc(func(g *jen.Group) {
+				for k := 0; k < n; k++ {
+					g.Lit(win.w(k, n))
+				}
+			})
+			f.Line()
+
+			cmplxName := fmt.Sprintf("%sCmplx%d", win.name, n)
+			f.Comment(fmt.Sprintf("%s multiplies x by a periodic %d point %s window in-place.", cmplxName, n, win.name))
+			f.Func().Id(cmplxName).Params(jen.Id("x").Index().Complex128()).Block(
+				jen.For(jen.List(jen.Id("k"), jen.Id("w")).Op(":=").Range().Id(coeffs)).Block(
+					jen.Id("x").Index(jen.Id("k")).Op("*=").Complex(jen.Id("w"), jen.Lit(0)),
+				),
+			)
+			f.Line()
+
+			floatName := fmt.Sprintf("%sFloat%d", win.name, n)
+			f.Comment(fmt.Sprintf("%s multiplies re and im by a periodic %d point %s window in-place.", floatName, n, win.name))
+			f.Func().Id(floatName).Params(jen.List(jen.Id("re"), jen.Id("im")).Index().Float64()).Block(
+				jen.For(jen.List(jen.Id("k"), jen.Id("w")).Op(":=").Range().Id(coeffs)).Block(
+					jen.Id("re").Index(jen.Id("k")).Op("*=").Id("w"),
+					jen.Id("im").Index(jen.Id("k")).Op("*=").Id("w"),
+				),
+			)
+			f.Line()
+		}
+	}
+
+	return f
+}
@@ -0,0 +1,64 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+func TestWindows(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Windows: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Windows: true}}),
+		"window.go":  windowSupport("dft", []int{8, 8}),
+	}
+
+	goTest(t, files, `package dft
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWindows(t *testing.T) {
+	want := []float64{0, 0.5 - math.Sqrt2/4, 0.5, 0.5 + math.Sqrt2/4, 1, 0.5 + math.Sqrt2/4, 0.5, 0.5 - math.Sqrt2/4}
+	for k, w := range hann8 {
+		if math.Abs(w-want[k]) > 1e-15 {
+			t.Fatalf("hann8[%d] = %v, want %v", k, w, want[k])
+		}
+	}
+
+	xi := randCmplx(8)
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	for k, x := range xi {
+		ri[k], ii[k] = real(x), imag(x)
+	}
+
+	// Window the reference input directly.
+	naive := make([]complex128, 8)
+	for k, x := range xi {
+		w := 0.5 - 0.5*math.Cos(2*math.Pi*float64(k)/8)
+		naive[k] = x * complex(w, 0)
+	}
+	naiveDFT(naive, -1.0)
+
+	HannCmplx8(xi)
+	DftCmplx8(xi, xi)
+	if err := dftError(xi, naive); err > 1e-13 {
+		t.Fatalf("HannCmplx8: error %g", err)
+	}
+
+	HannFloat8(ri, ii)
+	DftFloat8(ri, ii, ri, ii)
+	for k := range xi {
+		xi[k] = complex(ri[k], ii[k])
+	}
+	if err := dftError(xi, naive); err > 1e-13 {
+		t.Fatalf("HannFloat8: error %g", err)
+	}
+
+	HammingCmplx8(xi)
+	BlackmanCmplx8(xi)
+}
+`)
+}
@@ -0,0 +1,206 @@
+package genfft
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// genWrappers renders any convenience functions enabled for a transform.
+func (p Program) genWrappers(f *jen.File, name string) error {
+	if p.Options.Into && !p.Options.Generic {
+		p.genInto(f, name)
+	}
+	if p.Options.Residual && !p.Options.Generic {
+		p.genResidual(f, name)
+	}
+	if p.Options.PSD && !p.Options.Generic {
+		if err := p.genPSD(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Magnitude && !p.Options.Generic {
+		if err := p.genMagnitude(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Halfcomplex && !p.Options.Generic {
+		if err := p.genHalfcomplex(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.CrossSpectrum && !p.Options.Generic {
+		if err := p.genCrossSpectrum(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.ConvStep && !p.Options.Generic {
+		if err := p.genConvStep(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Pad && !p.Options.Generic {
+		if err := p.genPad(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Batch && !p.Options.Generic {
+		if err := p.genBatch(f, name, false); err != nil {
+			return err
+		}
+	}
+	if p.Options.BatchContext && !p.Options.Generic {
+		if err := p.genBatch(f, name, true); err != nil {
+			return err
+		}
+	}
+	if p.Options.Stream && !p.Options.Generic {
+		p.genStream(f, name)
+	}
+	if p.Options.Indexed && !p.Options.Generic {
+		if err := p.genIndexed(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Ring && !p.Options.Generic {
+		if err := p.genRing(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Direction && !p.Options.Generic {
+		if err := p.genDirection(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Scaled && !p.Options.Generic {
+		if err := p.genScaled(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Scratch && !p.Options.Generic {
+		if err := p.genScratch(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Columns && !p.Options.Generic {
+		if err := p.genColumns(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Accessor && !p.Options.Generic {
+		if err := p.genAccessor(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Plan && !p.Options.Generic {
+		if err := p.genPlan(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Transformer && !p.Options.Generic {
+		if err := p.genTransformer(f, name); err != nil {
+			return err
+		}
+	}
+	if p.Options.Frame {
+		if err := p.genFrameMethod(f, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// passThrough returns the parameters wrappers expose beyond their slices, and
+// the arguments they pass to the transform after its slices. Wrappers pass
+// through the strides of strided schedules, write outputs from the start of
+// their slices, and pass through the sign of transforms whose direction is
+// chosen at runtime.
+func (p Program) passThrough() (params, args []jen.Code) {
+	if strides := p.Strides(); len(strides) > 0 {
+		params = append(params, strideParams(strides))
+		for _, stride := range strides {
+			args = append(args, jen.Id(stride))
+		}
+	}
+	if p.Options.OutputBase {
+		args = append(args, jen.Lit(0))
+	}
+	if p.Options.RuntimeSign {
+		params = append(params, jen.Id("sign").Int())
+		args = append(args, jen.Id("sign"))
+	}
+	if p.Options.SignMultiplier {
+		params = append(params, jen.Id("sign").Float64())
+		args = append(args, jen.Id("sign"))
+	}
+	return params, args
+}
+
+// genInto renders a wrapper computing a transform into caller-provided output
+// buffers, which are returned resliced to the transform length.
+func (p Program) genInto(f *jen.File, name string) {
+	n := p.TransformLength()
+	into := name + "Into"
+	extraParams, extraArgs := p.passThrough()
+
+	var (
+		params  []jen.Code
+		outputs []string
+		inputs  []string
+		results []jen.Code
+	)
+	if p.Float() {
+		inputs, outputs = []string{"ri", "ii"}, []string{"ro", "io"}
+		params = []jen.Code{jen.List(jen.Id("ri"), jen.Id("ii"), jen.Id("ro"), jen.Id("io")).Index().Float64()}
+		results = []jen.Code{jen.Index().Float64(), jen.Index().Float64()}
+	} else {
+		inputs, outputs = []string{"xi"}, []string{"out"}
+		params = []jen.Code{jen.List(jen.Id("xi"), jen.Id("out")).Index().Complex128()}
+		results = []jen.Code{jen.Index().Complex128()}
+	}
+	params = append(params, extraParams...)
+
+	// Failures return nil outputs.
+	var nils []jen.Code
+	for range outputs {
+		nils = append(nils, jen.Nil())
+	}
+
+	var args, returns []jen.Code
+	for _, in := range inputs {
+		args = append(args, jen.Id(in))
+	}
+	for _, out := range outputs {
+		args = append(args, jen.Id(out))
+		returns = append(returns, jen.Id(out))
+	}
+	args = append(args, extraArgs...)
+
+	f.Line()
+	f.Comment(fmt.Sprintf(
+		"%s computes %s into %s, which must have capacity for at least\n%d elements, and returns %s resliced to length %d.",
+		into, name, joinNames(outputs), n, joinNames(outputs), n,
+	))
+	f.Func().Id(into).Params(params...).Add(p.wrapperResults(results...)).BlockFunc(func(g *jen.Group) {
+		for _, out := range outputs {
+			g.If(jen.Cap(jen.Id(out)).Op("<").Lit(n)).Block(
+				p.genWrapperFailure(nils, fmt.Sprintf("dft: %s: %s has capacity %%d, need %d", into, out, n), jen.Cap(jen.Id(out))),
+			)
+			g.Id(out).Op("=").Id(out).Index(jen.Empty(), jen.Lit(n))
+			g.Line()
+		}
+
+		g.Add(p.callTransform(name, nils, args...))
+		g.Add(p.wrapperReturn(returns...))
+	})
+}
+
+// joinNames joins identifiers for use in a doc comment.
+func joinNames(names []string) string {
+	if len(names) > 1 {
+		return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+	}
+	return names[0]
+}
@@ -0,0 +1,61 @@
+package genfft
+
+import (
+	"testing"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// generateNaive generates a transform from a naive schedule of size n.
+func generateNaive(t *testing.T, n int, dft Dft) *jen.File {
+	t.Helper()
+	alst, cout := naiveSchedule(n, dft.Func[3] == 'F')
+	dft.Prefix = writeSchedule(t, t.TempDir(), dft.Func, alst, cout)
+	return mustGenerate(t, dft)
+}
+
+func TestInto(t *testing.T) {
+	files := map[string]*jen.File{
+		"cmplx_8.go": generateNaive(t, 8, Dft{Func: "DftCmplx8", Options: Options{Into: true}}),
+		"float_8.go": generateNaive(t, 8, Dft{Func: "DftFloat8", Options: Options{Into: true}}),
+	}
+
+	goTest(t, files, `package dft
+
+import "testing"
+
+func TestInto(t *testing.T) {
+	xi := randCmplx(8)
+	buf := make([]complex128, 3, 16)
+
+	var out []complex128
+	allocs := testing.AllocsPerRun(10, func() {
+		out = DftCmplx8Into(xi, buf)
+	})
+	if allocs != 0 {
+		t.Errorf("DftCmplx8Into allocated %v times", allocs)
+	}
+	if len(out) != 8 || &out[0] != &buf[:1][0] {
+		t.Errorf("DftCmplx8Into didn't reuse the buffer")
+	}
+
+	naiveDFT(xi, -1.0)
+	if err := dftError(out, xi); err > 1e-14 {
+		t.Errorf("DftCmplx8Into: error %g", err)
+	}
+
+	ri, ii := make([]float64, 8), make([]float64, 8)
+	ro, io := DftFloat8Into(ri, ii, make([]float64, 0, 8), make([]float64, 0, 8))
+	if len(ro) != 8 || len(io) != 8 {
+		t.Errorf("DftFloat8Into returned lengths %d and %d", len(ro), len(io))
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("DftCmplx8Into didn't panic on a short buffer")
+		}
+	}()
+	DftCmplx8Into(xi, make([]complex128, 7))
+}
+`)
+}